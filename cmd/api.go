@@ -0,0 +1,569 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+// issueAPI implements the operations the MCP and HTTP servers offer, with
+// arguments and results shaped for JSON
+type issueAPI struct {
+	repo *pkg.Repository
+	// branch and author supply the git branch for provisional IDs and the
+	// default comment author
+	branch func() string
+	author func() string
+}
+
+func newIssueAPI(repo *pkg.Repository) *issueAPI {
+	return &issueAPI{
+		repo:   repo,
+		branch: currentBranch,
+		author: func() string {
+			if name := gitUserName(); name != "" {
+				return name
+			}
+			return os.Getenv("USER")
+		},
+	}
+}
+
+// decodeArgs decodes JSON arguments, rejecting unknown keys
+func decodeArgs(args []byte, v interface{}) error {
+	if len(bytes.TrimSpace(args)) == 0 || string(args) == "null" {
+		args = []byte("{}")
+	}
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+// issueSummary is the short form of an issue in results that list issues
+type issueSummary struct {
+	ID        string   `json:"id"`
+	Title     string   `json:"title"`
+	Status    string   `json:"status"`
+	Closed    bool     `json:"closed"`
+	Assignee  string   `json:"assignee,omitempty"`
+	Labels    []string `json:"labels,omitempty"`
+	Priority  string   `json:"priority,omitempty"`
+	Due       string   `json:"due,omitempty"`
+	Milestone string   `json:"milestone,omitempty"`
+	Parent    string   `json:"parent,omitempty"`
+	Updated   string   `json:"updated"`
+	Score     float64  `json:"score,omitempty"`
+}
+
+func newIssueSummary(item issueWithStatus) issueSummary {
+	due, _ := item.issue.Field(pkg.DueField)
+	return issueSummary{
+		ID:        item.issue.ID,
+		Title:     item.issue.Title,
+		Status:    item.status,
+		Closed:    item.dir == pkg.ClosedDir,
+		Assignee:  item.issue.Assignee,
+		Labels:    item.issue.Labels,
+		Priority:  item.issue.Priority(),
+		Due:       pkg.FormatFieldValue(due),
+		Milestone: item.issue.Milestone(),
+		Parent:    item.issue.Parent,
+		Updated:   item.issue.Updated.Format(time.RFC3339),
+	}
+}
+
+type issueList struct {
+	Issues []issueSummary `json:"issues"`
+	Total  int            `json:"total"`
+}
+
+// issueRecord is the full form of an issue, with the version of the file
+// it was read from
+type issueRecord struct {
+	pkg.IssueRecord
+	version string
+}
+
+type listArgs struct {
+	Query  string `json:"query"`
+	Status string `json:"status"`
+	Sort   string `json:"sort"`
+	Limit  *int   `json:"limit"`
+	Offset int    `json:"offset"`
+}
+
+func (a *issueAPI) listIssues(args listArgs) (issueList, error) {
+	limit := 50
+	if args.Limit != nil {
+		limit = *args.Limit
+	}
+	if limit < 0 || args.Offset < 0 {
+		return issueList{}, fmt.Errorf("limit and offset can't be negative")
+	}
+
+	cfg, err := a.repo.LoadConfig()
+	if err != nil {
+		return issueList{}, err
+	}
+	keys, err := parseSortKeys(args.Sort, cfg)
+	if err != nil {
+		return issueList{}, err
+	}
+	if keys == nil {
+		keys = []sortKey{{name: "id"}}
+	}
+
+	var query *pkg.Query
+	if args.Query != "" {
+		if query, err = pkg.ParseQuery(args.Query, cfg); err != nil {
+			return issueList{}, err
+		}
+	}
+
+	status := args.Status
+	if status == "" {
+		status = pkg.StateOpen
+		if query != nil && query.UsesField("status") {
+			status = "all"
+		}
+	}
+	items, err := a.issues(status, cfg)
+	if err != nil {
+		return issueList{}, err
+	}
+
+	var matched []issueWithStatus
+	for _, item := range items {
+		if query == nil || query.Match(item.issue, item.dir, item.status) {
+			matched = append(matched, item)
+		}
+	}
+	sortIssues(matched, keys, cfg)
+
+	list := issueList{Issues: []issueSummary{}, Total: len(matched)}
+	for _, item := range paginate(matched, args.Offset, limit) {
+		list.Issues = append(list.Issues, newIssueSummary(item))
+	}
+	return list, nil
+}
+
+// issues loads the issues with a status: open, closed, all or a workflow state
+func (a *issueAPI) issues(status string, cfg *pkg.Config) ([]issueWithStatus, error) {
+	dirs := []string{pkg.OpenDir, pkg.ClosedDir}
+	state := ""
+	if status != "all" {
+		var err error
+		if dirs, state, err = resolveStatusFilter(status, &cfg.Workflow); err != nil {
+			return nil, err
+		}
+	}
+
+	var items []issueWithStatus
+	for _, dir := range dirs {
+		issues, err := a.repo.ListIssues(dir)
+		if err != nil {
+			continue // The directory may not exist yet
+		}
+		for _, issue := range issues {
+			item := issueWithStatus{issue: issue, dir: dir, status: cfg.Workflow.StatusOf(issue, dir)}
+			if state == "" || item.status == state {
+				items = append(items, item)
+			}
+		}
+	}
+	return items, nil
+}
+
+// record returns the full machine-readable form of an issue
+func (a *issueAPI) record(issue *pkg.Issue, dir string) (issueRecord, error) {
+	cfg, err := a.repo.LoadConfig()
+	if err != nil {
+		return issueRecord{}, err
+	}
+	return issueRecord{
+		IssueRecord: pkg.NewIssueRecord(issue, dir, cfg.Workflow.StatusOf(issue, dir)),
+		version:     issue.Version,
+	}, nil
+}
+
+type idArgs struct {
+	ID string `json:"id"`
+}
+
+func (a *issueAPI) getIssue(args idArgs) (issueRecord, error) {
+	issue, dir, err := a.repo.LoadIssue(args.ID)
+	if err != nil {
+		return issueRecord{}, err
+	}
+	return a.record(issue, dir)
+}
+
+// parseFieldArgs converts the fields argument into values typed by the
+// schema; null values (which remove a field) are kept as nil
+func parseFieldArgs(cfg *pkg.Config, raw map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(raw))
+	for name, value := range raw {
+		field := cfg.Field(name)
+		if field == nil {
+			return nil, fmt.Errorf("field %q is not declared in %s", name, pkg.ConfigFile)
+		}
+		if value == nil {
+			values[name] = nil
+			continue
+		}
+
+		var text string
+		switch v := value.(type) {
+		case string:
+			text = v
+		case float64:
+			text = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			text = strconv.FormatBool(v)
+		case []interface{}:
+			items := make([]string, len(v))
+			for i, item := range v {
+				items[i] = fmt.Sprint(item)
+			}
+			text = strings.Join(items, ",")
+		default:
+			return nil, fmt.Errorf("field %q: unsupported value %v", name, value)
+		}
+
+		parsed, err := field.Parse(text)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = parsed
+	}
+	return values, nil
+}
+
+type createArgs struct {
+	Title     string                 `json:"title"`
+	Body      string                 `json:"body"`
+	Assignee  string                 `json:"assignee"`
+	Labels    []string               `json:"labels"`
+	Priority  string                 `json:"priority"`
+	Due       string                 `json:"due"`
+	Milestone string                 `json:"milestone"`
+	Parent    string                 `json:"parent"`
+	Fields    map[string]interface{} `json:"fields"`
+}
+
+func (a *issueAPI) createIssue(args createArgs) (issueRecord, error) {
+	cfg, err := a.repo.LoadConfig()
+	if err != nil {
+		return issueRecord{}, err
+	}
+	fields, err := parseFieldArgs(cfg, args.Fields)
+	if err != nil {
+		return issueRecord{}, err
+	}
+	for name, value := range fields {
+		if value == nil {
+			delete(fields, name)
+		}
+	}
+
+	issue, err := a.repo.CreateIssue(args.Title, a.branch(), pkg.IssueOptions{
+		Assignee:  args.Assignee,
+		Labels:    args.Labels,
+		Priority:  args.Priority,
+		Due:       args.Due,
+		Milestone: args.Milestone,
+		Parent:    args.Parent,
+		Body:      args.Body,
+		Fields:    fields,
+	})
+	if err != nil {
+		return issueRecord{}, err
+	}
+	return a.record(issue, pkg.OpenDir)
+}
+
+type updateArgs struct {
+	ID           string                 `json:"id"`
+	Title        *string                `json:"title"`
+	Body         *string                `json:"body"`
+	Assignee     *string                `json:"assignee"`
+	Labels       *[]string              `json:"labels"`
+	AddLabels    []string               `json:"add_labels"`
+	RemoveLabels []string               `json:"remove_labels"`
+	Priority     *string                `json:"priority"`
+	Due          *string                `json:"due"`
+	Milestone    *string                `json:"milestone"`
+	Parent       *string                `json:"parent"`
+	Status       *string                `json:"status"`
+	Fields       map[string]interface{} `json:"fields"`
+	// Force and Strict apply to moving the issue to a closed state, as they
+	// do to closing it
+	Force  bool `json:"force"`
+	Strict bool `json:"strict"`
+	// version is the issue version the change is based on, "" for any
+	version string
+}
+
+func (a *issueAPI) updateIssue(args updateArgs) (issueRecord, error) {
+	cfg, err := a.repo.LoadConfig()
+	if err != nil {
+		return issueRecord{}, err
+	}
+	fields, err := parseFieldArgs(cfg, args.Fields)
+	if err != nil {
+		return issueRecord{}, err
+	}
+
+	issue, dir, err := a.repo.UpdateIssue(args.ID, pkg.IssueUpdate{
+		Title:        args.Title,
+		Body:         args.Body,
+		Assignee:     args.Assignee,
+		Labels:       args.Labels,
+		AddLabels:    args.AddLabels,
+		RemoveLabels: args.RemoveLabels,
+		Priority:     args.Priority,
+		Due:          args.Due,
+		Milestone:    args.Milestone,
+		Parent:       args.Parent,
+		Status:       args.Status,
+		Close:        pkg.CloseOptions{Force: args.Force, Strict: args.Strict},
+		Fields:       fields,
+		IfVersion:    args.version,
+	})
+	if err != nil {
+		return issueRecord{}, closeRefusal(err)
+	}
+	return a.record(issue, dir)
+}
+
+type closeArgs struct {
+	ID     string `json:"id"`
+	Force  bool   `json:"force"`
+	Strict bool   `json:"strict"`
+	// version is the issue version the change is based on, "" for any
+	version string
+}
+
+type closeResult struct {
+	Issue    issueRecord `json:"issue"`
+	Warnings []string    `json:"warnings"`
+}
+
+// closeIssue closes an issue the way 'gi close' does, without prompting
+func (a *issueAPI) closeIssue(args closeArgs) (closeResult, error) {
+	issue, dir, err := a.repo.LoadIssue(args.ID)
+	if err != nil {
+		return closeResult{}, err
+	}
+	if dir == pkg.ClosedDir {
+		return closeResult{}, fmt.Errorf("issue #%s is already closed", issue.ID)
+	}
+
+	check, err := a.repo.CloseChecks(issue.ID)
+	if err != nil {
+		return closeResult{}, err
+	}
+	issue, err = a.repo.CloseIssue(issue.ID, args.version, pkg.CloseOptions{Force: args.Force, Strict: args.Strict})
+	if err != nil {
+		return closeResult{}, closeRefusal(err)
+	}
+	record, err := a.record(issue, pkg.ClosedDir)
+	if err != nil {
+		return closeResult{}, err
+	}
+	return closeResult{Issue: record, Warnings: closeWarnings(issue.ID, check)}, nil
+}
+
+// closeRefusal adds the argument that gets past a refusal to close an issue
+func closeRefusal(err error) error {
+	if errors.Is(err, pkg.ErrOpenChildren) {
+		return fmt.Errorf("%w (set force to close it anyway)", err)
+	}
+	return err
+}
+
+type searchArgs struct {
+	Query  string `json:"query"`
+	Status string `json:"status"`
+	Limit  *int   `json:"limit"`
+}
+
+func (a *issueAPI) searchIssues(args searchArgs) (issueList, error) {
+	limit := 20
+	if args.Limit != nil {
+		limit = *args.Limit
+	}
+	if strings.TrimSpace(args.Query) == "" {
+		return issueList{}, fmt.Errorf("search query cannot be empty")
+	}
+
+	cfg, err := a.repo.LoadConfig()
+	if err != nil {
+		return issueList{}, err
+	}
+	query, err := pkg.ParseSearch(args.Query, cfg)
+	if err != nil {
+		return issueList{}, err
+	}
+	dirs, state := []string{pkg.OpenDir, pkg.ClosedDir}, ""
+	if args.Status != "" {
+		if dirs, state, err = resolveStatusFilter(args.Status, &cfg.Workflow); err != nil {
+			return issueList{}, err
+		}
+	}
+
+	hits, err := a.repo.Search(query)
+	if err != nil {
+		return issueList{}, err
+	}
+	list := issueList{Issues: []issueSummary{}}
+	for _, hit := range hits {
+		if !containsKey(dirs, hit.Dir) || state != "" && hit.Status != state {
+			continue
+		}
+		list.Total++
+		if limit == 0 || len(list.Issues) < limit {
+			issue := newIssueSummary(issueWithStatus{issue: hit.Issue, dir: hit.Dir, status: hit.Status})
+			issue.Score = hit.Score
+			list.Issues = append(list.Issues, issue)
+		}
+	}
+	return list, nil
+}
+
+type commentArgs struct {
+	ID     string `json:"id"`
+	Text   string `json:"text"`
+	Author string `json:"author"`
+}
+
+type commentResult struct {
+	ID      string       `json:"id"`
+	Comment *pkg.Comment `json:"comment"`
+}
+
+func (a *issueAPI) comment(args commentArgs) (commentResult, error) {
+	author := args.Author
+	if author == "" {
+		author = a.author()
+	}
+	if author == "" {
+		return commentResult{}, fmt.Errorf("can't determine the comment author; set author")
+	}
+
+	issue, _, err := a.repo.LoadIssue(args.ID)
+	if err != nil {
+		return commentResult{}, err
+	}
+	comment, err := a.repo.AddComment(issue.ID, author, args.Text)
+	if err != nil {
+		return commentResult{}, err
+	}
+	return commentResult{ID: issue.ID, Comment: comment}, nil
+}
+
+type markdownArgs struct {
+	ID      string
+	Content string
+	// version is the issue version the change is based on, "" for any
+	version string
+}
+
+// issueMarkdown returns an issue's Markdown file with its version
+func (a *issueAPI) issueMarkdown(args idArgs) (string, string, error) {
+	issue, _, err := a.repo.LoadIssue(args.ID)
+	if err != nil {
+		return "", "", err
+	}
+	content, err := pkg.SerializeIssue(issue)
+	if err != nil {
+		return "", "", err
+	}
+	return content, issue.Version, nil
+}
+
+// replaceIssue replaces an issue's Markdown file, as 'gi edit' does
+func (a *issueAPI) replaceIssue(args markdownArgs) (issueRecord, error) {
+	issue, dir, err := a.repo.ReplaceIssue(args.ID, args.Content, args.version)
+	if err != nil {
+		return issueRecord{}, err
+	}
+	return a.record(issue, dir)
+}
+
+// stateInfo describes a workflow state and the states it may move to
+type stateInfo struct {
+	Name        string   `json:"name"`
+	Closed      bool     `json:"closed"`
+	Description string   `json:"description,omitempty"`
+	Transitions []string `json:"transitions"`
+}
+
+type fieldInfo struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Values      []string `json:"values,omitempty"`
+	Required    bool     `json:"required,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
+
+// configInfo is what clients need to know of config.yaml to offer choices
+type configInfo struct {
+	States     []stateInfo `json:"states"`
+	Priorities []string    `json:"priorities"`
+	Fields     []fieldInfo `json:"fields"`
+}
+
+func (a *issueAPI) config() (configInfo, error) {
+	cfg, err := a.repo.LoadConfig()
+	if err != nil {
+		return configInfo{}, err
+	}
+
+	info := configInfo{States: []stateInfo{}, Priorities: cfg.PriorityLevels(), Fields: []fieldInfo{}}
+	for _, name := range boardStates(&cfg.Workflow) {
+		state := cfg.Workflow.State(name)
+		transitions := cfg.Workflow.AllowedTransitions(name)
+		if transitions == nil {
+			transitions = []string{}
+		}
+		info.States = append(info.States, stateInfo{Name: name, Closed: state.Closed, Description: state.Description, Transitions: transitions})
+	}
+	for _, field := range cfg.Fields {
+		info.Fields = append(info.Fields, fieldInfo{
+			Name:        field.Name,
+			Type:        string(field.Type),
+			Values:      field.Values,
+			Required:    field.Required,
+			Description: field.Description,
+		})
+	}
+	return info, nil
+}
+
+// boardStates orders the workflow states the way work flows across a board:
+// open, the other open states, the closed states, then closed
+func boardStates(workflow *pkg.Workflow) []string {
+	states := []string{pkg.StateOpen}
+	for _, state := range workflow.States {
+		if !state.Closed {
+			states = append(states, state.Name)
+		}
+	}
+	for _, state := range workflow.States {
+		if state.Closed {
+			states = append(states, state.Name)
+		}
+	}
+	return append(states, pkg.StateClosed)
+}
@@ -0,0 +1,377 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	boardBy     string
+	boardAll    bool
+	boardQuery  string
+	boardSort   string
+	boardLimit  int
+	boardWidth  int
+	boardFormat string
+)
+
+// Ways of grouping the board into columns (--by)
+const (
+	boardByStatus   = "status"
+	boardByLabel    = "label"
+	boardByAssignee = "assignee"
+)
+
+const (
+	// boardMinCardWidth is the narrowest a card gets before the board wraps
+	// its columns onto several rows
+	boardMinCardWidth = 20
+	// boardDefaultWidth is used when the output isn't a terminal and $COLUMNS is unset
+	boardDefaultWidth = 120
+)
+
+var boardCmd = &cobra.Command{
+	Use:   "board",
+	Short: "Show issues as a kanban board",
+	Long: `Show issues as a kanban board with a column per workflow state, or per
+label or assignee with --by.
+
+Columns fill the terminal width and card titles are truncated to fit; when
+there are too many columns to fit, the board continues below. An issue with
+several labels appears in each of their columns. --format markdown prints the
+board as a Markdown table with full titles, for pasting into standup notes.
+
+By default only open issues are shown; --all adds closed issues and the
+closed columns.
+
+Examples:
+  gi board                           # Columns per status
+  gi board --all                     # Include closed issues
+  gi board --by assignee             # Who's working on what
+  gi board --by label -q 'milestone:v1.0'
+  gi board --sort priority --limit 10
+  gi board --format markdown         # For standup notes`,
+	Args: cobra.NoArgs,
+	RunE: runBoard,
+}
+
+func init() {
+	rootCmd.AddCommand(boardCmd)
+	boardCmd.Flags().StringVar(&boardBy, "by", boardByStatus, "Group columns by status, label or assignee")
+	boardCmd.Flags().BoolVarP(&boardAll, "all", "a", false, "Include closed issues")
+	boardCmd.Flags().StringVarP(&boardQuery, "query", "q", "", "Only show issues matching a query expression (see 'gi list --help')")
+	boardCmd.Flags().StringVar(&boardSort, "sort", "", "Sort the cards by comma-separated keys, each optionally :asc or :desc (as for 'gi list')")
+	boardCmd.Flags().IntVar(&boardLimit, "limit", 0, "Show at most this many cards per column")
+	boardCmd.Flags().IntVar(&boardWidth, "width", 0, "Board width in characters (default: the terminal width)")
+	boardCmd.Flags().StringVar(&boardFormat, "format", formatTable, "Output format: table or markdown")
+}
+
+// boardColumn is a column of the board and the issues in it
+type boardColumn struct {
+	name   string
+	closed bool // a closed workflow state, colored as such
+	items  []issueWithStatus
+}
+
+func runBoard(cmd *cobra.Command, args []string) error {
+	// Check if repository is initialized
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	if boardBy != boardByStatus && boardBy != boardByLabel && boardBy != boardByAssignee {
+		return fmt.Errorf("invalid --by: %s (must be one of: status, label, assignee)", boardBy)
+	}
+	if boardFormat != formatTable && boardFormat != formatMarkdown {
+		return fmt.Errorf("invalid format: %s (must be one of: %s, %s)", boardFormat, formatTable, formatMarkdown)
+	}
+	if boardLimit < 0 {
+		return fmt.Errorf("--limit can't be negative")
+	}
+
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	var queries []*pkg.Query
+	if boardQuery != "" {
+		q, err := pkg.ParseQuery(boardQuery, cfg)
+		if err != nil {
+			return err
+		}
+		queries = append(queries, q)
+	}
+
+	// Collect and filter the issues
+	withClosed := boardAll || queriesUseStatus(queries)
+	dirs := []string{pkg.OpenDir}
+	if withClosed {
+		dirs = append(dirs, pkg.ClosedDir)
+	}
+	var items []issueWithStatus
+	for _, item := range collectIssues(dirs, &cfg.Workflow) {
+		if matchesQueries(item, queries) {
+			items = append(items, item)
+		}
+	}
+
+	if boardSort != "" {
+		keys, err := parseSortKeys(boardSort, cfg)
+		if err != nil {
+			return err
+		}
+		sortIssues(items, keys, cfg)
+	}
+
+	columns := boardColumns(items, boardBy, &cfg.Workflow, withClosed)
+	if len(columns) == 0 {
+		fmt.Println("No issues found.")
+		return nil
+	}
+
+	if boardFormat == formatMarkdown {
+		return writeMarkdownBoard(os.Stdout, columns, boardBy, boardLimit)
+	}
+
+	width := boardWidth
+	if width <= 0 {
+		width = terminalWidth(os.Stdout)
+	}
+	if width <= 0 {
+		width, _ = strconv.Atoi(os.Getenv("COLUMNS"))
+	}
+	if width <= 0 {
+		width = boardDefaultWidth
+	}
+	writeBoard(os.Stdout, columns, boardBy, boardLimit, width)
+	return nil
+}
+
+// boardColumns groups issues into the columns of the board. Status boards
+// have a column per workflow state in the order work flows, leaving out the
+// closed states when closed issues weren't loaded; label and assignee boards
+// have a column per value in use, alphabetically, then one for issues
+// without any.
+func boardColumns(items []issueWithStatus, by string, workflow *pkg.Workflow, withClosed bool) []boardColumn {
+	var columns []boardColumn
+
+	if by == boardByStatus {
+		index := make(map[string]int)
+		for _, name := range boardStates(workflow) {
+			if closed := workflow.State(name).Closed; !closed || withClosed {
+				index[name] = len(columns)
+				columns = append(columns, boardColumn{name: name, closed: closed})
+			}
+		}
+		for _, item := range items {
+			if n, ok := index[item.status]; ok {
+				columns[n].items = append(columns[n].items, item)
+			}
+		}
+		return columns
+	}
+
+	none := "unassigned"
+	if by == boardByLabel {
+		none = "no label"
+	}
+	groups := make(map[string][]issueWithStatus)
+	for _, item := range items {
+		values := item.issue.Labels
+		if by == boardByAssignee {
+			values = nil
+			if item.issue.Assignee != "" {
+				values = []string{item.issue.Assignee}
+			}
+		}
+		if len(values) == 0 {
+			groups[none] = append(groups[none], item)
+		}
+		for _, value := range values {
+			groups[value] = append(groups[value], item)
+		}
+	}
+
+	var names []string
+	for name := range groups {
+		if name != none {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	if _, ok := groups[none]; ok {
+		names = append(names, none)
+	}
+	for _, name := range names {
+		columns = append(columns, boardColumn{name: name, items: groups[name]})
+	}
+	return columns
+}
+
+// boardCard is the text of an issue's card: its ID and title, and the
+// assignee unless the board is grouped by assignee
+func boardCard(item issueWithStatus, by string) string {
+	card := "#" + item.issue.ID + " " + item.issue.Title
+	if by != boardByAssignee && item.issue.Assignee != "" {
+		card += " @" + item.issue.Assignee
+	}
+	return card
+}
+
+// boardHeader is a column's heading: its name and how many issues are in it
+func boardHeader(column boardColumn) string {
+	return fmt.Sprintf("%s (%d)", column.name, len(column.items))
+}
+
+// boardCells returns the cards of a column, up to limit (0 for all) and a
+// "+N more" line for the rest, each made to fit in width cells when width
+// is positive
+func boardCells(column boardColumn, by string, limit, width int) []string {
+	items := column.items
+	more := 0
+	if limit > 0 && len(items) > limit {
+		items, more = items[:limit], len(items)-limit
+	}
+
+	cells := make([]string, 0, len(items)+1)
+	for _, item := range items {
+		card := boardCard(item, by)
+		if width > 0 && displayWidth(card) > width {
+			// Keep the ID and cut the title, dropping the assignee first
+			card = truncateWidth("#"+item.issue.ID+" "+item.issue.Title, width)
+		}
+		cells = append(cells, card)
+	}
+	if more > 0 {
+		cells = append(cells, fmt.Sprintf("+%d more", more))
+	}
+	return cells
+}
+
+// boardColumnWidths shares width cells between columns that need the given
+// widths: a column needing less than an even share gets what it needs and the
+// others split the rest evenly
+func boardColumnWidths(needed []int, width int) []int {
+	widths := make([]int, len(needed))
+	remaining := width
+	left := len(needed)
+	for changed := true; changed && left > 0; {
+		changed = false
+		share := remaining / left
+		for i, need := range needed {
+			if widths[i] == 0 && need <= share {
+				widths[i] = max(need, 1)
+				remaining -= widths[i]
+				left--
+				changed = true
+			}
+		}
+	}
+	for i := range widths {
+		if widths[i] == 0 {
+			widths[i] = max(remaining/left, 1)
+		}
+	}
+	return widths
+}
+
+// writeBoard draws the board as a table no wider than width, continuing
+// below with the remaining columns when they don't all fit side by side
+func writeBoard(w io.Writer, columns []boardColumn, by string, limit, width int) {
+	// Each column takes its card width plus 3 for its border and padding,
+	// and the table one more for its right border
+	perRow := max((width-1)/(boardMinCardWidth+3), 1)
+
+	for start := 0; start < len(columns); start += perRow {
+		if start > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		group := columns[start:min(start+perRow, len(columns))]
+
+		needed := make([]int, len(group))
+		for i, column := range group {
+			needed[i] = displayWidth(boardHeader(column))
+			for _, card := range boardCells(column, by, limit, 0) {
+				needed[i] = max(needed[i], displayWidth(card))
+			}
+		}
+		widths := boardColumnWidths(needed, width-1-3*len(group))
+
+		table := tablewriter.NewWriter(w)
+		table.SetAutoFormatHeaders(false)
+		table.SetAutoWrapText(false)
+		table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+		table.SetAlignment(tablewriter.ALIGN_LEFT)
+
+		headers := make([]string, len(group))
+		headerColors := make([]tablewriter.Colors, len(group))
+		cells := make([][]string, len(group))
+		rows := 0
+		for i, column := range group {
+			headers[i] = truncateWidth(boardHeader(column), widths[i])
+			headerColors[i] = tablewriter.Colors{tablewriter.Bold}
+			if by == boardByStatus {
+				headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgGreenColor}
+				if column.closed {
+					headerColors[i] = tablewriter.Colors{tablewriter.Bold, tablewriter.FgRedColor}
+				}
+			}
+			cells[i] = boardCells(column, by, limit, widths[i])
+			rows = max(rows, len(cells[i]))
+		}
+		table.SetHeader(headers)
+		if !color.NoColor {
+			table.SetHeaderColor(headerColors...)
+		}
+
+		for r := 0; r < rows; r++ {
+			row := make([]string, len(group))
+			for i := range group {
+				if r < len(cells[i]) {
+					row[i] = cells[i][r]
+				}
+			}
+			table.Append(row)
+		}
+		table.Render()
+	}
+}
+
+// writeMarkdownBoard writes the board as a Markdown table with a column per
+// board column and full card titles
+func writeMarkdownBoard(w io.Writer, columns []boardColumn, by string, limit int) error {
+	var b strings.Builder
+	cells := make([][]string, len(columns))
+	headers := make([]string, len(columns))
+	rows := 0
+	for i, column := range columns {
+		headers[i] = markdownCell(boardHeader(column))
+		cells[i] = boardCells(column, by, limit, 0)
+		rows = max(rows, len(cells[i]))
+	}
+
+	b.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for r := 0; r < rows; r++ {
+		row := make([]string, len(columns))
+		for i := range columns {
+			if r < len(cells[i]) {
+				row[i] = markdownCell(cells[i][r])
+			}
+		}
+		b.WriteString("| " + strings.Join(row, " | ") + " |\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+// columnSummary lists each column as "name:ID,ID"
+func columnSummary(columns []boardColumn) []string {
+	var summary []string
+	for _, column := range columns {
+		ids := make([]string, len(column.items))
+		for i, item := range column.items {
+			ids[i] = item.issue.ID
+		}
+		summary = append(summary, column.name+":"+strings.Join(ids, ","))
+	}
+	return summary
+}
+
+func TestBoardColumns(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+	writeWorkflowConfig(t)
+
+	createLabels = []string{"bug", "backend"}
+	createAssignee = "mina"
+	if err := runCreate(nil, []string{"Fix Redis connection timeout"}); err != nil {
+		t.Fatal(err)
+	}
+	createLabels = []string{"docs"}
+	createAssignee = ""
+	if err := runCreate(nil, []string{"Update documentation"}); err != nil {
+		t.Fatal(err)
+	}
+	createLabels = []string{}
+	if err := runCreate(nil, []string{"Add user authentication"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runStatus(nil, []string{"001", "in-progress"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, state := range []string{"in-progress", "done"} {
+		if err := runStatus(nil, []string{"002", state}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := collectIssues([]string{pkg.OpenDir}, &cfg.Workflow)
+	all := collectIssues([]string{pkg.OpenDir, pkg.ClosedDir}, &cfg.Workflow)
+
+	tests := []struct {
+		name       string
+		items      []issueWithStatus
+		by         string
+		withClosed bool
+		want       []string
+	}{
+		{"open states", open, boardByStatus, false, []string{"open:003", "in-progress:001"}},
+		{"all states", all, boardByStatus, true, []string{"open:003", "in-progress:001", "done:002", "closed:"}},
+		{"labels", all, boardByLabel, true, []string{"backend:001", "bug:001", "docs:002", "no label:003"}},
+		{"assignees", open, boardByAssignee, false, []string{"mina:001", "unassigned:003"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := columnSummary(boardColumns(tt.items, tt.by, &cfg.Workflow, tt.withClosed)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("boardColumns() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// boardItem makes an issue for rendering tests
+func boardItem(id, title, assignee string) issueWithStatus {
+	return issueWithStatus{issue: &pkg.Issue{ID: id, Title: title, Assignee: assignee}, dir: pkg.OpenDir, status: pkg.StateOpen}
+}
+
+func TestWriteBoard(t *testing.T) {
+	columns := []boardColumn{
+		{name: "open", items: []issueWithStatus{
+			boardItem("001", "Investigate flaky login tests on the CI runners", "mina"),
+			boardItem("002", "레디스 연결 오류 수정 및 재시도 로직 추가", ""),
+			boardItem("003", "Add user authentication", ""),
+		}},
+		{name: "closed", closed: true, items: []issueWithStatus{boardItem("004", "Docs", "")}},
+	}
+
+	var out bytes.Buffer
+	writeBoard(&out, columns, boardByStatus, 0, 60)
+	board := out.String()
+	for _, line := range strings.Split(strings.TrimSpace(board), "\n") {
+		if displayWidth(line) > 60 {
+			t.Errorf("line is %d cells wide, more than 60: %q", displayWidth(line), line)
+		}
+	}
+	for _, want := range []string{"open (3)", "closed (1)", "#001 Investigate flaky", "…", "#004 Docs"} {
+		if !strings.Contains(board, want) {
+			t.Errorf("board doesn't contain %q:\n%s", want, board)
+		}
+	}
+	if strings.Contains(board, "@mina") {
+		t.Errorf("a truncated card should drop the assignee before the title:\n%s", board)
+	}
+
+	// Columns that don't fit side by side continue below, and --limit folds
+	// the rest of a column into a count
+	out.Reset()
+	writeBoard(&out, columns, boardByStatus, 2, 30)
+	board = out.String()
+	if strings.Count(board, "\n\n") != 1 || strings.Index(board, "closed (1)") < strings.Index(board, "open (3)") {
+		t.Errorf("a 30-wide board should show the columns one below the other:\n%s", board)
+	}
+	if !strings.Contains(board, "+1 more") || strings.Contains(board, "#003") {
+		t.Errorf("--limit 2 should hide #003 behind +1 more:\n%s", board)
+	}
+}
+
+func TestBoardColumnWidths(t *testing.T) {
+	tests := []struct {
+		needed []int
+		width  int
+		want   []int
+	}{
+		{[]int{10, 10}, 60, []int{10, 10}},
+		{[]int{50, 10}, 40, []int{30, 10}},
+		{[]int{50, 50, 5}, 45, []int{20, 20, 5}},
+	}
+	for _, tt := range tests {
+		if got := boardColumnWidths(tt.needed, tt.width); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("boardColumnWidths(%v, %d) = %v, want %v", tt.needed, tt.width, got, tt.want)
+		}
+	}
+}
+
+func TestWriteMarkdownBoard(t *testing.T) {
+	columns := []boardColumn{
+		{name: "mina", items: []issueWithStatus{boardItem("001", "Fix A | B parsing", "mina"), boardItem("002", "Add login page", "mina")}},
+		{name: "unassigned", items: []issueWithStatus{boardItem("003", "Update documentation", "")}},
+	}
+
+	var out bytes.Buffer
+	if err := writeMarkdownBoard(&out, columns, boardByAssignee, 0); err != nil {
+		t.Fatal(err)
+	}
+	want := `| mina (2) | unassigned (1) |
+| --- | --- |
+| #001 Fix A \| B parsing | #003 Update documentation |
+| #002 Add login page |  |
+`
+	if out.String() != want {
+		t.Errorf("writeMarkdownBoard() =\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestRunBoardFlags(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	boardBy = "milestone"
+	if err := runBoard(nil, nil); err == nil || !strings.Contains(err.Error(), "invalid --by") {
+		t.Errorf("runBoard(--by milestone) error = %v", err)
+	}
+	boardBy = boardByStatus
+	boardFormat = formatJSON
+	if err := runBoard(nil, nil); err == nil || !strings.Contains(err.Error(), "invalid format") {
+		t.Errorf("runBoard(--format json) error = %v", err)
+	}
+}
@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var (
+	checkUncheck bool
+	checkCommit  bool
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check <issue-id> [item...]",
+	Short: "Tick task list items of an issue",
+	Long: `Tick "- [ ]" task list items in an issue's description without opening an editor.
+
+Items are numbered from 1 in the order they appear. Without item numbers, the
+task list is printed with its numbers.
+
+Examples:
+  gi check 001            # Show the numbered task list
+  gi check 001 2          # Tick the second item
+  gi check 001 1 3        # Tick several items
+  gi check 001 2 --uncheck`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runCheck,
+}
+
+func init() {
+	rootCmd.AddCommand(checkCmd)
+	checkCmd.Flags().BoolVar(&checkUncheck, "uncheck", false, "Untick the items instead")
+	checkCmd.Flags().BoolVarP(&checkCommit, "commit", "c", false, "Auto-commit the change to git")
+}
+
+func runCheck(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	issue, _, err := pkg.LoadIssue(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load issue: %w", err)
+	}
+
+	// Without item numbers, list the tasks
+	if len(args) == 1 {
+		tasks := issue.Tasks()
+		if len(tasks) == 0 {
+			fmt.Printf("Issue #%s has no task list items.\n", issue.ID)
+			return nil
+		}
+		printTasks(tasks)
+		fmt.Printf("\n%s checked\n", issue.TaskProgress())
+		return nil
+	}
+
+	// Validate every number before changing anything
+	total := len(issue.Tasks())
+	items := make([]int, 0, len(args)-1)
+	for _, arg := range args[1:] {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid task number: %s", arg)
+		}
+		if n > total {
+			return fmt.Errorf("issue #%s has no task %d (it has %d)", issue.ID, n, total)
+		}
+		items = append(items, n)
+	}
+
+	verb := "Checked"
+	if checkUncheck {
+		verb = "Unchecked"
+	}
+	for _, n := range items {
+		task, err := pkg.CheckTask(issue.ID, n, !checkUncheck)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✓ %s %d. %s\n", verb, n, task.Text)
+	}
+
+	if checkCommit {
+		numbers := strings.Join(args[1:], ", ")
+		if err := gitCommitChanges(fmt.Sprintf("%s task %s of issue #%s", verb, numbers, issue.ID)); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
+
+// printTasks prints a numbered task list, grouped by section
+func printTasks(tasks []pkg.Task) {
+	bold := color.New(color.Bold).SprintFunc()
+	green := color.New(color.FgGreen).SprintFunc()
+
+	section := ""
+	for i, task := range tasks {
+		if task.Section != section || i == 0 && task.Section != "" {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Println(bold(task.Section))
+			section = task.Section
+		}
+		mark := "[ ]"
+		if task.Checked {
+			mark = green("[x]")
+		}
+		fmt.Printf("%3d. %s %s\n", i+1, mark, task.Text)
+	}
+}
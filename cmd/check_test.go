@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestRunCheck(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Checklist"}); err != nil {
+		t.Fatal(err)
+	}
+	setCriteria(t, "001", "Shipped", "Documented")
+
+	if err := runCheck(nil, []string{"001"}); err != nil {
+		t.Errorf("runCheck() listing failed: %v", err)
+	}
+	if err := runCheck(nil, []string{"001", "1", "2"}); err != nil {
+		t.Fatalf("runCheck() failed: %v", err)
+	}
+	issue, _, _ := pkg.LoadIssue("001")
+	if p := issue.TaskProgress(); p.Checked != 2 || p.Total != 2 {
+		t.Errorf("TaskProgress() = %+v, want 2/2", p)
+	}
+
+	checkUncheck = true
+	if err := runCheck(nil, []string{"001", "2"}); err != nil {
+		t.Fatalf("runCheck(--uncheck) failed: %v", err)
+	}
+	checkUncheck = false
+
+	for _, args := range [][]string{{"001", "3"}, {"001", "x"}, {"001", "1", "0"}} {
+		if err := runCheck(nil, args); err == nil {
+			t.Errorf("runCheck(%v) should fail", args)
+		}
+	}
+
+	// --strict refuses while a criterion is unchecked
+	closeStrict = true
+	if err := runClose(nil, []string{"001"}); err == nil {
+		t.Fatal("runClose(--strict) with unchecked criteria should fail")
+	}
+	if err := runCheck(nil, []string{"001", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runClose(nil, []string{"001"}); err != nil {
+		t.Errorf("runClose(--strict) with all criteria checked failed: %v", err)
+	}
+}
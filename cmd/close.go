@@ -1,46 +1,72 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
-var closeCommit bool
+var (
+	closeCommit bool
+	closeStrict bool
+	closeForce  bool
+)
 
 var closeCmd = &cobra.Command{
 	Use:   "close <issue-id>",
 	Short: "Close an issue",
-	Long:  `Close an issue by moving it from .issues/open/ to .issues/closed/`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runClose,
+	Long: `Close an issue by moving it from .issues/open/ to .issues/closed/
+
+Closing an issue that is still blocked by open issues (see 'gi link'), or whose
+success criteria ("- [ ]" items under "## Success Criteria", or anywhere when
+there's no such section) aren't all checked, prints a warning; with --strict
+it fails instead.
+
+Closing a parent issue whose children are still open asks for confirmation,
+or fails when standard input isn't a terminal; --force skips the check.
+
+Closing is a move to the built-in closed state, so when config.yaml declares
+workflow transitions the issue's state must allow it (see 'gi status').`,
+	Args: cobra.ExactArgs(1),
+	RunE: runClose,
 }
 
 func init() {
 	rootCmd.AddCommand(closeCmd)
 	closeCmd.Flags().BoolVarP(&closeCommit, "commit", "c", false, "Auto-commit the change to git")
+	closeCmd.Flags().BoolVar(&closeStrict, "strict", false, "Refuse to close an issue with open blockers or unchecked success criteria")
+	closeCmd.Flags().BoolVarP(&closeForce, "force", "f", false, "Close a parent issue even if it has open children")
 }
 
 func runClose(cmd *cobra.Command, args []string) error {
 	issueID := args[0]
 
 	// Load the issue to check its status
-	_, currentDir, err := pkg.LoadIssue(issueID)
+	issue, currentDir, err := pkg.LoadIssue(issueID)
 	if err != nil {
 		return fmt.Errorf("failed to load issue: %w", err)
 	}
+	issueID = issue.ID // expand a short ID prefix
 
 	// Check if issue is already closed
 	if currentDir == pkg.ClosedDir {
 		return fmt.Errorf("issue #%s is already closed", issueID)
 	}
 
-	// Move issue from open to closed (timestamp update included)
-	if err := pkg.MoveIssue(issueID, pkg.OpenDir, pkg.ClosedDir); err != nil {
-		return fmt.Errorf("failed to move issue: %w", err)
+	// Check what's left undone on the issue, then close it
+	opts, err := prepareClose(issueID, closeStrict, closeForce)
+	if err != nil {
+		return err
+	}
+	if _, err := pkg.CloseIssue(issueID, "", opts); err != nil {
+		return closeError(err, issueID)
 	}
 
 	fmt.Printf("✓ Closed issue #%s\n", issueID)
@@ -56,15 +82,111 @@ func runClose(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-// isGitRepo checks if the current directory is a git repository
+// prepareClose checks what's left undone on an open issue about to be closed,
+// as 'gi close' and 'gi status' do, and returns the options to close it with.
+// With strict, open blockers and unchecked success criteria are refused;
+// otherwise they're warned about. Open children are confirmed unless force
+// is set.
+func prepareClose(issueID string, strict, force bool) (pkg.CloseOptions, error) {
+	check, err := pkg.CloseChecks(issueID)
+	if err != nil {
+		return pkg.CloseOptions{}, err
+	}
+
+	// Open children are confirmed below rather than refused
+	opts := pkg.CloseOptions{Strict: strict, Force: true}
+	if err := check.Check(issueID, opts); err != nil {
+		return opts, closeError(err, issueID)
+	}
+	for _, warning := range closeWarnings(issueID, check) {
+		_, _ = color.New(color.FgYellow).Printf("! %s\n", warning)
+	}
+	if !force {
+		if err := confirmOpenChildren(issueID, check.OpenChildren); err != nil {
+			return opts, err
+		}
+	}
+	return opts, nil
+}
+
+// closeWarnings describes the open blockers and unchecked success criteria of
+// an issue about to be closed
+func closeWarnings(issueID string, check *pkg.CloseCheck) []string {
+	warnings := []string{}
+	if len(check.Blockers) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Issue #%s is still blocked by open issue(s) %s", issueID, formatIssueRefs(check.Blockers)))
+	}
+	if len(check.Unchecked) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Issue #%s has %d unchecked success criteria", issueID, len(check.Unchecked)))
+	}
+	return warnings
+}
+
+// closeError adds the way past a refusal to close issue id to err
+func closeError(err error, issueID string) error {
+	switch {
+	case errors.Is(err, pkg.ErrOpenChildren):
+		return fmt.Errorf("%w (use --force to close it anyway)", err)
+	case errors.Is(err, pkg.ErrUncheckedCriteria):
+		return fmt.Errorf("%w (tick them with 'gi check %s')", err, issueID)
+	}
+	return err
+}
+
+// confirmOpenChildren asks before closing a parent whose children are still
+// open, and refuses when there's no terminal to ask on
+func confirmOpenChildren(issueID string, open []string) error {
+	if len(open) == 0 {
+		return nil
+	}
+
+	if !stdinIsTerminal() {
+		return fmt.Errorf("issue #%s has %d open child issue(s): %s (use --force to close it anyway)", issueID, len(open), formatIssueRefs(open))
+	}
+
+	fmt.Printf("Issue #%s has %d open child issue(s): %s. Close it anyway? [y/N] ", issueID, len(open), formatIssueRefs(open))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if answer = strings.ToLower(strings.TrimSpace(answer)); answer != "y" && answer != "yes" {
+		return fmt.Errorf("aborted closing issue #%s", issueID)
+	}
+	return nil
+}
+
+// isGitRepo checks if .issues/ is inside a git repository
 func isGitRepo() bool {
 	cmd := exec.Command("git", "rev-parse", "--git-dir")
+	cmd.Dir = pkg.GetIssuesPath()
 	cmd.Stderr = nil
 	cmd.Stdout = nil
 	return cmd.Run() == nil
 }
 
-// gitCommitChanges stages and commits changes to .issues/
+// currentBranch returns the git branch checked out in the repository holding
+// .issues/, or "" when it can't be determined
+func currentBranch() string {
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
+	cmd.Dir = pkg.GetIssuesPath()
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitUserName returns git's user.name for the repository holding .issues/,
+// or "" when it isn't set
+func gitUserName() string {
+	cmd := exec.Command("git", "config", "user.name")
+	cmd.Dir = pkg.GetIssuesPath()
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// gitCommitChanges stages and commits changes to .issues/ in the repository
+// holding it, which needn't be the one in the working directory (--repo, GI_DIR)
 func gitCommitChanges(message string) error {
 	// Check if we're in a git repository
 	if !isGitRepo() {
@@ -72,7 +194,8 @@ func gitCommitChanges(message string) error {
 	}
 
 	// Stage changes
-	stageCmd := exec.Command("git", "add", pkg.GetIssuesPath())
+	stageCmd := exec.Command("git", "add", ".")
+	stageCmd.Dir = pkg.GetIssuesPath()
 	stageCmd.Stdout = os.Stdout
 	stageCmd.Stderr = os.Stderr
 	if err := stageCmd.Run(); err != nil {
@@ -81,6 +204,7 @@ func gitCommitChanges(message string) error {
 
 	// Commit changes
 	commitCmd := exec.Command("git", "commit", "-m", message)
+	commitCmd.Dir = pkg.GetIssuesPath()
 	commitCmd.Stdout = os.Stdout
 	commitCmd.Stderr = os.Stderr
 	if err := commitCmd.Run(); err != nil {
@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"os"
+	"os/exec"
 	"strings"
 	"testing"
 
@@ -119,6 +120,47 @@ func TestRunCloseCommitCreatesGitCommit(t *testing.T) {
 	}
 }
 
+func TestRunCloseCommitFromOutsideRepo(t *testing.T) {
+	repoDir, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	initGitRepository(t, repoDir)
+
+	if err := runCreate(nil, []string{"Committed elsewhere"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+
+	// Run from another git repository, pointing at the issues with --repo
+	elsewhere, err := os.MkdirTemp("", "git-issue-elsewhere-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(elsewhere) }()
+	initGitRepository(t, elsewhere)
+	if err := os.Chdir(elsewhere); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+
+	repoPath = repoDir
+	applyRepoPath()
+	defer func() {
+		repoPath = ""
+		applyRepoPath()
+	}()
+
+	closeCommit = true
+	if err := runClose(nil, []string{"001"}); err != nil {
+		t.Fatalf("runClose() failed: %v", err)
+	}
+
+	if lastMessage := gitLastCommitMessage(t, repoDir); lastMessage != "Close issue #001" {
+		t.Errorf("unexpected commit message %q", lastMessage)
+	}
+	if err := exec.Command("git", "-C", elsewhere, "rev-parse", "--verify", "HEAD").Run(); err == nil {
+		t.Error("the change was committed to the repository in the working directory")
+	}
+}
+
 func TestRunClosePreservesFilenameWithKoreanTitle(t *testing.T) {
 	_, cleanup := setupCommandTestRepo(t)
 	defer cleanup()
@@ -284,3 +326,32 @@ func TestRunClosePreservesFilenameWhenTitleModified(t *testing.T) {
 		t.Errorf("closed directory should have exactly 1 file, found %d", len(closedFiles))
 	}
 }
+
+func TestRunCloseFollowsWorkflowTransitions(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+	writeWorkflowConfig(t)
+
+	if err := runCreate(nil, []string{"Workflow issue"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+
+	// open only leads to in-progress
+	err := runClose(nil, []string{"001"})
+	if err == nil || !strings.Contains(err.Error(), "can't move issue 001 from open to closed") {
+		t.Fatalf("runClose() error = %v, want a transition error", err)
+	}
+	if _, dir, _ := pkg.LoadIssue("001"); dir != pkg.OpenDir {
+		t.Errorf("issue moved to %s despite the error", dir)
+	}
+
+	// done leads back to open
+	for _, state := range []string{"in-progress", "done"} {
+		if err := runStatus(nil, []string{"001", state}); err != nil {
+			t.Fatalf("runStatus(%s) failed: %v", state, err)
+		}
+	}
+	if err := runOpen(nil, []string{"001"}); err != nil {
+		t.Fatalf("runOpen() failed: %v", err)
+	}
+}
@@ -0,0 +1,168 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	commentAuthor string
+	commentFile   string
+	commentCommit bool
+)
+
+var commentCmd = &cobra.Command{
+	Use:   "comment <issue-id> [text]",
+	Short: "Add a comment to an issue",
+	Long: `Add a comment to an issue's discussion thread.
+
+Comments are appended to a "## Comments" section at the end of the issue file,
+each under a "### author — timestamp" heading. The author defaults to git's
+user.name (or $USER).
+
+Without text, the comment is read from standard input when it's piped, or
+written in $EDITOR otherwise.
+
+Examples:
+  gi comment 001 "Reproduced on staging"
+  gi comment 001 --author alice "Fixed in #42"
+  git log -1 --format=%B | gi comment 001
+  gi comment 001 -F notes.md
+  gi comment 001                  # Write the comment in $EDITOR`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runComment,
+}
+
+func init() {
+	rootCmd.AddCommand(commentCmd)
+	commentCmd.Flags().StringVar(&commentAuthor, "author", "", "Comment author (defaults to git user.name or $USER)")
+	commentCmd.Flags().StringVarP(&commentFile, "file", "F", "", "Read the comment from a file ('-' for standard input)")
+	commentCmd.Flags().BoolVarP(&commentCommit, "commit", "c", false, "Auto-commit the change to git")
+}
+
+func runComment(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	issueID := args[0]
+
+	// Check the issue exists before asking for text
+	issue, _, err := pkg.LoadIssue(issueID)
+	if err != nil {
+		return fmt.Errorf("failed to load issue: %w", err)
+	}
+
+	author := commentAuthor
+	if author == "" {
+		author = gitUserName()
+	}
+	if author == "" {
+		author = os.Getenv("USER")
+	}
+	if author == "" {
+		return fmt.Errorf("can't determine the comment author; use --author")
+	}
+
+	text, err := readCommentText(args[1:], issue)
+	if err != nil {
+		return err
+	}
+	if strings.TrimSpace(text) == "" {
+		return fmt.Errorf("aborting comment due to empty text")
+	}
+
+	comment, err := pkg.AddComment(issue.ID, author, text)
+	if err != nil {
+		return fmt.Errorf("failed to add comment: %w", err)
+	}
+
+	fmt.Printf("✓ Commented on issue #%s as %s\n", issue.ID, comment.Author)
+
+	// Handle git commit if requested
+	if commentCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Comment on issue #%s", issue.ID)); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
+
+// readCommentText takes the comment from the arguments, --file, piped standard input or $EDITOR
+func readCommentText(args []string, issue *pkg.Issue) (string, error) {
+	if len(args) > 0 {
+		if commentFile != "" {
+			return "", fmt.Errorf("give the comment as text or with --file, not both")
+		}
+		return strings.Join(args, " "), nil
+	}
+
+	switch {
+	case commentFile == "-":
+		return readAll(os.Stdin)
+	case commentFile != "":
+		data, err := os.ReadFile(commentFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read comment: %w", err)
+		}
+		return string(data), nil
+	case !stdinIsTerminal():
+		return readAll(os.Stdin)
+	}
+
+	return editComment(issue)
+}
+
+func readAll(r io.Reader) (string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read comment: %w", err)
+	}
+	return string(data), nil
+}
+
+// stdinIsTerminal reports whether standard input is interactive rather than a pipe or file
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// commentTemplateMarker separates the comment from the instructions in the editor
+const commentTemplateMarker = "# ------------------------ >8 ------------------------"
+
+// editComment opens $EDITOR on a temporary file and returns what was written above the marker
+func editComment(issue *pkg.Issue) (string, error) {
+	f, err := os.CreateTemp("", "gi-comment-*.md")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer func() { _ = os.Remove(f.Name()) }()
+
+	instructions := fmt.Sprintf("\n%s\n# Write your comment on #%s %s above this line.\n# An empty comment aborts.\n",
+		commentTemplateMarker, issue.ID, issue.Title)
+	if _, err := f.WriteString(instructions); err != nil {
+		_ = f.Close()
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	if err := openEditor(f.Name()); err != nil {
+		return "", err
+	}
+
+	data, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to read comment: %w", err)
+	}
+	text, _, _ := strings.Cut(string(data), commentTemplateMarker)
+	return text, nil
+}
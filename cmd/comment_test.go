@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestRunComment(t *testing.T) {
+	tmpDir, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Discussed issue"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+
+	commentAuthor = "alice"
+	if err := runComment(nil, []string{"001", "Looks", "good"}); err != nil {
+		t.Fatalf("runComment() failed: %v", err)
+	}
+
+	file := filepath.Join(tmpDir, "comment.md")
+	if err := os.WriteFile(file, []byte("From a file\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commentAuthor = "bob"
+	commentFile = file
+	if err := runComment(nil, []string{"001"}); err != nil {
+		t.Fatalf("runComment(--file) failed: %v", err)
+	}
+
+	issue, _, err := pkg.LoadIssue("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issue.Comments) != 2 || issue.Comments[0].Body != "Looks good" || issue.Comments[1].Author != "bob" || issue.Comments[1].Body != "From a file" {
+		t.Errorf("Comments = %+v", issue.Comments)
+	}
+
+	if err := runShow(nil, []string{"001"}); err != nil {
+		t.Errorf("runShow() with comments failed: %v", err)
+	}
+
+	if err := runComment(nil, []string{"001", "both"}); err == nil {
+		t.Error("runComment() with text and --file should fail")
+	}
+	commentFile = ""
+	if err := runComment(nil, []string{"404", "text"}); err == nil {
+		t.Error("runComment() on a missing issue should fail")
+	}
+}
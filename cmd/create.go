@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/Allra-Fintech/git-issue/pkg"
@@ -10,8 +11,13 @@ import (
 )
 
 var (
-	createAssignee string
-	createLabels   []string
+	createAssignee  string
+	createLabels    []string
+	createFields    []string
+	createParent    string
+	createPriority  string
+	createDue       string
+	createMilestone string
 )
 
 var createCmd = &cobra.Command{
@@ -21,7 +27,14 @@ var createCmd = &cobra.Command{
 
 Examples:
   gi create "Fix authentication bug"
-  gi create "Add user profile" --assignee john --label feature --label backend`,
+  gi create "Add user profile" --assignee john --label feature --label backend
+  gi create "Slow checkout" --set priority=high --set estimate=3
+  gi create "Login form" --parent 001
+  gi create "Renew certificate" --priority high --due 2026-11-01
+  gi create "Release notes" --milestone v1.0
+
+Custom fields set with --set are validated against .issues/config.yaml, as are
+priorities (critical, high, medium, low unless config.yaml sets priorities).`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runCreate,
 }
@@ -30,6 +43,11 @@ func init() {
 	rootCmd.AddCommand(createCmd)
 	createCmd.Flags().StringVar(&createAssignee, "assignee", "", "Assign the issue to a user")
 	createCmd.Flags().StringSliceVar(&createLabels, "label", []string{}, "Add labels to the issue (can be specified multiple times)")
+	createCmd.Flags().StringArrayVar(&createFields, "set", []string{}, "Set a custom field as key=value (can be specified multiple times)")
+	createCmd.Flags().StringVar(&createParent, "parent", "", "Make the issue a child of another issue (e.g. an epic)")
+	createCmd.Flags().StringVar(&createPriority, "priority", "", "Set the priority (e.g. high)")
+	createCmd.Flags().StringVar(&createDue, "due", "", "Set the due date (YYYY-MM-DD)")
+	createCmd.Flags().StringVar(&createMilestone, "milestone", "", "Add the issue to a milestone")
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
@@ -46,18 +64,31 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("issue title cannot be empty")
 	}
 
-	// Get next ID
-	id, err := pkg.GetNextID()
+	// Load custom field schema
+	cfg, err := pkg.LoadConfig()
 	if err != nil {
-		return fmt.Errorf("failed to get next issue ID: %w", err)
+		return err
 	}
 
-	// Create new issue
-	issue := pkg.NewIssue(id, title, createAssignee, createLabels)
+	// Parse custom fields
+	fields, err := parseFieldValues(cfg, createFields)
+	if err != nil {
+		return err
+	}
 
-	// Save issue to open directory
-	if err := pkg.SaveIssue(issue, pkg.OpenDir); err != nil {
-		return fmt.Errorf("failed to save issue: %w", err)
+	// Validate, allocate an ID (counter, hash, ULID or provisional, per
+	// config.yaml) and save to the open directory
+	issue, err := pkg.CreateIssue(title, currentBranch(), pkg.IssueOptions{
+		Assignee:  createAssignee,
+		Labels:    createLabels,
+		Priority:  createPriority,
+		Due:       createDue,
+		Milestone: createMilestone,
+		Parent:    createParent,
+		Fields:    fields,
+	})
+	if err != nil {
+		return err
 	}
 
 	// Display success message
@@ -70,21 +101,74 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	// Display issue details
 	fmt.Printf("  ID:       %s\n", issue.ID)
 	fmt.Printf("  Title:    %s\n", issue.Title)
-	fmt.Printf("  Status:   open\n")
+	status := issue.Status
+	if status == "" {
+		status = pkg.StateOpen
+	}
+	fmt.Printf("  Status:   %s\n", status)
 	if issue.Assignee != "" {
 		fmt.Printf("  Assignee: %s\n", issue.Assignee)
 	}
 	if len(issue.Labels) > 0 {
 		fmt.Printf("  Labels:   %s\n", strings.Join(issue.Labels, ", "))
 	}
+	if issue.Parent != "" {
+		fmt.Printf("  Parent:   #%s\n", issue.Parent)
+	}
+	for _, name := range planningFields(cfg) {
+		if value, ok := issue.Field(name); ok {
+			fmt.Printf("  %-9s %s\n", fieldLabel(name)+":", pkg.FormatFieldValue(value))
+		}
+	}
+	for _, field := range cfg.Fields {
+		if value, ok := issue.Field(field.Name); ok {
+			fmt.Printf("  %-9s %s\n", fieldLabel(field.Name)+":", pkg.FormatFieldValue(value))
+		}
+	}
 	fmt.Printf("  Created:  %s\n", issue.Created.Format("2006-01-02 15:04:05"))
 	fmt.Println()
 
 	// Show file path
 	slug := pkg.GenerateSlug(issue.Title)
 	filename := fmt.Sprintf("%s-%s.md", issue.ID, slug)
-	fmt.Printf("Issue saved to: .issues/open/%s\n", filename)
+	fmt.Printf("Issue saved to: %s\n", filepath.Join(pkg.GetOpenPath(), filename))
 	fmt.Printf("Edit the file to add a detailed description.\n")
 
 	return nil
 }
+
+// planningFields returns the priority, due and milestone fields that aren't
+// declared as custom fields in config.yaml (those are displayed with the custom fields)
+func planningFields(cfg *pkg.Config) []string {
+	var names []string
+	for _, name := range []string{pkg.PriorityField, pkg.DueField, pkg.MilestoneField} {
+		if cfg.Field(name) == nil {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseFieldValues parses --set key=value flags into values typed by the schema
+func parseFieldValues(cfg *pkg.Config, raw []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for _, f := range raw {
+		name, value, ok := strings.Cut(f, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid field %q (expected key=value)", f)
+		}
+
+		field := cfg.Field(name)
+		if field == nil {
+			return nil, fmt.Errorf("field %q is not declared in %s", name, pkg.ConfigFile)
+		}
+
+		parsed, err := field.Parse(value)
+		if err != nil {
+			return nil, err
+		}
+		values[name] = parsed
+	}
+	return values, nil
+}
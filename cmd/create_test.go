@@ -339,3 +339,110 @@ func TestCreateIssueFile(t *testing.T) {
 		t.Error("Issue file should contain title as heading")
 	}
 }
+
+func TestCreateFromSubdirectory(t *testing.T) {
+	repoDir, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	nested := filepath.Join(repoDir, "services", "api")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatalf("failed to create nested directory: %v", err)
+	}
+	if err := os.Chdir(nested); err != nil {
+		t.Fatalf("failed to change to nested directory: %v", err)
+	}
+
+	if err := runCreate(nil, []string{"Created from a subdirectory"}); err != nil {
+		t.Fatalf("runCreate() from subdirectory failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(repoDir, pkg.IssuesDir, pkg.OpenDir, "001-created-from-a-subdirectory.md")); err != nil {
+		t.Errorf("issue should be written to the repository root .issues: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(nested, pkg.IssuesDir)); !os.IsNotExist(err) {
+		t.Error("no .issues directory should be created in the subdirectory")
+	}
+
+	if err := runClose(nil, []string{"001"}); err != nil {
+		t.Fatalf("runClose() from subdirectory failed: %v", err)
+	}
+}
+
+func TestCreateWithRepoFlag(t *testing.T) {
+	repoDir, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	elsewhere, err := os.MkdirTemp("", "git-issue-elsewhere-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer func() { _ = os.RemoveAll(elsewhere) }()
+	if err := os.Chdir(elsewhere); err != nil {
+		t.Fatalf("failed to change directory: %v", err)
+	}
+
+	repoPath = repoDir
+	applyRepoPath()
+	defer func() {
+		repoPath = ""
+		applyRepoPath()
+	}()
+
+	if err := runCreate(nil, []string{"Created with repo flag"}); err != nil {
+		t.Fatalf("runCreate() with --repo failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(repoDir, pkg.IssuesDir, pkg.OpenDir, "001-created-with-repo-flag.md")); err != nil {
+		t.Errorf("issue should be written to the --repo location: %v", err)
+	}
+}
+
+func TestCreateWithCustomFields(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	config := "fields:\n  - name: priority\n    type: enum\n    values: [low, high]\n    default: low\n  - name: estimate\n    type: int\n"
+	if err := os.WriteFile(filepath.Join(pkg.IssuesDir, pkg.ConfigFile), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	createFields = []string{"priority=high", "estimate=3"}
+	if err := runCreate(nil, []string{"Typed fields"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+
+	issue, _, err := pkg.LoadIssue("001")
+	if err != nil {
+		t.Fatalf("failed to load issue: %v", err)
+	}
+	if value, _ := issue.Field("priority"); value != "high" {
+		t.Errorf("priority = %v, want high", value)
+	}
+	if value, _ := issue.Field("estimate"); value != 3 {
+		t.Errorf("estimate = %v, want 3", value)
+	}
+
+	createFields = []string{}
+	if err := runCreate(nil, []string{"Defaulted fields"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+	issue, _, err = pkg.LoadIssue("002")
+	if err != nil {
+		t.Fatalf("failed to load issue: %v", err)
+	}
+	if value, _ := issue.Field("priority"); value != "low" {
+		t.Errorf("priority = %v, want default low", value)
+	}
+
+	for _, invalid := range [][]string{{"priority=urgent"}, {"estimate=lots"}, {"color=blue"}, {"novalue"}} {
+		createFields = invalid
+		if err := runCreate(nil, []string{"Invalid fields"}); err == nil {
+			t.Errorf("runCreate() with --set %v should fail", invalid)
+		}
+	}
+
+	// Rejected creates must not consume IDs
+	if id, err := pkg.GetNextID(); err != nil || id != 3 {
+		t.Errorf("GetNextID() = %d, %v, want 3", id, err)
+	}
+}
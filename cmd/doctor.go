@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+var doctorFix bool
+
+var doctorCmd = &cobra.Command{
+	Use:     "doctor",
+	Aliases: []string{"fsck"},
+	Short:   "Check the issue store for problems",
+	Long: `Check the issue store for problems.
+
+Reports files gi can't read or that other commands silently skip:
+  - unparsable frontmatter and issues without a # title heading
+  - an id field that doesn't match the filename
+  - IDs used by more than one file (repair with 'gi renumber')
+  - a .counter that is missing, isn't a number or is lower than the highest ID
+  - non-.md files in open/ and closed/
+  - filenames whose slug no longer matches the title
+  - custom fields that don't match config.yaml
+
+With --fix, the safe repairs are applied: id fields are rewritten to match
+their filename, stale slugs are renamed, the counter is raised (or recreated
+when missing) and leftover temporary files are removed. A counter that isn't
+a number, such as one with merge conflict markers, is left for you to fix.
+
+Exits with a non-zero status when problems remain, so it can run in CI.
+
+Examples:
+  gi doctor        # Report problems
+  gi doctor --fix  # Repair what can be repaired safely`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+func init() {
+	rootCmd.AddCommand(doctorCmd)
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Apply safe repairs")
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	problems, err := pkg.Doctor(pkg.DoctorOptions{Fix: doctorFix})
+	if err != nil {
+		return fmt.Errorf("failed to check issues: %w", err)
+	}
+
+	if len(problems) == 0 {
+		fmt.Println("✓ No problems found")
+		return nil
+	}
+
+	red := color.New(color.FgRed)
+	green := color.New(color.FgGreen)
+	gray := color.New(color.FgHiBlack)
+
+	remaining, fixable := 0, 0
+	for _, p := range problems {
+		if p.Fixed {
+			_, _ = green.Print("✓ fixed ")
+		} else {
+			remaining++
+			_, _ = red.Print("✗ ")
+		}
+		fmt.Printf("%s: %s ", p.Name, p.Message)
+		_, _ = gray.Printf("[%s]", p.Kind)
+		if p.Fixable && !p.Fixed {
+			fixable++
+			_, _ = gray.Print(" (fixable)")
+		}
+		fmt.Println()
+	}
+
+	fmt.Println()
+	if remaining == 0 {
+		fmt.Printf("✓ Fixed %d problem(s)\n", len(problems))
+		return nil
+	}
+	if fixable > 0 {
+		fmt.Printf("Run 'gi doctor --fix' to repair %d of them.\n", fixable)
+	}
+
+	return fmt.Errorf("%d problem(s) found", remaining)
+}
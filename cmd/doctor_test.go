@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestRunDoctor(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Healthy"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+	if err := runDoctor(nil, []string{}); err != nil {
+		t.Fatalf("runDoctor() on a healthy store failed: %v", err)
+	}
+
+	// Renaming the title leaves a stale slug, which --fix repairs
+	path := filepath.Join(pkg.GetOpenPath(), "001-healthy.md")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(data), "# Healthy", "# Renamed", 1)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err = runDoctor(nil, []string{})
+	if err == nil || !strings.Contains(err.Error(), "1 problem") {
+		t.Fatalf("runDoctor() error = %v, want 1 problem", err)
+	}
+
+	doctorFix = true
+	if err := runDoctor(nil, []string{}); err != nil {
+		t.Fatalf("runDoctor(--fix) failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(pkg.GetOpenPath(), "001-renamed.md")); err != nil {
+		t.Errorf("expected renamed file: %v", err)
+	}
+
+	// Unparsable files can't be fixed automatically
+	if err := os.WriteFile(filepath.Join(pkg.GetOpenPath(), "002-broken.md"), []byte("no frontmatter"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := runDoctor(nil, []string{}); err == nil {
+		t.Error("runDoctor(--fix) should fail while unfixable problems remain")
+	}
+}
@@ -25,26 +25,26 @@ func init() {
 func runEdit(cmd *cobra.Command, args []string) error {
 	issueID := args[0]
 
+	if err := editIssue(issueID); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Updated issue #%s\n", issueID)
+
+	return nil
+}
+
+// editIssue opens an issue in the editor, then validates and saves the result
+func editIssue(issueID string) error {
 	// Find the issue file
 	path, dir, err := pkg.FindIssueFile(issueID)
 	if err != nil {
 		return fmt.Errorf("failed to find issue: %w", err)
 	}
 
-	// Get editor from environment, default to vim
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = pkg.DefaultEditor
-	}
-
 	// Open editor
-	editorCmd := exec.Command(editor, path)
-	editorCmd.Stdin = os.Stdin
-	editorCmd.Stdout = os.Stdout
-	editorCmd.Stderr = os.Stderr
-
-	if err := editorCmd.Run(); err != nil {
-		return fmt.Errorf("failed to open editor: %w", err)
+	if err := openEditor(path); err != nil {
+		return err
 	}
 
 	// Read and validate the edited file
@@ -67,7 +67,23 @@ func runEdit(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to save issue: %w", err)
 	}
 
-	fmt.Printf("✓ Updated issue #%s\n", issueID)
+	return nil
+}
+
+// openEditor opens path in $EDITOR (defaults to vim) and waits for it to exit
+func openEditor(path string) error {
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = pkg.DefaultEditor
+	}
 
+	editorCmd := exec.Command(editor, path)
+	editorCmd.Stdin = os.Stdin
+	editorCmd.Stdout = os.Stdout
+	editorCmd.Stderr = os.Stderr
+
+	if err := editorCmd.Run(); err != nil {
+		return fmt.Errorf("failed to open editor: %w", err)
+	}
 	return nil
 }
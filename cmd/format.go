@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by --format
+const (
+	formatTable    = "table"
+	formatJSON     = "json"
+	formatNDJSON   = "ndjson"
+	formatYAML     = "yaml"
+	formatCSV      = "csv"
+	formatMarkdown = "markdown"
+)
+
+var outputFormats = []string{formatTable, formatJSON, formatNDJSON, formatYAML, formatCSV, formatMarkdown}
+
+// outputOptions holds the --format and --template flags of a command
+type outputOptions struct {
+	format   string
+	template string
+}
+
+// addOutputFlags registers --format and --template on cmd
+func addOutputFlags(cmd *cobra.Command, opts *outputOptions) {
+	cmd.Flags().StringVar(&opts.format, "format", formatTable, "Output format: "+strings.Join(outputFormats, ", "))
+	cmd.Flags().StringVar(&opts.template, "template", "", "Render each issue with a Go template, e.g. '{{.ID}} {{.Title}}'")
+}
+
+// reset restores the flag defaults
+func (o *outputOptions) reset() {
+	o.format = formatTable
+	o.template = ""
+}
+
+// validate checks the flags before any work is done
+func (o *outputOptions) validate() error {
+	if o.format == "" {
+		o.format = formatTable
+	}
+	if !containsFormat(o.format) {
+		return fmt.Errorf("invalid format: %s (must be one of: %s)", o.format, strings.Join(outputFormats, ", "))
+	}
+	if o.template != "" && o.format != formatTable {
+		return fmt.Errorf("--template can't be combined with --format %s", o.format)
+	}
+	if o.template != "" {
+		if _, err := parseIssueTemplate(o.template); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// tabular reports whether the human-readable table/text output was requested
+func (o *outputOptions) tabular() bool {
+	return o.format == formatTable && o.template == ""
+}
+
+func containsFormat(format string) bool {
+	for _, f := range outputFormats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// issueRecords converts issues to their machine-readable records
+func issueRecords(items []issueWithStatus) []pkg.IssueRecord {
+	records := make([]pkg.IssueRecord, len(items))
+	for i, item := range items {
+		records[i] = pkg.NewIssueRecord(item.issue, item.dir, item.status)
+	}
+	return records
+}
+
+// writeIssues renders issues in a machine-readable format (anything but the default table)
+func writeIssues(w io.Writer, items []issueWithStatus, cfg *pkg.Config, opts outputOptions) error {
+	records := issueRecords(items)
+
+	if opts.template != "" {
+		return writeTemplate(w, records, opts.template)
+	}
+
+	switch opts.format {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	case formatNDJSON:
+		enc := json.NewEncoder(w)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatYAML:
+		return writeYAML(w, records)
+	case formatCSV:
+		return writeCSV(w, records, cfg)
+	case formatMarkdown:
+		return writeMarkdownTable(w, records, cfg)
+	}
+	return fmt.Errorf("unsupported format: %s", opts.format)
+}
+
+// writeIssue renders a single issue, as gi show does. JSON and YAML output an
+// object rather than a one-element list.
+func writeIssue(w io.Writer, item issueWithStatus, cfg *pkg.Config, opts outputOptions) error {
+	record := pkg.NewIssueRecord(item.issue, item.dir, item.status)
+
+	switch {
+	case opts.template != "":
+		return writeTemplate(w, []pkg.IssueRecord{record}, opts.template)
+	case opts.format == formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(record)
+	case opts.format == formatYAML:
+		return writeYAML(w, record)
+	case opts.format == formatMarkdown:
+		return writeMarkdownIssue(w, record, cfg)
+	}
+	return writeIssues(w, []issueWithStatus{item}, cfg, opts)
+}
+
+func writeYAML(w io.Writer, v interface{}) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// parseIssueTemplate parses a --template, with a few helpers for common formatting
+func parseIssueTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("issue").Funcs(template.FuncMap{
+		"join":  strings.Join,
+		"upper": strings.ToUpper,
+		"lower": strings.ToLower,
+		"field": func(r pkg.IssueRecord, name string) string { return pkg.FormatFieldValue(r.Fields[name]) },
+	}).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// writeTemplate executes the template once per issue, one line each
+func writeTemplate(w io.Writer, records []pkg.IssueRecord, text string) error {
+	tmpl, err := parseIssueTemplate(text)
+	if err != nil {
+		return err
+	}
+
+	for _, record := range records {
+		var buf strings.Builder
+		if err := tmpl.Execute(&buf, record); err != nil {
+			return fmt.Errorf("failed to render template: %w", err)
+		}
+		out := buf.String()
+		if !strings.HasSuffix(out, "\n") {
+			out += "\n"
+		}
+		if _, err := io.WriteString(w, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// standardColumns are the CSV columns every issue has
+var standardColumns = []string{"id", "title", "status", "assignee", "labels", "created", "updated", "path"}
+
+// customColumns lists the custom fields declared in config.yaml, then any
+// other frontmatter keys the records have
+func customColumns(records []pkg.IssueRecord, cfg *pkg.Config) []string {
+	var columns []string
+	seen := make(map[string]bool)
+	for _, field := range cfg.Fields {
+		columns = append(columns, field.Name)
+		seen[field.Name] = true
+	}
+	var others []string
+	for _, record := range records {
+		for _, name := range record.FieldNames() {
+			if !seen[name] {
+				others = append(others, name)
+				seen[name] = true
+			}
+		}
+	}
+	sort.Strings(others)
+
+	return append(columns, others...)
+}
+
+// recordColumnValue returns the text of a column for a record
+func recordColumnValue(record pkg.IssueRecord, column string) string {
+	switch column {
+	case "id":
+		return record.ID
+	case "title":
+		return record.Title
+	case "status":
+		return record.Status
+	case "assignee":
+		return record.Assignee
+	case "labels":
+		return strings.Join(record.Labels, ", ")
+	case "created":
+		return record.Created
+	case "updated":
+		return record.Updated
+	case "path":
+		return record.Path
+	case "parent":
+		return record.Parent
+	}
+	return pkg.FormatFieldValue(record.Fields[column])
+}
+
+func writeCSV(w io.Writer, records []pkg.IssueRecord, cfg *pkg.Config) error {
+	columns := append(append([]string(nil), standardColumns...), customColumns(records, cfg)...)
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(columns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = recordColumnValue(record, column)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// markdownCell escapes a value for use in a markdown table cell
+func markdownCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
+
+func writeMarkdownTable(w io.Writer, records []pkg.IssueRecord, cfg *pkg.Config) error {
+	columns := []string{"id", "title", "status", "assignee", "labels"}
+	for _, field := range cfg.Fields {
+		columns = append(columns, field.Name)
+	}
+
+	var b strings.Builder
+	b.WriteString("| " + strings.Join(columns, " | ") + " |\n")
+	b.WriteString("|" + strings.Repeat(" --- |", len(columns)) + "\n")
+	for _, record := range records {
+		cells := make([]string, len(columns))
+		for i, column := range columns {
+			value := recordColumnValue(record, column)
+			if column == "id" {
+				value = "#" + value
+			}
+			cells[i] = markdownCell(value)
+		}
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdownIssue(w io.Writer, record pkg.IssueRecord, cfg *pkg.Config) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# #%s %s\n\n", record.ID, record.Title)
+	fmt.Fprintf(&b, "- **Status:** %s\n", record.Status)
+	if record.Parent != "" {
+		fmt.Fprintf(&b, "- **Parent:** #%s\n", record.Parent)
+	}
+	if record.Assignee != "" {
+		fmt.Fprintf(&b, "- **Assignee:** %s\n", record.Assignee)
+	}
+	if len(record.Labels) > 0 {
+		fmt.Fprintf(&b, "- **Labels:** %s\n", strings.Join(record.Labels, ", "))
+	}
+	for _, column := range customColumns([]pkg.IssueRecord{record}, cfg) {
+		if _, ok := record.Fields[column]; ok {
+			fmt.Fprintf(&b, "- **%s:** %s\n", fieldLabel(column), recordColumnValue(record, column))
+		}
+	}
+	for _, t := range pkg.LinkTypes {
+		if ids := record.Links[string(t)]; len(ids) > 0 {
+			fmt.Fprintf(&b, "- **%s:** %s\n", fieldLabel(string(t)), formatIssueRefs(ids))
+		}
+	}
+	fmt.Fprintf(&b, "- **Created:** %s\n", record.Created)
+	fmt.Fprintf(&b, "- **Updated:** %s\n", record.Updated)
+	if record.Body != "" {
+		b.WriteString("\n" + record.Body + "\n")
+	}
+	for i, comment := range record.Comments {
+		if i == 0 {
+			b.WriteString("\n## Comments\n")
+		}
+		fmt.Fprintf(&b, "\n**%s** (%s):\n\n%s\n", comment.Author, comment.Created.Format(time.RFC3339), comment.Body)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"gopkg.in/yaml.v3"
+)
+
+func formatTestItems() ([]issueWithStatus, *pkg.Config) {
+	created := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	first := &pkg.Issue{
+		ID:       "001",
+		Title:    "Fix | pipes",
+		Assignee: "alice",
+		Labels:   []string{"bug", "backend"},
+		Created:  created,
+		Updated:  created,
+		Body:     "Body text",
+		Path:     ".issues/open/001-fix-pipes.md",
+	}
+	first.SetField("priority", "high")
+	first.SetField("due", time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC))
+	second := &pkg.Issue{ID: "002", Title: "Done", Created: created, Updated: created, Path: ".issues/closed/002-done.md"}
+
+	cfg := &pkg.Config{Fields: []pkg.FieldDef{{Name: "priority", Type: pkg.FieldString}}}
+	return []issueWithStatus{
+		{issue: first, dir: pkg.OpenDir, status: "in-progress"},
+		{issue: second, dir: pkg.ClosedDir, status: "closed"},
+	}, cfg
+}
+
+func TestWriteIssuesJSONSchema(t *testing.T) {
+	items, cfg := formatTestItems()
+
+	var buf bytes.Buffer
+	if err := writeIssues(&buf, items, cfg, outputOptions{format: formatJSON}); err != nil {
+		t.Fatalf("writeIssues() error = %v", err)
+	}
+
+	var records []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2", len(records))
+	}
+
+	first := records[0]
+	for _, key := range []string{"id", "title", "status", "closed", "assignee", "labels", "created", "updated", "path", "fields", "body"} {
+		if _, ok := first[key]; !ok {
+			t.Errorf("JSON record is missing %q", key)
+		}
+	}
+	if first["status"] != "in-progress" || first["path"] != ".issues/open/001-fix-pipes.md" || first["created"] != "2026-09-01T10:00:00Z" {
+		t.Errorf("unexpected record: %v", first)
+	}
+	fields := first["fields"].(map[string]interface{})
+	if fields["priority"] != "high" || fields["due"] != "2026-10-01" {
+		t.Errorf("fields = %v", fields)
+	}
+
+	// Empty collections are [] and {}, never null
+	second := records[1]
+	if _, ok := second["labels"].([]interface{}); !ok || second["closed"] != true {
+		t.Errorf("second record = %v", second)
+	}
+	if _, ok := second["fields"].(map[string]interface{}); !ok {
+		t.Errorf("fields should be an object, got %v", second["fields"])
+	}
+}
+
+func TestWriteIssuesFormats(t *testing.T) {
+	items, cfg := formatTestItems()
+
+	render := func(opts outputOptions) string {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := writeIssues(&buf, items, cfg, opts); err != nil {
+			t.Fatalf("writeIssues(%+v) error = %v", opts, err)
+		}
+		return buf.String()
+	}
+
+	ndjson := strings.Split(strings.TrimSpace(render(outputOptions{format: formatNDJSON})), "\n")
+	if len(ndjson) != 2 || !json.Valid([]byte(ndjson[0])) {
+		t.Errorf("ndjson = %q", ndjson)
+	}
+
+	var records []pkg.IssueRecord
+	if err := yaml.Unmarshal([]byte(render(outputOptions{format: formatYAML})), &records); err != nil || len(records) != 2 || records[0].Title != "Fix | pipes" {
+		t.Errorf("yaml = %+v, %v", records, err)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(render(outputOptions{format: formatCSV}))).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if strings.Join(rows[0], ",") != "id,title,status,assignee,labels,created,updated,path,priority,due" {
+		t.Errorf("CSV header = %v", rows[0])
+	}
+	if rows[1][4] != "bug, backend" || rows[1][9] != "2026-10-01" || len(rows) != 3 {
+		t.Errorf("CSV rows = %v", rows)
+	}
+
+	markdown := render(outputOptions{format: formatMarkdown})
+	if !strings.Contains(markdown, "| #001 | Fix \\| pipes | in-progress | alice | bug, backend | high |") {
+		t.Errorf("markdown = %q", markdown)
+	}
+
+	tmpl := render(outputOptions{format: formatTable, template: `{{.ID}} {{.Title}} [{{join .Labels ","}}] {{field . "priority"}}`})
+	if tmpl != "001 Fix | pipes [bug,backend] high\n002 Done [] \n" {
+		t.Errorf("template = %q", tmpl)
+	}
+}
+
+func TestWriteIssueSingleObject(t *testing.T) {
+	items, cfg := formatTestItems()
+
+	var buf bytes.Buffer
+	if err := writeIssue(&buf, items[0], cfg, outputOptions{format: formatJSON}); err != nil {
+		t.Fatal(err)
+	}
+	var record pkg.IssueRecord
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil || record.ID != "001" {
+		t.Errorf("show --format json = %s (%v)", buf.String(), err)
+	}
+
+	buf.Reset()
+	if err := writeIssue(&buf, items[0], cfg, outputOptions{format: formatMarkdown}); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(buf.String(), "# #001 Fix | pipes\n") || !strings.Contains(buf.String(), "- **Priority:** high") {
+		t.Errorf("show --format markdown = %q", buf.String())
+	}
+}
+
+func TestOutputOptionsValidate(t *testing.T) {
+	tests := []struct {
+		opts    outputOptions
+		wantErr string
+	}{
+		{outputOptions{format: "xml"}, "invalid format"},
+		{outputOptions{format: formatJSON, template: "{{.ID}}"}, "can't be combined"},
+		{outputOptions{format: formatTable, template: "{{.ID"}, "invalid template"},
+	}
+	for _, tt := range tests {
+		if err := tt.opts.validate(); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("validate(%+v) error = %v, want %q", tt.opts, err, tt.wantErr)
+		}
+	}
+
+	opts := outputOptions{}
+	if err := opts.validate(); err != nil || !opts.tabular() {
+		t.Errorf("empty options should default to the table format")
+	}
+}
+
+func TestListSearchShowWithFormat(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Formatted issue"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+
+	listOutput.format = formatJSON
+	if err := runList(nil, []string{}); err != nil {
+		t.Errorf("runList(--format json) failed: %v", err)
+	}
+	searchOutput.format = formatCSV
+	if err := runSearch(nil, []string{"Formatted"}); err != nil {
+		t.Errorf("runSearch(--format csv) failed: %v", err)
+	}
+	showOutput.template = "{{.Path}}"
+	if err := runShow(nil, []string{"001"}); err != nil {
+		t.Errorf("runShow(--template) failed: %v", err)
+	}
+
+	listOutput.format = "xml"
+	if err := runList(nil, []string{}); err == nil {
+		t.Error("runList() should reject an unknown format")
+	}
+}
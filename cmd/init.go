@@ -26,7 +26,7 @@ func init() {
 func runInit(cmd *cobra.Command, args []string) error {
 	// Check if .issues already exists
 	if pkg.RepoExists() {
-		return fmt.Errorf(".issues directory already exists. Use 'gi list' to see existing issues")
+		return fmt.Errorf("%s directory already exists. Use 'gi list' to see existing issues", pkg.GetIssuesPath())
 	}
 
 	// Initialize the repository
@@ -35,7 +35,7 @@ func runInit(cmd *cobra.Command, args []string) error {
 	}
 
 	// Display success message
-	fmt.Println("✓ Initialized .issues directory structure:")
+	fmt.Printf("✓ Initialized %s directory structure:\n", pkg.GetIssuesPath())
 	fmt.Println()
 	fmt.Println("  .issues/")
 	fmt.Println("  ├── open/       # Open issues")
@@ -0,0 +1,351 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+)
+
+// issueWithStatus pairs an issue with its directory and workflow status
+type issueWithStatus struct {
+	issue  *pkg.Issue
+	dir    string
+	status string
+	branch string // tree lines drawn before the title by list --tree
+}
+
+// fieldFilter matches a custom frontmatter field against a value (--field key=value)
+type fieldFilter struct {
+	name  string
+	value string
+}
+
+// collectIssues loads the issues in the given directories
+func collectIssues(dirs []string, workflow *pkg.Workflow) []issueWithStatus {
+	var allIssues []issueWithStatus
+
+	for _, dir := range dirs {
+		issues, err := pkg.ListIssues(dir)
+		if err != nil {
+			// If directory doesn't exist yet, just skip it
+			continue
+		}
+
+		for _, issue := range issues {
+			allIssues = append(allIssues, issueWithStatus{
+				issue:  issue,
+				dir:    dir,
+				status: workflow.StatusOf(issue, dir),
+			})
+		}
+	}
+
+	return allIssues
+}
+
+// resolveStatusFilter turns a --status value into the directories to read and
+// the workflow state to match. "open" and "closed" select every issue in that
+// directory, whatever its workflow state; other states match exactly.
+func resolveStatusFilter(status string, workflow *pkg.Workflow) ([]string, string, error) {
+	switch status {
+	case pkg.StateOpen:
+		return []string{pkg.OpenDir}, "", nil
+	case pkg.StateClosed:
+		return []string{pkg.ClosedDir}, "", nil
+	}
+
+	dir, err := workflow.DirFor(status)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid status: %s (must be one of: %s)", status, strings.Join(workflow.StateNames(), ", "))
+	}
+	return []string{dir}, status, nil
+}
+
+// colorStatus colors a status green when it's an open state and red when closed
+func colorStatus(item issueWithStatus, bold bool) string {
+	attrs := []color.Attribute{color.FgGreen}
+	if item.dir == pkg.ClosedDir {
+		attrs = []color.Attribute{color.FgRed}
+	}
+	if bold {
+		attrs = append(attrs, color.Bold)
+	}
+	return color.New(attrs...).Sprint(item.status)
+}
+
+// parseFieldFilters parses --field key=value flags
+func parseFieldFilters(raw []string) ([]fieldFilter, error) {
+	var filters []fieldFilter
+	for _, f := range raw {
+		name, value, ok := strings.Cut(f, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid field filter %q (expected key=value)", f)
+		}
+		filters = append(filters, fieldFilter{name: name, value: strings.TrimSpace(value)})
+	}
+	return filters, nil
+}
+
+// matchesFieldFilters reports whether an issue satisfies every field filter
+func matchesFieldFilters(issue *pkg.Issue, filters []fieldFilter) bool {
+	for _, f := range filters {
+		value, ok := issue.Field(f.name)
+		if !ok || !pkg.MatchFieldValue(value, f.value) {
+			return false
+		}
+	}
+	return true
+}
+
+// fieldLabel turns a custom field name into a display label ("priority" -> "Priority")
+func fieldLabel(name string) string {
+	if name == "" {
+		return name
+	}
+	return strings.ToUpper(name[:1]) + name[1:]
+}
+
+// treeOrder arranges issues depth-first under their parents and sets the
+// tree branch drawn before each title. Issues whose parent isn't in the list
+// are shown at the top level.
+func treeOrder(items []issueWithStatus) []issueWithStatus {
+	present := make(map[string]bool, len(items))
+	for _, item := range items {
+		present[item.issue.ID] = true
+	}
+
+	children := make(map[string][]issueWithStatus)
+	var roots []issueWithStatus
+	for _, item := range items {
+		if parent := item.issue.Parent; parent != "" && parent != item.issue.ID && present[parent] {
+			children[parent] = append(children[parent], item)
+		} else {
+			roots = append(roots, item)
+		}
+	}
+
+	var ordered []issueWithStatus
+	visited := make(map[string]bool, len(items))
+	var walk func(item issueWithStatus, branch, indent string)
+	walk = func(item issueWithStatus, branch, indent string) {
+		if visited[item.issue.ID] {
+			return
+		}
+		visited[item.issue.ID] = true
+		item.branch = branch
+		ordered = append(ordered, item)
+
+		kids := children[item.issue.ID]
+		for i, child := range kids {
+			if i == len(kids)-1 {
+				walk(child, indent+"└─ ", indent+"   ")
+			} else {
+				walk(child, indent+"├─ ", indent+"│  ")
+			}
+		}
+	}
+	for _, item := range roots {
+		walk(item, "", "")
+	}
+
+	// Issues in a parent cycle have no root; list them at the top level
+	for _, item := range items {
+		walk(item, "", "")
+	}
+
+	return ordered
+}
+
+// declaredField reports whether a custom field is declared
+func declaredField(fields []pkg.FieldDef, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isOverdue reports whether an open issue is past its due date
+func isOverdue(item issueWithStatus) bool {
+	return item.dir == pkg.OpenDir && item.issue.DaysOverdue(time.Now()) > 0
+}
+
+// formatProgress renders child progress compactly for tables ("3/7")
+func formatProgress(p pkg.Progress) string {
+	return fmt.Sprintf("%d/%d", p.Closed, p.Total)
+}
+
+// tableColumns are the built-in columns accepted by --columns; custom fields
+// declared in config.yaml are accepted too
+var tableColumns = []string{"id", "title", "status", "assignee", "labels", "priority", "due", "milestone", "parent", "tasks", "children", "created", "updated", "path"}
+
+// parseColumns splits a --columns value ("id,title,updated") and checks each column
+func parseColumns(value string, cfg *pkg.Config) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var columns []string
+	for _, column := range strings.Split(value, ",") {
+		column = strings.TrimSpace(column)
+		if !containsKey(tableColumns, column) && cfg.Field(column) == nil {
+			return nil, fmt.Errorf("invalid column: %q (must be one of: %s, or a custom field)", column, strings.Join(tableColumns, ", "))
+		}
+		columns = append(columns, column)
+	}
+	return columns, nil
+}
+
+// defaultColumns returns the columns shown without --columns: ID, title,
+// status, assignee and labels, then priority, due and milestone, tasks
+// (checked task list items) and children (closed child issues) when any of
+// the issues has some, and a column per custom field
+func defaultColumns(items []issueWithStatus, fields []pkg.FieldDef, progress map[string]pkg.Progress) []string {
+	columns := []string{"id", "title", "status", "assignee", "labels"}
+
+	// Priority, due and milestone, unless declared as custom fields
+	for _, name := range []string{pkg.PriorityField, pkg.DueField, pkg.MilestoneField} {
+		if declaredField(fields, name) {
+			continue
+		}
+		for _, item := range items {
+			if _, ok := item.issue.Field(name); ok {
+				columns = append(columns, name)
+				break
+			}
+		}
+	}
+
+	for _, item := range items {
+		if item.issue.TaskProgress().Total > 0 {
+			columns = append(columns, "tasks")
+			break
+		}
+	}
+	for _, item := range items {
+		if _, ok := progress[item.issue.ID]; ok {
+			columns = append(columns, "children")
+			break
+		}
+	}
+
+	for _, field := range fields {
+		columns = append(columns, field.Name)
+	}
+	return columns
+}
+
+// renderIssueTable prints issues as a table with the given columns, or the
+// default columns when there are none
+func renderIssueTable(items []issueWithStatus, fields []pkg.FieldDef, progress map[string]pkg.Progress, columns []string) {
+	if len(columns) == 0 {
+		columns = defaultColumns(items, fields, progress)
+	}
+
+	// Create table
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader(columns)
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+	table.SetAutoWrapText(false)
+
+	// Add rows
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, column := range columns {
+			row[i] = issueCell(item, column, progress)
+		}
+		table.Append(row)
+	}
+
+	table.Render()
+}
+
+// issueCell returns the table text of a column for an issue, "-" when empty
+func issueCell(item issueWithStatus, column string, progress map[string]pkg.Progress) string {
+	issue := item.issue
+
+	var value string
+	switch column {
+	case "id":
+		return "#" + issue.ID
+	case "title":
+		return item.branch + issue.Title
+	case "status":
+		// Color-code status
+		return colorStatus(item, false)
+	case "assignee":
+		value = issue.Assignee
+	case "labels":
+		value = strings.Join(issue.Labels, ", ")
+	case "parent":
+		if issue.Parent != "" {
+			value = "#" + issue.Parent
+		}
+	case "tasks":
+		// Checked task list items
+		if p := issue.TaskProgress(); p.Total > 0 {
+			value = p.String()
+		}
+	case "children":
+		// Children closed
+		if p, ok := progress[issue.ID]; ok {
+			value = formatProgress(p)
+		}
+	case "created":
+		value = formatTableTime(issue.Created)
+	case "updated":
+		value = formatTableTime(issue.Updated)
+	case "path":
+		value = displayPath(issue.Path)
+	default:
+		// Priority, due, milestone and custom fields; due dates are red when overdue
+		field, _ := issue.Field(column)
+		value = pkg.FormatFieldValue(field)
+		if value != "" && column == pkg.DueField && isOverdue(item) {
+			return color.New(color.FgRed).Sprint(value)
+		}
+	}
+
+	if value == "" {
+		return "-"
+	}
+	return value
+}
+
+// formatTableTime renders a timestamp to the minute, or "" when unset
+func formatTableTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+// displayPath shortens an issue file path to be relative to the working
+// directory when it's below it
+func displayPath(path string) string {
+	wd, err := os.Getwd()
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(wd, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return rel
+}
@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	linkCommit   bool
+	unlinkCommit bool
+)
+
+var linkCmd = &cobra.Command{
+	Use:   "link <issue-id> <type> <issue-id>",
+	Short: "Link two issues",
+	Long: `Record a relationship between two issues.
+
+Link types:
+  blocks      the first issue must be closed before the second
+  depends-on  the first issue can't be closed before the second
+  duplicates  the first issue duplicates the second
+  relates-to  the issues are related
+
+The link is stored in the first issue's frontmatter; 'gi show' displays it on
+both issues ("002 blocks 001" shows as "blocked by #002" on 001). Links that
+would make an issue block itself are refused.
+
+Examples:
+  gi link 002 blocks 001
+  gi link 001 depends-on 002    # The same relationship
+  gi link 005 duplicates 003`,
+	Args: cobra.ExactArgs(3),
+	RunE: runLink,
+}
+
+var unlinkCmd = &cobra.Command{
+	Use:   "unlink <issue-id> <type> <issue-id>",
+	Short: "Remove a link between two issues",
+	Long: `Remove a relationship created with 'gi link', whichever issue it's stored on.
+
+Examples:
+  gi unlink 002 blocks 001`,
+	Args: cobra.ExactArgs(3),
+	RunE: runUnlink,
+}
+
+func init() {
+	rootCmd.AddCommand(linkCmd)
+	rootCmd.AddCommand(unlinkCmd)
+	linkCmd.Flags().BoolVarP(&linkCommit, "commit", "c", false, "Auto-commit the change to git")
+	unlinkCmd.Flags().BoolVarP(&unlinkCommit, "commit", "c", false, "Auto-commit the change to git")
+}
+
+func runLink(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	linkType, err := pkg.ParseLinkType(args[1])
+	if err != nil {
+		return err
+	}
+
+	issue, _, err := pkg.LoadIssue(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load issue: %w", err)
+	}
+	target, _, err := pkg.LoadIssue(args[2])
+	if err != nil {
+		return fmt.Errorf("failed to load issue: %w", err)
+	}
+
+	if err := pkg.Link(issue.ID, linkType, target.ID); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Linked #%s %s #%s\n", issue.ID, linkType, target.ID)
+
+	if linkCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Link issue #%s %s #%s", issue.ID, linkType, target.ID)); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
+
+func runUnlink(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	linkType, err := pkg.ParseLinkType(args[1])
+	if err != nil {
+		return err
+	}
+
+	issue, _, err := pkg.LoadIssue(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load issue: %w", err)
+	}
+
+	// The target may have been deleted since it was linked
+	targetID := args[2]
+	if target, _, err := pkg.LoadIssue(targetID); err == nil {
+		targetID = target.ID
+	}
+
+	if err := pkg.Unlink(issue.ID, linkType, targetID); err != nil {
+		return err
+	}
+
+	fmt.Printf("✓ Unlinked #%s %s #%s\n", issue.ID, linkType, targetID)
+
+	if unlinkCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Unlink issue #%s %s #%s", issue.ID, linkType, targetID)); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
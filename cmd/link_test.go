@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestRunLinkAndUnlink(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	for _, title := range []string{"Release", "Fix tests"} {
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatalf("runCreate() failed: %v", err)
+		}
+	}
+
+	if err := runLink(nil, []string{"002", "blocks", "001"}); err != nil {
+		t.Fatalf("runLink() failed: %v", err)
+	}
+	issue, _, err := pkg.LoadIssue("002")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issue.Blocks) != 1 || issue.Blocks[0] != "001" {
+		t.Errorf("Blocks = %v, want [001]", issue.Blocks)
+	}
+
+	if err := runShow(nil, []string{"001"}); err != nil {
+		t.Errorf("runShow() with links failed: %v", err)
+	}
+
+	if err := runLink(nil, []string{"001", "blocks", "002"}); err == nil {
+		t.Error("runLink() creating a cycle should fail")
+	}
+	if err := runLink(nil, []string{"001", "fixes", "002"}); err == nil {
+		t.Error("runLink() with an unknown type should fail")
+	}
+	if err := runLink(nil, []string{"001", "blocks", "404"}); err == nil {
+		t.Error("runLink() to a missing issue should fail")
+	}
+
+	if err := runUnlink(nil, []string{"001", "depends-on", "002"}); err != nil {
+		t.Fatalf("runUnlink() failed: %v", err)
+	}
+	issue, _, _ = pkg.LoadIssue("002")
+	if len(issue.Blocks) != 0 {
+		t.Errorf("Blocks after unlink = %v", issue.Blocks)
+	}
+}
+
+func TestRunCloseWithOpenBlockers(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	// No success criteria, so --strict only checks blockers
+	if err := os.WriteFile(filepath.Join(pkg.GetIssuesPath(), pkg.TemplateFile), []byte("---\n---\n\n# Title\n\nDetails\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, title := range []string{"Release", "Fix tests", "Write notes"} {
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatalf("runCreate() failed: %v", err)
+		}
+	}
+	if err := runLink(nil, []string{"001", "depends-on", "002"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runLink(nil, []string{"003", "blocks", "002"}); err != nil {
+		t.Fatal(err)
+	}
+
+	closeStrict = true
+	err := runClose(nil, []string{"001"})
+	if err == nil || !strings.Contains(err.Error(), "#002") {
+		t.Fatalf("runClose(--strict) error = %v, want blocked by #002", err)
+	}
+	if _, dir, _ := pkg.LoadIssue("001"); dir != pkg.OpenDir {
+		t.Error("blocked issue was closed with --strict")
+	}
+
+	// Closed blockers don't count
+	if err := runClose(nil, []string{"003"}); err != nil {
+		t.Fatalf("runClose() of the blocker failed: %v", err)
+	}
+	if err := runClose(nil, []string{"002"}); err != nil {
+		t.Fatalf("runClose() with closed blockers failed: %v", err)
+	}
+
+	// Without --strict it only warns
+	if err := runOpen(nil, []string{"002"}); err != nil {
+		t.Fatal(err)
+	}
+	closeStrict = false
+	if err := runClose(nil, []string{"001"}); err != nil {
+		t.Fatalf("runClose() with open blockers failed: %v", err)
+	}
+	if _, dir, _ := pkg.LoadIssue("001"); dir != pkg.ClosedDir {
+		t.Error("issue should be closed despite the warning")
+	}
+}
@@ -6,16 +6,24 @@ import (
 	"strings"
 
 	"github.com/Allra-Fintech/git-issue/pkg"
-	"github.com/fatih/color"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
 var (
-	listAll      bool
-	listAssignee string
-	listLabel    string
-	listStatus   string
+	listAll       bool
+	listAssignee  string
+	listLabel     string
+	listStatus    string
+	listMilestone string
+	listFields    []string
+	listTree      bool
+	listSort      string
+	listLimit     int
+	listOffset    int
+	listColumns   string
+	listQuery     string
+	listView      string
+	listOutput    outputOptions
 )
 
 var listCmd = &cobra.Command{
@@ -25,12 +33,37 @@ var listCmd = &cobra.Command{
 
 By default, only open issues are shown. Use --all to include closed issues.
 
+--query filters with an expression of field:value qualifiers and free text,
+combined with AND, OR, NOT (or a leading -) and parentheses:
+
+  label:bug AND -label:wontfix AND assignee:(jonghun OR mina)
+  created:>2026-09-01 updated:>7d "login page"
+
+Qualifiers are id, title, body, label, assignee, status, milestone, priority,
+parent, due, created, updated and custom fields. created, updated, due and
+date or int custom fields take >, >=, < and <=; created and updated also take
+ages such as 12h, 7d or 2w, which stand for the time that long ago and compare
+like dates: updated:>7d is updated in the last 7 days, created:<2w created
+more than 2 weeks ago, and updated:7d means updated:>=7d. Terms next to each
+other are ANDed. A query that
+uses status: searches closed issues too.
+
 Examples:
   gi list                           # List open issues
   gi list --all                     # List all issues
   gi list --assignee john           # List issues assigned to john
   gi list --label bug               # List issues with 'bug' label
-  gi list --status closed           # List closed issues`,
+  gi list --status closed           # List closed issues
+  gi list --field priority=high     # Filter by a custom field
+  gi list --milestone v1.0          # List issues in the v1.0 milestone
+  gi list -q 'label:bug AND -label:wontfix AND updated:>7d'
+  gi list --view my-bugs            # Run a view saved with 'gi view save'
+  gi list --tree                    # Show child issues under their parent
+  gi list --sort priority,due       # Highest priority first, then earliest due date
+  gi list --sort updated:desc --limit 10
+  gi list --columns id,title,updated,path
+  gi list --format json             # Machine-readable output
+  gi list --template '{{.ID}} {{.Title}}'`,
 	RunE: runList,
 }
 
@@ -39,7 +72,17 @@ func init() {
 	listCmd.Flags().BoolVarP(&listAll, "all", "a", false, "Include closed issues")
 	listCmd.Flags().StringVar(&listAssignee, "assignee", "", "Filter by assignee")
 	listCmd.Flags().StringVar(&listLabel, "label", "", "Filter by label")
-	listCmd.Flags().StringVar(&listStatus, "status", "", "Filter by status (open/closed)")
+	listCmd.Flags().StringVar(&listStatus, "status", "", "Filter by status (open, closed or any workflow state)")
+	listCmd.Flags().StringVar(&listMilestone, "milestone", "", "Filter by milestone")
+	listCmd.Flags().StringArrayVar(&listFields, "field", []string{}, "Filter by custom field as key=value (can be specified multiple times)")
+	listCmd.Flags().BoolVar(&listTree, "tree", false, "Show child issues indented under their parent")
+	listCmd.Flags().StringVarP(&listQuery, "query", "q", "", "Filter with a query expression (see above)")
+	listCmd.Flags().StringVar(&listView, "view", "", "Apply a saved view (see 'gi views')")
+	listCmd.Flags().StringVar(&listSort, "sort", "", "Sort by comma-separated keys, each optionally :asc or :desc ("+strings.Join(sortKeys, ", ")+" or a custom field)")
+	listCmd.Flags().IntVar(&listLimit, "limit", 0, "Show at most this many issues")
+	listCmd.Flags().IntVar(&listOffset, "offset", 0, "Skip this many issues")
+	listCmd.Flags().StringVar(&listColumns, "columns", "", "Comma-separated table columns ("+strings.Join(tableColumns, ", ")+" or a custom field)")
+	addOutputFlags(listCmd, &listOutput)
 }
 
 func runList(cmd *cobra.Command, args []string) error {
@@ -48,19 +91,57 @@ func runList(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
 	}
 
+	if err := listOutput.validate(); err != nil {
+		return err
+	}
+
+	// Load custom field schema and workflow
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Options of a saved view apply unless given on the command line
+	all, sortValue, columnsValue := listAll, listSort, listColumns
+	var queries []*pkg.Query
+	if listView != "" {
+		view, err := pkg.FindView(listView)
+		if err != nil {
+			return err
+		}
+		if view.Query != "" {
+			q, err := pkg.ParseQuery(view.Query, cfg)
+			if err != nil {
+				return fmt.Errorf("view %s: %w", view.Name, err)
+			}
+			queries = append(queries, q)
+		}
+		all = all || view.All
+		if sortValue == "" {
+			sortValue = view.Sort
+		}
+		if columnsValue == "" && listOutput.tabular() {
+			columnsValue = view.Columns
+		}
+	}
+	if listQuery != "" {
+		q, err := pkg.ParseQuery(listQuery, cfg)
+		if err != nil {
+			return err
+		}
+		queries = append(queries, q)
+	}
+
 	// Determine which directories to search
 	var dirsToSearch []string
+	var stateFilter string
 	if listStatus != "" {
 		// Filter by specific status
-		switch listStatus {
-		case "open":
-			dirsToSearch = []string{pkg.OpenDir}
-		case "closed":
-			dirsToSearch = []string{pkg.ClosedDir}
-		default:
-			return fmt.Errorf("invalid status: %s (must be 'open' or 'closed')", listStatus)
+		dirsToSearch, stateFilter, err = resolveStatusFilter(listStatus, &cfg.Workflow)
+		if err != nil {
+			return err
 		}
-	} else if listAll {
+	} else if all || queriesUseStatus(queries) {
 		// Show all issues
 		dirsToSearch = []string{pkg.OpenDir, pkg.ClosedDir}
 	} else {
@@ -68,36 +149,41 @@ func runList(cmd *cobra.Command, args []string) error {
 		dirsToSearch = []string{pkg.OpenDir}
 	}
 
-	// Collect issues from all directories
-	type issueWithStatus struct {
-		issue  *pkg.Issue
-		status string
+	fieldFilters, err := parseFieldFilters(listFields)
+	if err != nil {
+		return err
 	}
-	var allIssues []issueWithStatus
-
-	for _, dir := range dirsToSearch {
-		issues, err := pkg.ListIssues(dir)
-		if err != nil {
-			// If directory doesn't exist yet, just skip it
-			continue
-		}
 
-		status := "open"
-		if dir == pkg.ClosedDir {
-			status = "closed"
-		}
+	sortBy, err := parseSortKeys(sortValue, cfg)
+	if err != nil {
+		return err
+	}
+	if len(sortBy) == 0 {
+		sortBy = []sortKey{{name: "id"}}
+	}
 
-		for _, issue := range issues {
-			allIssues = append(allIssues, issueWithStatus{
-				issue:  issue,
-				status: status,
-			})
-		}
+	columns, err := parseColumns(columnsValue, cfg)
+	if err != nil {
+		return err
+	}
+	if len(columns) > 0 && !listOutput.tabular() {
+		return fmt.Errorf("--columns only applies to table output")
 	}
+	if listLimit < 0 || listOffset < 0 {
+		return fmt.Errorf("--limit and --offset can't be negative")
+	}
+
+	// Collect issues from all directories
+	allIssues := collectIssues(dirsToSearch, &cfg.Workflow)
 
 	// Apply filters
 	var filteredIssues []issueWithStatus
 	for _, item := range allIssues {
+		// Filter by workflow state
+		if stateFilter != "" && item.status != stateFilter {
+			continue
+		}
+
 		// Filter by assignee
 		if listAssignee != "" && item.issue.Assignee != listAssignee {
 			continue
@@ -108,70 +194,76 @@ func runList(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Filter by milestone
+		if listMilestone != "" && !strings.EqualFold(item.issue.Milestone(), listMilestone) {
+			continue
+		}
+
+		// Filter by custom fields
+		if !matchesFieldFilters(item.issue, fieldFilters) {
+			continue
+		}
+
+		// Filter by queries
+		if !matchesQueries(item, queries) {
+			continue
+		}
+
 		filteredIssues = append(filteredIssues, item)
 	}
 
+	sortIssues(filteredIssues, sortBy, cfg)
+	if listTree {
+		filteredIssues = treeOrder(filteredIssues)
+	}
+	total := len(filteredIssues)
+	filteredIssues = paginate(filteredIssues, listOffset, listLimit)
+
+	// Machine-readable output, including an empty list
+	if !listOutput.tabular() {
+		return writeIssues(os.Stdout, filteredIssues, cfg, listOutput)
+	}
+
 	// Display results
 	if len(filteredIssues) == 0 {
 		fmt.Println("No issues found.")
 		return nil
 	}
 
-	// Create table
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"ID", "Title", "Status", "Assignee", "Labels"})
-	table.SetBorder(false)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("")
-	table.SetRowSeparator("")
-	table.SetHeaderLine(false)
-	table.SetTablePadding("\t")
-	table.SetNoWhiteSpace(true)
-	table.SetAutoWrapText(false)
-
-	// Add rows
-	green := color.New(color.FgGreen).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-
-	for _, item := range filteredIssues {
-		issue := item.issue
-		status := item.status
-
-		// Color-code status
-		var statusStr string
-		if status == "open" {
-			statusStr = green(status)
-		} else {
-			statusStr = red(status)
-		}
-
-		// Format labels
-		labelsStr := "-"
-		if len(issue.Labels) > 0 {
-			labelsStr = strings.Join(issue.Labels, ", ")
-		}
-
-		// Format assignee
-		assigneeStr := "-"
-		if issue.Assignee != "" {
-			assigneeStr = issue.Assignee
-		}
-
-		table.Append([]string{
-			"#" + issue.ID,
-			issue.Title,
-			statusStr,
-			assigneeStr,
-			labelsStr,
-		})
+	// Children closed per parent, counting children that aren't listed
+	progress, err := pkg.ChildrenProgress()
+	if err != nil {
+		return err
 	}
 
-	table.Render()
+	renderIssueTable(filteredIssues, cfg.Fields, progress, columns)
 
 	// Summary
-	fmt.Printf("\nTotal: %d issue(s)\n", len(filteredIssues))
+	if len(filteredIssues) < total {
+		fmt.Printf("\nShowing %d-%d of %d issue(s)\n", listOffset+1, listOffset+len(filteredIssues), total)
+	} else {
+		fmt.Printf("\nTotal: %d issue(s)\n", total)
+	}
 
 	return nil
 }
+
+// queriesUseStatus reports whether any of the queries filters on status
+func queriesUseStatus(queries []*pkg.Query) bool {
+	for _, q := range queries {
+		if q.UsesField("status") {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesQueries reports whether an issue matches every query
+func matchesQueries(item issueWithStatus, queries []*pkg.Query) bool {
+	for _, q := range queries {
+		if !q.Match(item.issue, item.dir, item.status) {
+			return false
+		}
+	}
+	return true
+}
@@ -30,6 +30,7 @@ func setupListTest(t *testing.T) (string, func()) {
 		listAssignee = ""
 		listLabel = ""
 		listStatus = ""
+		listFields = []string{}
 	}
 
 	// Change to temp directory
@@ -415,3 +416,66 @@ func TestListEmptyRepo(t *testing.T) {
 		t.Errorf("runList() should handle empty repo, got error: %v", err)
 	}
 }
+
+func TestListCommandFieldFilter(t *testing.T) {
+	_, cleanup := setupListTest(t)
+	defer cleanup()
+
+	issue, _, err := pkg.LoadIssue("001")
+	if err != nil {
+		t.Fatalf("failed to load issue: %v", err)
+	}
+	issue.SetField("priority", "high")
+	if err := pkg.SaveIssue(issue, pkg.OpenDir); err != nil {
+		t.Fatalf("failed to save issue: %v", err)
+	}
+
+	listFields = []string{"priority=high"}
+	if err := runList(nil, []string{}); err != nil {
+		t.Errorf("runList() with --field failed: %v", err)
+	}
+
+	listFields = []string{"priority"}
+	if err := runList(nil, []string{}); err == nil {
+		t.Error("runList() should reject a --field without a value")
+	}
+
+	filters, err := parseFieldFilters([]string{"priority=high"})
+	if err != nil {
+		t.Fatalf("parseFieldFilters() error = %v", err)
+	}
+	if !matchesFieldFilters(issue, filters) {
+		t.Error("issue with priority=high should match")
+	}
+
+	other, _, err := pkg.LoadIssue("003")
+	if err != nil {
+		t.Fatalf("failed to load issue: %v", err)
+	}
+	if matchesFieldFilters(other, filters) {
+		t.Error("issue without priority should not match")
+	}
+}
+
+func TestListCommandQuery(t *testing.T) {
+	_, cleanup := setupListTest(t)
+	defer cleanup()
+	defer func() { listQuery = "" }()
+
+	listQuery = "label:bug AND -label:frontend AND assignee:(alice OR bob)"
+	if err := runList(nil, []string{}); err != nil {
+		t.Errorf("runList() with --query failed: %v", err)
+	}
+
+	// status: reads closed issues without --all
+	listQuery = "status:closed"
+	if err := runList(nil, []string{}); err != nil {
+		t.Errorf("runList() with a status query failed: %v", err)
+	}
+
+	listQuery = "label:bug AND (assignee:alice"
+	err := runList(nil, []string{})
+	if err == nil || !strings.Contains(err.Error(), "unmatched (") {
+		t.Errorf("runList() error = %v, want an unmatched ( error", err)
+	}
+}
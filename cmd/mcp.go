@@ -0,0 +1,434 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/spf13/cobra"
+)
+
+var mcpCmd = &cobra.Command{
+	Use:   "mcp",
+	Short: "Run a Model Context Protocol server for AI agents",
+	Long: `Run a Model Context Protocol (MCP) server on standard input and output,
+so AI agents can work with issues without parsing gi's terminal output.
+
+The server speaks JSON-RPC 2.0, one message per line, and exposes:
+
+  Tools      list_issues, get_issue, create_issue, update_issue, close_issue,
+             search_issues, comment
+  Resources  issue://<id> (the issue's Markdown file)
+
+Register it with an MCP client by running 'gi mcp' in the repository, e.g.:
+
+  {"mcpServers": {"gi": {"command": "gi", "args": ["mcp", "--repo", "/path/to/project"]}}}`,
+	Args: cobra.NoArgs,
+	RunE: runMCP,
+}
+
+func init() {
+	rootCmd.AddCommand(mcpCmd)
+}
+
+func runMCP(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+	return newMCPServer(pkg.DefaultRepository()).serve(os.Stdin, os.Stdout)
+}
+
+// mcpProtocolVersions are the MCP revisions the server speaks, newest first
+var mcpProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// JSON-RPC error codes
+const (
+	rpcParseError     = -32700
+	rpcInvalidRequest = -32600
+	rpcMethodNotFound = -32601
+	rpcInvalidParams  = -32602
+	rpcInternalError  = -32603
+	// mcpResourceNotFound is MCP's code for an unknown resource URI
+	mcpResourceNotFound = -32002
+)
+
+type rpcRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type rpcResponse struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return e.Message
+}
+
+// mcpTool is a tool offered to clients, with the function that runs it
+type mcpTool struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	run func(args json.RawMessage) (interface{}, error)
+}
+
+// mcpServer serves MCP requests against a repository
+type mcpServer struct {
+	*issueAPI
+	tools []mcpTool
+}
+
+func newMCPServer(repo *pkg.Repository) *mcpServer {
+	s := &mcpServer{issueAPI: newIssueAPI(repo)}
+	s.tools = s.defineTools()
+	return s
+}
+
+// serve reads requests from in, one JSON object per line, and writes the
+// responses to out until in is closed
+func (s *mcpServer) serve(in io.Reader, out io.Writer) error {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	enc := json.NewEncoder(out)
+	enc.SetEscapeHTML(false)
+
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		if resp := s.handle(line); resp != nil {
+			if err := enc.Encode(resp); err != nil {
+				return err
+			}
+		}
+	}
+	return scanner.Err()
+}
+
+// handle answers one message; notifications get no response
+func (s *mcpServer) handle(line []byte) *rpcResponse {
+	var req rpcRequest
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &rpcResponse{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{Code: rpcParseError, Message: "parse error: " + err.Error()}}
+	}
+	if len(req.ID) == 0 {
+		return nil
+	}
+
+	resp := &rpcResponse{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{Code: rpcInvalidRequest, Message: "invalid request"}
+		return resp
+	}
+
+	result, err := s.call(req.Method, req.Params)
+	if err != nil {
+		var rerr *rpcError
+		if !errors.As(err, &rerr) {
+			rerr = &rpcError{Code: rpcInternalError, Message: err.Error()}
+		}
+		resp.Error = rerr
+		return resp
+	}
+	resp.Result = result
+	return resp
+}
+
+// call runs a request method
+func (s *mcpServer) call(method string, params json.RawMessage) (interface{}, error) {
+	switch method {
+	case "initialize":
+		var p struct {
+			ProtocolVersion string `json:"protocolVersion"`
+		}
+		if err := decodeParams(params, &p); err != nil {
+			return nil, err
+		}
+		protocol := mcpProtocolVersions[0]
+		if containsKey(mcpProtocolVersions, p.ProtocolVersion) {
+			protocol = p.ProtocolVersion
+		}
+		return map[string]interface{}{
+			"protocolVersion": protocol,
+			"capabilities": map[string]interface{}{
+				"tools":     map[string]interface{}{},
+				"resources": map[string]interface{}{},
+			},
+			"serverInfo": map[string]interface{}{"name": "gi", "version": version},
+			"instructions": "Issues are Markdown files in .issues/ of a git repository. " +
+				"Use list_issues or search_issues to find issues and get_issue or the issue://<id> resource to read one.",
+		}, nil
+	case "ping":
+		return map[string]interface{}{}, nil
+	case "tools/list":
+		return map[string]interface{}{"tools": s.tools}, nil
+	case "tools/call":
+		return s.callTool(params)
+	case "resources/list":
+		return s.listResources()
+	case "resources/templates/list":
+		return map[string]interface{}{
+			"resourceTemplates": []map[string]interface{}{{
+				"uriTemplate": "issue://{id}",
+				"name":        "issue",
+				"description": "An issue's Markdown file, by ID",
+				"mimeType":    "text/markdown",
+			}},
+		}, nil
+	case "resources/read":
+		return s.readResource(params)
+	}
+	return nil, &rpcError{Code: rpcMethodNotFound, Message: fmt.Sprintf("method not found: %s", method)}
+}
+
+// decodeParams decodes request params. Keys the server doesn't use (client
+// capabilities, _meta, ...) are ignored.
+func decodeParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return &rpcError{Code: rpcInvalidParams, Message: "invalid params: " + err.Error()}
+	}
+	return nil
+}
+
+// callTool runs a tool. Failures of the tool itself are reported in the
+// result with isError set, so the model can see them and correct itself.
+func (s *mcpServer) callTool(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	for _, tool := range s.tools {
+		if tool.Name != p.Name {
+			continue
+		}
+		value, err := tool.run(p.Arguments)
+		if err != nil {
+			return map[string]interface{}{
+				"content": []map[string]interface{}{{"type": "text", "text": err.Error()}},
+				"isError": true,
+			}, nil
+		}
+		text, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{
+			"content": []map[string]interface{}{{"type": "text", "text": string(text)}},
+		}, nil
+	}
+	return nil, &rpcError{Code: rpcInvalidParams, Message: fmt.Sprintf("unknown tool: %s", p.Name)}
+}
+
+// Helpers for the JSON schemas of tool arguments
+func schemaObject(required []string, properties map[string]interface{}) map[string]interface{} {
+	schema := map[string]interface{}{"type": "object", "properties": properties, "additionalProperties": false}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func schemaString(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "string", "description": description}
+}
+
+func schemaStrings(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}, "description": description}
+}
+
+func schemaInteger(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "integer", "minimum": 0, "description": description}
+}
+
+func schemaBoolean(description string) map[string]interface{} {
+	return map[string]interface{}{"type": "boolean", "description": description}
+}
+
+func (s *mcpServer) defineTools() []mcpTool {
+	fields := map[string]interface{}{"type": "object", "description": "Custom fields declared in .issues/config.yaml, as name: value (lists as arrays)"}
+	return []mcpTool{
+		{
+			Name:        "list_issues",
+			Description: "List issues, optionally filtered with a query such as 'label:bug assignee:mina updated:>7d'.",
+			InputSchema: schemaObject(nil, map[string]interface{}{
+				"query":  schemaString("Query expression: field:value qualifiers, words, AND/OR/NOT and parentheses"),
+				"status": schemaString("open (default), closed, all, or a workflow state"),
+				"sort":   schemaString("Sort keys such as priority,updated:desc (default id)"),
+				"limit":  schemaInteger("Maximum number of issues to return (default 50, 0 for all)"),
+				"offset": schemaInteger("Number of issues to skip"),
+			}),
+			run: toolFunc(s.listIssues),
+		},
+		{
+			Name:        "get_issue",
+			Description: "Get an issue with its description, tasks, links and comments.",
+			InputSchema: schemaObject([]string{"id"}, map[string]interface{}{
+				"id": schemaString("Issue ID (leading zeros can be left out, and hash and ULID IDs shortened to a unique prefix)"),
+			}),
+			run: toolFunc(s.getIssue),
+		},
+		{
+			Name:        "create_issue",
+			Description: "Create an open issue.",
+			InputSchema: schemaObject([]string{"title"}, map[string]interface{}{
+				"title":     schemaString("Title"),
+				"body":      schemaString("Markdown description (defaults to the repository's issue template)"),
+				"assignee":  schemaString("Assignee"),
+				"labels":    schemaStrings("Labels"),
+				"priority":  schemaString("Priority, e.g. high"),
+				"due":       schemaString("Due date (YYYY-MM-DD)"),
+				"milestone": schemaString("Name of an open milestone"),
+				"parent":    schemaString("ID of the parent issue"),
+				"fields":    fields,
+			}),
+			run: toolFunc(s.createIssue),
+		},
+		{
+			Name:        "update_issue",
+			Description: "Change an issue. Only the given attributes change; an empty string clears an attribute. Moving an issue to a closed state is checked as close_issue checks it.",
+			InputSchema: schemaObject([]string{"id"}, map[string]interface{}{
+				"id":            schemaString("Issue ID"),
+				"title":         schemaString("New title"),
+				"body":          schemaString("New Markdown description"),
+				"assignee":      schemaString("Assignee"),
+				"labels":        schemaStrings("Labels, replacing the current ones"),
+				"add_labels":    schemaStrings("Labels to add"),
+				"remove_labels": schemaStrings("Labels to remove"),
+				"priority":      schemaString("Priority"),
+				"due":           schemaString("Due date (YYYY-MM-DD)"),
+				"milestone":     schemaString("Milestone"),
+				"parent":        schemaString("ID of the parent issue"),
+				"status":        schemaString("Workflow state to move the issue to"),
+				"fields":        fields,
+				"force":         schemaBoolean("Move the issue to a closed state even if it has open child issues"),
+				"strict":        schemaBoolean("Refuse to move the issue to a closed state if it has open blockers or unchecked success criteria"),
+			}),
+			run: toolFunc(s.updateIssue),
+		},
+		{
+			Name:        "close_issue",
+			Description: "Close an issue. Open blockers and unchecked success criteria are reported as warnings, or refuse the close with strict.",
+			InputSchema: schemaObject([]string{"id"}, map[string]interface{}{
+				"id":     schemaString("Issue ID"),
+				"force":  schemaBoolean("Close the issue even if it has open child issues"),
+				"strict": schemaBoolean("Refuse to close the issue if it has open blockers or unchecked success criteria"),
+			}),
+			run: toolFunc(s.closeIssue),
+		},
+		{
+			Name:        "search_issues",
+			Description: "Full-text search over titles, descriptions and comments, best matches first. Words match anywhere in the text, also inside longer words, and \"quoted words\" match a phrase. Qualifiers such as label:bug work too.",
+			InputSchema: schemaObject([]string{"query"}, map[string]interface{}{
+				"query":  schemaString("Search query"),
+				"status": schemaString("open, closed or a workflow state (default: all issues)"),
+				"limit":  schemaInteger("Maximum number of issues to return (default 20, 0 for all)"),
+			}),
+			run: toolFunc(s.searchIssues),
+		},
+		{
+			Name:        "comment",
+			Description: "Add a comment to an issue's discussion thread.",
+			InputSchema: schemaObject([]string{"id", "text"}, map[string]interface{}{
+				"id":     schemaString("Issue ID"),
+				"text":   schemaString("Markdown comment"),
+				"author": schemaString("Comment author (defaults to git user.name)"),
+			}),
+			run: toolFunc(s.comment),
+		},
+	}
+}
+
+// toolFunc adapts an operation to a tool, decoding its arguments
+func toolFunc[A, R any](op func(A) (R, error)) func(json.RawMessage) (interface{}, error) {
+	return func(raw json.RawMessage) (interface{}, error) {
+		var args A
+		if err := decodeArgs(raw, &args); err != nil {
+			return nil, err
+		}
+		return op(args)
+	}
+}
+
+// issueURI is the resource URI of an issue
+func issueURI(id string) string {
+	return "issue://" + id
+}
+
+func (s *mcpServer) listResources() (interface{}, error) {
+	cfg, err := s.repo.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.issues("all", cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	resources := []map[string]interface{}{}
+	for _, item := range items {
+		resources = append(resources, map[string]interface{}{
+			"uri":         issueURI(item.issue.ID),
+			"name":        item.issue.ID,
+			"title":       fmt.Sprintf("#%s %s", item.issue.ID, item.issue.Title),
+			"description": fmt.Sprintf("%s issue", item.status),
+			"mimeType":    "text/markdown",
+		})
+	}
+	return map[string]interface{}{"resources": resources}, nil
+}
+
+func (s *mcpServer) readResource(params json.RawMessage) (interface{}, error) {
+	var p struct {
+		URI string `json:"uri"`
+	}
+	if err := decodeParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	id, ok := strings.CutPrefix(p.URI, "issue://")
+	if !ok || id == "" {
+		return nil, &rpcError{Code: mcpResourceNotFound, Message: fmt.Sprintf("resource not found: %s", p.URI)}
+	}
+	issue, _, err := s.repo.LoadIssue(id)
+	if err != nil {
+		return nil, &rpcError{Code: mcpResourceNotFound, Message: fmt.Sprintf("resource not found: %s (%v)", p.URI, err)}
+	}
+	content, err := pkg.SerializeIssue(issue)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"contents": []map[string]interface{}{{
+			"uri":      p.URI,
+			"mimeType": "text/markdown",
+			"text":     content,
+		}},
+	}, nil
+}
@@ -0,0 +1,286 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+// mcpClient drives an MCP server over pipes, the way an agent does over stdio
+type mcpClient struct {
+	t      *testing.T
+	in     *io.PipeWriter
+	out    *bufio.Reader
+	nextID int
+	done   chan error
+}
+
+func startMCPServer(t *testing.T) *mcpClient {
+	t.Helper()
+
+	server := newMCPServer(pkg.DefaultRepository())
+	server.branch = func() string { return "main" }
+	server.author = func() string { return "agent" }
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	c := &mcpClient{t: t, in: inW, out: bufio.NewReader(outR), done: make(chan error, 1)}
+	go func() {
+		err := server.serve(inR, outW)
+		_ = outW.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+func (c *mcpClient) send(line string) {
+	c.t.Helper()
+	if _, err := io.WriteString(c.in, line+"\n"); err != nil {
+		c.t.Fatalf("write: %v", err)
+	}
+}
+
+// receive reads one response
+func (c *mcpClient) receive() rpcTestResponse {
+	c.t.Helper()
+	line, err := c.out.ReadBytes('\n')
+	if err != nil {
+		c.t.Fatalf("read: %v", err)
+	}
+	var resp rpcTestResponse
+	if err := json.Unmarshal(line, &resp); err != nil {
+		c.t.Fatalf("invalid response %s: %v", line, err)
+	}
+	return resp
+}
+
+// request sends a request and returns its response
+func (c *mcpClient) request(method string, params interface{}) rpcTestResponse {
+	c.t.Helper()
+	c.nextID++
+	data, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": c.nextID, "method": method, "params": params})
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.send(string(data))
+
+	resp := c.receive()
+	if string(resp.ID) != fmt.Sprint(c.nextID) {
+		c.t.Fatalf("response id = %s, want %d", resp.ID, c.nextID)
+	}
+	return resp
+}
+
+// tool calls a tool and decodes the JSON it returns into v
+func (c *mcpClient) tool(name string, args map[string]interface{}, v interface{}) {
+	c.t.Helper()
+	text, isError := c.toolText(name, args)
+	if isError {
+		c.t.Fatalf("%s failed: %s", name, text)
+	}
+	if err := json.Unmarshal([]byte(text), v); err != nil {
+		c.t.Fatalf("%s returned %s: %v", name, text, err)
+	}
+}
+
+// toolText calls a tool and returns its text content and whether it failed
+func (c *mcpClient) toolText(name string, args map[string]interface{}) (string, bool) {
+	c.t.Helper()
+	resp := c.request("tools/call", map[string]interface{}{"name": name, "arguments": args})
+	if resp.Error != nil {
+		c.t.Fatalf("%s: %s", name, resp.Error.Message)
+	}
+	var result struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+		IsError bool `json:"isError"`
+	}
+	if err := json.Unmarshal(resp.Result, &result); err != nil || len(result.Content) != 1 {
+		c.t.Fatalf("%s: unexpected result %s", name, resp.Result)
+	}
+	return result.Content[0].Text, result.IsError
+}
+
+func (c *mcpClient) close() {
+	c.t.Helper()
+	_ = c.in.Close()
+	if err := <-c.done; err != nil {
+		c.t.Errorf("serve() error = %v", err)
+	}
+}
+
+type rpcTestResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+}
+
+func TestMCPServer(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	createLabels = []string{"bug"}
+	if err := runCreate(nil, []string{"Fix Redis connection timeout"}); err != nil {
+		t.Fatal(err)
+	}
+	createLabels = []string{}
+	if err := runCreate(nil, []string{"Add user authentication"}); err != nil {
+		t.Fatal(err)
+	}
+	setCriteria(t, "002", "Sessions expire")
+
+	c := startMCPServer(t)
+	defer c.close()
+
+	// Handshake
+	resp := c.request("initialize", map[string]interface{}{
+		"protocolVersion": "2025-03-26",
+		"capabilities":    map[string]interface{}{},
+		"clientInfo":      map[string]interface{}{"name": "test", "version": "1.0"},
+	})
+	var init struct {
+		ProtocolVersion string `json:"protocolVersion"`
+		ServerInfo      struct {
+			Name string `json:"name"`
+		} `json:"serverInfo"`
+	}
+	if err := json.Unmarshal(resp.Result, &init); err != nil || init.ProtocolVersion != "2025-03-26" || init.ServerInfo.Name != "gi" {
+		t.Errorf("initialize = %s, %v", resp.Result, err)
+	}
+	c.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`) // no response
+
+	resp = c.request("tools/list", nil)
+	var tools struct {
+		Tools []struct {
+			Name        string                 `json:"name"`
+			InputSchema map[string]interface{} `json:"inputSchema"`
+		} `json:"tools"`
+	}
+	if err := json.Unmarshal(resp.Result, &tools); err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range tools.Tools {
+		names = append(names, tool.Name)
+		if tool.InputSchema["type"] != "object" {
+			t.Errorf("tool %s has no object input schema", tool.Name)
+		}
+	}
+	if got := strings.Join(names, ","); got != "list_issues,get_issue,create_issue,update_issue,close_issue,search_issues,comment" {
+		t.Errorf("tools = %s", got)
+	}
+
+	// Tools
+	var list struct {
+		Issues []issueSummary `json:"issues"`
+		Total  int            `json:"total"`
+	}
+	c.tool("list_issues", map[string]interface{}{"query": "label:bug"}, &list)
+	if list.Total != 1 || list.Issues[0].ID != "001" {
+		t.Errorf("list_issues(label:bug) = %+v", list)
+	}
+
+	var created pkg.IssueRecord
+	c.tool("create_issue", map[string]interface{}{
+		"title":    "Rotate API keys",
+		"body":     "Keys leaked in CI logs.",
+		"labels":   []string{"security"},
+		"priority": "high",
+	}, &created)
+	if created.ID != "003" || created.Body != "Keys leaked in CI logs." || created.Fields["priority"] != "high" {
+		t.Errorf("create_issue = %+v", created)
+	}
+
+	var updated pkg.IssueRecord
+	c.tool("update_issue", map[string]interface{}{
+		"id":         "003",
+		"assignee":   "mina",
+		"add_labels": []string{"ops"},
+		"priority":   "",
+		"status":     "closed",
+	}, &updated)
+	if updated.Assignee != "mina" || strings.Join(updated.Labels, ",") != "security,ops" || updated.Fields["priority"] != nil || !updated.Closed {
+		t.Errorf("update_issue = %+v", updated)
+	}
+
+	var commented struct {
+		ID      string      `json:"id"`
+		Comment pkg.Comment `json:"comment"`
+	}
+	c.tool("comment", map[string]interface{}{"id": "001", "text": "Reproduced on staging"}, &commented)
+	if commented.Comment.Author != "agent" {
+		t.Errorf("comment author = %q, want agent", commented.Comment.Author)
+	}
+
+	var got pkg.IssueRecord
+	c.tool("get_issue", map[string]interface{}{"id": "001"}, &got)
+	if got.Title != "Fix Redis connection timeout" || len(got.Comments) != 1 {
+		t.Errorf("get_issue = %+v", got)
+	}
+
+	var found struct {
+		Issues []issueSummary `json:"issues"`
+	}
+	c.tool("search_issues", map[string]interface{}{"query": "staging"}, &found)
+	if len(found.Issues) != 1 || found.Issues[0].ID != "001" || found.Issues[0].Score <= 0 {
+		t.Errorf("search_issues(staging) = %+v", found)
+	}
+
+	if text, isError := c.toolText("close_issue", map[string]interface{}{"id": "002", "strict": true}); !isError || !strings.Contains(text, "unchecked success criteria") {
+		t.Errorf("close_issue with strict = %q, isError %v", text, isError)
+	}
+	var closed struct {
+		Issue    pkg.IssueRecord `json:"issue"`
+		Warnings []string        `json:"warnings"`
+	}
+	c.tool("close_issue", map[string]interface{}{"id": "002"}, &closed)
+	if !closed.Issue.Closed {
+		t.Errorf("close_issue = %+v", closed)
+	}
+
+	// Tool failures are results the model can read
+	if text, isError := c.toolText("close_issue", map[string]interface{}{"id": "002"}); !isError || !strings.Contains(text, "already closed") {
+		t.Errorf("closing a closed issue = %q, isError %v", text, isError)
+	}
+	if text, isError := c.toolText("get_issue", map[string]interface{}{"id": "001", "bogus": true}); !isError || !strings.Contains(text, "bogus") {
+		t.Errorf("unknown argument = %q, isError %v", text, isError)
+	}
+
+	// Resources
+	resp = c.request("resources/list", nil)
+	if !strings.Contains(string(resp.Result), `"uri":"issue://003"`) {
+		t.Errorf("resources/list = %s", resp.Result)
+	}
+	resp = c.request("resources/read", map[string]interface{}{"uri": "issue://001"})
+	var read struct {
+		Contents []struct {
+			URI  string `json:"uri"`
+			Text string `json:"text"`
+		} `json:"contents"`
+	}
+	if err := json.Unmarshal(resp.Result, &read); err != nil || len(read.Contents) != 1 || !strings.Contains(read.Contents[0].Text, "# Fix Redis connection timeout") {
+		t.Errorf("resources/read = %s, %v", resp.Result, err)
+	}
+	if resp = c.request("resources/read", map[string]interface{}{"uri": "issue://999"}); resp.Error == nil || resp.Error.Code != mcpResourceNotFound {
+		t.Errorf("reading a missing issue = %+v", resp)
+	}
+
+	// Protocol errors
+	if resp = c.request("tools/call", map[string]interface{}{"name": "nope"}); resp.Error == nil || resp.Error.Code != rpcInvalidParams {
+		t.Errorf("unknown tool = %+v", resp)
+	}
+	if resp = c.request("nope", nil); resp.Error == nil || resp.Error.Code != rpcMethodNotFound {
+		t.Errorf("unknown method = %+v", resp)
+	}
+	c.send("{not json")
+	if resp = c.receive(); resp.Error == nil || resp.Error.Code != rpcParseError || string(resp.ID) != "null" {
+		t.Errorf("malformed message = %+v", resp)
+	}
+}
@@ -0,0 +1,274 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	milestoneStart       string
+	milestoneDue         string
+	milestoneDescription string
+	milestoneAll         bool
+	milestoneCommit      bool
+)
+
+var milestoneCmd = &cobra.Command{
+	Use:   "milestone",
+	Short: "Manage milestones",
+	Long: `Manage milestones, such as releases or sprints.
+
+Milestones are stored in .issues/milestones/, one Markdown file each. Issues
+join a milestone through the milestone field in their frontmatter, set with
+'gi create --milestone' or by editing the issue.
+
+Examples:
+  gi milestone create v1.0 --start 2026-10-01 --due 2026-11-15
+  gi milestone list
+  gi milestone show v1.0
+  gi milestone close v1.0`,
+}
+
+var milestoneCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a milestone",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMilestoneCreate,
+}
+
+var milestoneListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List milestones with their progress",
+	Args:  cobra.NoArgs,
+	RunE:  runMilestoneList,
+}
+
+var milestoneShowCmd = &cobra.Command{
+	Use:   "show <name>",
+	Short: "Show a milestone and its issues",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runMilestoneShow,
+}
+
+var milestoneCloseCmd = &cobra.Command{
+	Use:   "close <name>",
+	Short: "Close a milestone",
+	Long: `Close a milestone. Its issues are left as they are, and new issues can no
+longer be created in it.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runMilestoneClose,
+}
+
+func init() {
+	rootCmd.AddCommand(milestoneCmd)
+	milestoneCmd.AddCommand(milestoneCreateCmd, milestoneListCmd, milestoneShowCmd, milestoneCloseCmd)
+
+	milestoneCreateCmd.Flags().StringVar(&milestoneStart, "start", "", "Start date (YYYY-MM-DD)")
+	milestoneCreateCmd.Flags().StringVar(&milestoneDue, "due", "", "Due date (YYYY-MM-DD)")
+	milestoneCreateCmd.Flags().StringVarP(&milestoneDescription, "description", "d", "", "Description of the milestone")
+	milestoneCreateCmd.Flags().BoolVarP(&milestoneCommit, "commit", "c", false, "Auto-commit the change to git")
+	milestoneListCmd.Flags().BoolVarP(&milestoneAll, "all", "a", false, "Include closed milestones")
+	milestoneCloseCmd.Flags().BoolVarP(&milestoneCommit, "commit", "c", false, "Auto-commit the change to git")
+}
+
+func runMilestoneCreate(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	milestone := &pkg.Milestone{
+		Name:        strings.TrimSpace(args[0]),
+		Start:       milestoneStart,
+		Due:         milestoneDue,
+		Description: strings.TrimSpace(milestoneDescription),
+	}
+	if err := pkg.CreateMilestone(milestone); err != nil {
+		return err
+	}
+
+	green := color.New(color.FgGreen, color.Bold)
+	fmt.Print("✓ Created milestone ")
+	_, _ = green.Println(milestone.Name)
+
+	if milestoneCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Create milestone %s", milestone.Name)); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
+
+func runMilestoneList(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	milestones, err := pkg.ListMilestones()
+	if err != nil {
+		return err
+	}
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Status", "Start", "Due", "Open", "Closed", "Complete"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+	table.SetAutoWrapText(false)
+
+	shown := 0
+	for _, milestone := range milestones {
+		if milestone.Closed() && !milestoneAll {
+			continue
+		}
+		summary, err := pkg.GetMilestoneSummary(milestone.Name)
+		if err != nil {
+			return err
+		}
+		table.Append([]string{
+			milestone.Name,
+			milestoneStatus(milestone),
+			orDash(milestone.Start),
+			orDash(milestone.Due),
+			strconv.Itoa(len(summary.Open)),
+			strconv.Itoa(len(summary.Closed)),
+			fmt.Sprintf("%d%%", summary.PercentComplete()),
+		})
+		shown++
+	}
+
+	if shown == 0 {
+		fmt.Println("No milestones found.")
+		return nil
+	}
+	table.Render()
+	fmt.Printf("\nTotal: %d milestone(s)\n", shown)
+
+	return nil
+}
+
+func runMilestoneShow(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	milestone, err := pkg.LoadMilestone(args[0])
+	if err != nil {
+		return err
+	}
+	summary, err := pkg.GetMilestoneSummary(milestone.Name)
+	if err != nil {
+		return err
+	}
+
+	bold := color.New(color.Bold).SprintFunc()
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	fmt.Printf("%s\n", bold(milestone.Name))
+	fmt.Printf("Status: %s\n", milestoneStatus(milestone))
+	if milestone.Start != "" {
+		fmt.Printf("Start: %s\n", milestone.Start)
+	}
+	if milestone.Due != "" {
+		fmt.Printf("Due: %s\n", milestone.Due)
+	}
+	fmt.Printf("Progress: %s %d/%d closed (%d%%)\n",
+		progressBar(summary.PercentComplete(), 20), len(summary.Closed), summary.Total(), summary.PercentComplete())
+
+	if milestone.Description != "" {
+		fmt.Printf("\n%s\n", milestone.Description)
+	}
+
+	sections := []struct {
+		heading string
+		issues  []*pkg.Issue
+	}{
+		{fmt.Sprintf("Open (%d)", len(summary.Open)), summary.Open},
+		{fmt.Sprintf("Closed (%d)", len(summary.Closed)), summary.Closed},
+	}
+	for _, section := range sections {
+		if len(section.issues) == 0 {
+			continue
+		}
+		fmt.Printf("\n%s\n", bold(section.heading))
+		for _, issue := range section.issues {
+			line := fmt.Sprintf("  #%s %s", issue.ID, issue.Title)
+			if issue.Assignee != "" {
+				line += gray(" @" + issue.Assignee)
+			}
+			fmt.Println(line)
+		}
+	}
+	if summary.Total() == 0 {
+		fmt.Println("\nNo issues in this milestone yet.")
+	}
+
+	return nil
+}
+
+func runMilestoneClose(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	milestone, err := pkg.LoadMilestone(args[0])
+	if err != nil {
+		return err
+	}
+	if err := pkg.CloseMilestone(milestone.Name); err != nil {
+		return err
+	}
+	fmt.Printf("✓ Closed milestone %s\n", milestone.Name)
+
+	summary, err := pkg.GetMilestoneSummary(milestone.Name)
+	if err != nil {
+		return err
+	}
+	if n := len(summary.Open); n > 0 {
+		_, _ = color.New(color.FgYellow).Printf("! %d issue(s) in the milestone are still open\n", n)
+	}
+
+	if milestoneCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Close milestone %s", milestone.Name)); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
+
+// milestoneStatus colors a milestone's status like an issue's
+func milestoneStatus(m *pkg.Milestone) string {
+	if m.Closed() {
+		return color.New(color.FgRed).Sprint(m.Status)
+	}
+	return color.New(color.FgGreen).Sprint(m.Status)
+}
+
+// progressBar draws a bar of width cells, filled to percent
+func progressBar(percent, width int) string {
+	filled := percent * width / 100
+	return "[" + strings.Repeat("#", filled) + strings.Repeat("-", width-filled) + "]"
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestRunMilestone(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	milestoneStart = "2026-10-01"
+	milestoneDue = "2026-11-01"
+	if err := runMilestoneCreate(nil, []string{"v1.0"}); err != nil {
+		t.Fatalf("runMilestoneCreate() failed: %v", err)
+	}
+	if err := runMilestoneCreate(nil, []string{"v1.0"}); err == nil {
+		t.Error("runMilestoneCreate() should reject a duplicate milestone")
+	}
+
+	createMilestone = "v1.0"
+	for _, title := range []string{"Release notes", "Changelog"} {
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatalf("runCreate() failed: %v", err)
+		}
+	}
+	createMilestone = "v9"
+	if err := runCreate(nil, []string{"Unknown milestone"}); err == nil {
+		t.Error("runCreate() with a missing milestone should fail")
+	}
+	createMilestone = ""
+
+	issue, _, _ := pkg.LoadIssue("001")
+	if issue.Milestone() != "v1.0" {
+		t.Errorf("Milestone() = %q, want v1.0", issue.Milestone())
+	}
+	if err := pkg.MoveIssue("001", pkg.OpenDir, pkg.ClosedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := runMilestoneList(nil, nil); err != nil {
+		t.Errorf("runMilestoneList() failed: %v", err)
+	}
+	if err := runMilestoneShow(nil, []string{"v1.0"}); err != nil {
+		t.Errorf("runMilestoneShow() failed: %v", err)
+	}
+	summary, _ := pkg.GetMilestoneSummary("v1.0")
+	if summary.PercentComplete() != 50 {
+		t.Errorf("PercentComplete() = %d, want 50", summary.PercentComplete())
+	}
+
+	if err := runMilestoneClose(nil, []string{"v1.0"}); err != nil {
+		t.Fatalf("runMilestoneClose() failed: %v", err)
+	}
+	createMilestone = "v1.0"
+	if err := runCreate(nil, []string{"Late addition"}); err == nil {
+		t.Error("runCreate() in a closed milestone should fail")
+	}
+}
@@ -12,9 +12,12 @@ var openCommit bool
 var openCmd = &cobra.Command{
 	Use:   "open <issue-id>",
 	Short: "Reopen a closed issue",
-	Long:  `Reopen a closed issue by moving it from .issues/closed/ to .issues/open/`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runOpen,
+	Long: `Reopen a closed issue by moving it from .issues/closed/ to .issues/open/
+
+Reopening is a move to the built-in open state, so when config.yaml declares
+workflow transitions the issue's state must allow it (see 'gi status').`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOpen,
 }
 
 func init() {
@@ -26,10 +29,11 @@ func runOpen(cmd *cobra.Command, args []string) error {
 	issueID := args[0]
 
 	// Load the issue to check its status
-	_, currentDir, err := pkg.LoadIssue(issueID)
+	issue, currentDir, err := pkg.LoadIssue(issueID)
 	if err != nil {
 		return fmt.Errorf("failed to load issue: %w", err)
 	}
+	issueID = issue.ID // expand a short ID prefix
 
 	// Check if issue is already open
 	if currentDir == pkg.OpenDir {
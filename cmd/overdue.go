@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	overdueDays   int
+	overdueOutput outputOptions
+)
+
+var overdueCmd = &cobra.Command{
+	Use:   "overdue",
+	Short: "List open issues past their due date",
+	Long: `List open issues whose due date has passed, most overdue first.
+
+With --days, issues due within that many days are listed too, highlighted in
+yellow rather than red.
+
+Examples:
+  gi overdue
+  gi overdue --days 7        # Also show what's due in the next week
+  gi overdue --format json`,
+	Args: cobra.NoArgs,
+	RunE: runOverdue,
+}
+
+func init() {
+	rootCmd.AddCommand(overdueCmd)
+	overdueCmd.Flags().IntVar(&overdueDays, "days", 0, "Also list issues due within this many days")
+	addOutputFlags(overdueCmd, &overdueOutput)
+}
+
+func runOverdue(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	if err := overdueOutput.validate(); err != nil {
+		return err
+	}
+	if overdueDays < 0 {
+		return fmt.Errorf("--days can't be negative")
+	}
+
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	due := dueIssues(collectIssues([]string{pkg.OpenDir}, &cfg.Workflow), today, overdueDays)
+	sortIssues(due, []sortKey{{name: "due"}, {name: "priority"}, {name: "id"}}, cfg)
+
+	if !overdueOutput.tabular() {
+		return writeIssues(os.Stdout, due, cfg, overdueOutput)
+	}
+
+	if len(due) == 0 {
+		fmt.Println("No overdue issues.")
+		return nil
+	}
+
+	red := color.New(color.FgRed)
+	yellow := color.New(color.FgYellow)
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"ID", "Title", "Due", "When", "Priority", "Assignee"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+	table.SetAutoWrapText(false)
+
+	overdue := 0
+	for _, item := range due {
+		date, _ := item.issue.Due()
+		days := int(today.Sub(date).Hours() / 24)
+
+		var when string
+		switch {
+		case days > 0:
+			overdue++
+			when = red.Sprintf("%d day(s) overdue", days)
+		case days == 0:
+			when = yellow.Sprint("due today")
+		default:
+			when = yellow.Sprintf("due in %d day(s)", -days)
+		}
+
+		priority := item.issue.Priority()
+		if priority == "" {
+			priority = "-"
+		}
+		assignee := item.issue.Assignee
+		if assignee == "" {
+			assignee = "-"
+		}
+
+		table.Append([]string{
+			"#" + item.issue.ID,
+			item.issue.Title,
+			date.Format(pkg.DateLayout),
+			when,
+			priority,
+			assignee,
+		})
+	}
+	table.Render()
+
+	fmt.Printf("\nOverdue: %d issue(s)", overdue)
+	if overdueDays > 0 {
+		fmt.Printf(", due within %d day(s): %d", overdueDays, len(due)-overdue)
+	}
+	fmt.Println()
+
+	return nil
+}
+
+// dueIssues selects the issues due before today, or within days of it
+func dueIssues(items []issueWithStatus, today time.Time, days int) []issueWithStatus {
+	horizon := today.AddDate(0, 0, days)
+
+	var due []issueWithStatus
+	for _, item := range items {
+		if date, ok := item.issue.Due(); ok && (date.Before(today) || days > 0 && !date.After(horizon)) {
+			due = append(due, item)
+		}
+	}
+	return due
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestCreateWithPriorityAndDue(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	createPriority = "high"
+	createDue = "2026-11-01"
+	if err := runCreate(nil, []string{"Renew certificate"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+	issue, _, _ := pkg.LoadIssue("001")
+	if issue.Priority() != "high" {
+		t.Errorf("Priority() = %q", issue.Priority())
+	}
+	if _, ok := issue.Due(); !ok {
+		t.Error("Due() not set")
+	}
+
+	createPriority = "urgent"
+	if err := runCreate(nil, []string{"Bad priority"}); err == nil {
+		t.Error("runCreate() with an unknown priority should fail")
+	}
+	createPriority = ""
+	createDue = "tomorrow"
+	if err := runCreate(nil, []string{"Bad due"}); err == nil {
+		t.Error("runCreate() with an invalid due date should fail")
+	}
+
+	// Invalid input doesn't consume an ID
+	createDue = ""
+	if err := runCreate(nil, []string{"Next"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := pkg.LoadIssue("002"); err != nil {
+		t.Errorf("next issue should be 002: %v", err)
+	}
+}
+
+func TestSortIssues(t *testing.T) {
+	cfg := &pkg.Config{}
+	item := func(id, priority, due string) issueWithStatus {
+		issue := &pkg.Issue{ID: id}
+		if priority != "" {
+			issue.SetField(pkg.PriorityField, priority)
+		}
+		if due != "" {
+			issue.SetField(pkg.DueField, due)
+		}
+		return issueWithStatus{issue: issue, dir: pkg.OpenDir}
+	}
+	items := []issueWithStatus{
+		item("001", "low", "2026-10-01"),
+		item("002", "", "2026-09-01"),
+		item("003", "high", "2026-12-01"),
+		item("004", "high", "2026-11-01"),
+		item("005", "", ""),
+	}
+
+	keys, err := parseSortKeys("priority,due", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sortIssues(items, keys, cfg)
+
+	var got string
+	for _, it := range items {
+		got += it.issue.ID + " "
+	}
+	if got != "004 003 001 002 005 " {
+		t.Errorf("sort by priority,due = %s", got)
+	}
+
+	if _, err := parseSortKeys("priority,size", cfg); err == nil {
+		t.Error("parseSortKeys() should reject unknown keys")
+	}
+}
+
+func TestRunOverdue(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	today := time.Now()
+	for title, due := range map[string]string{
+		"Late":   today.AddDate(0, 0, -3).Format(pkg.DateLayout),
+		"Soon":   today.AddDate(0, 0, 2).Format(pkg.DateLayout),
+		"Closed": today.AddDate(0, 0, -10).Format(pkg.DateLayout),
+	} {
+		createDue = due
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createDue = ""
+
+	// Close the issue titled Closed
+	issues, _ := pkg.ListIssues(pkg.OpenDir)
+	for _, issue := range issues {
+		if issue.Title == "Closed" {
+			closeForce = true
+			if err := runClose(nil, []string{issue.ID}); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	now := time.Now()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	open := collectIssues([]string{pkg.OpenDir}, &pkg.Workflow{})
+	if due := dueIssues(open, midnight, 0); len(due) != 1 || due[0].issue.Title != "Late" {
+		t.Errorf("dueIssues() = %v, want only Late", due)
+	}
+	if due := dueIssues(open, midnight, 7); len(due) != 2 {
+		t.Errorf("dueIssues(7 days) = %v, want Late and Soon", due)
+	}
+
+	if err := runOverdue(nil, []string{}); err != nil {
+		t.Errorf("runOverdue() failed: %v", err)
+	}
+	overdueDays = 7
+	if err := runOverdue(nil, []string{}); err != nil {
+		t.Errorf("runOverdue(--days) failed: %v", err)
+	}
+	overdueDays = -1
+	if err := runOverdue(nil, []string{}); err == nil {
+		t.Error("runOverdue() with negative --days should fail")
+	}
+
+	listSort = "due"
+	if err := runList(nil, []string{}); err != nil {
+		t.Errorf("runList(--sort due) failed: %v", err)
+	}
+	listSort = "bogus"
+	if err := runList(nil, []string{}); err == nil {
+		t.Error("runList() with an invalid sort key should fail")
+	}
+}
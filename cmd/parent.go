@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	parentClear  bool
+	parentCommit bool
+)
+
+var parentCmd = &cobra.Command{
+	Use:   "parent <issue-id> [parent-id]",
+	Short: "Show or set the parent of an issue",
+	Long: `Show or set the parent of an issue.
+
+Child issues are listed by 'gi show' on their parent, with how many of them
+are closed, and 'gi list --tree' shows them indented under it. An issue can't
+become a child of itself or of one of its own children.
+
+Examples:
+  gi parent 004          # Show the parent of 004
+  gi parent 004 001      # Make 004 a child of epic 001
+  gi parent 004 --clear  # Remove the parent`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runParent,
+}
+
+func init() {
+	rootCmd.AddCommand(parentCmd)
+	parentCmd.Flags().BoolVar(&parentClear, "clear", false, "Remove the issue's parent")
+	parentCmd.Flags().BoolVarP(&parentCommit, "commit", "c", false, "Auto-commit the change to git")
+}
+
+func runParent(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	issue, _, err := pkg.LoadIssue(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to load issue: %w", err)
+	}
+
+	if len(args) == 2 && parentClear {
+		return fmt.Errorf("--clear can't be combined with a parent ID")
+	}
+
+	// Without a new parent, show the current one
+	if len(args) == 1 && !parentClear {
+		if issue.Parent == "" {
+			fmt.Printf("Issue #%s has no parent\n", issue.ID)
+		} else {
+			fmt.Printf("#%s\n", issue.Parent)
+		}
+		return nil
+	}
+
+	parent := ""
+	if len(args) == 2 {
+		parentIssue, _, err := pkg.LoadIssue(args[1])
+		if err != nil {
+			return fmt.Errorf("failed to load parent issue: %w", err)
+		}
+		parent = parentIssue.ID
+	}
+
+	if err := pkg.SetParent(issue.ID, parent); err != nil {
+		return err
+	}
+
+	var message string
+	if parent == "" {
+		message = fmt.Sprintf("Remove parent of issue #%s", issue.ID)
+		fmt.Printf("✓ Removed the parent of issue #%s\n", issue.ID)
+	} else {
+		message = fmt.Sprintf("Set parent of issue #%s to #%s", issue.ID, parent)
+		fmt.Printf("✓ Issue #%s is now a child of #%s\n", issue.ID, parent)
+	}
+
+	if parentCommit {
+		if err := gitCommitChanges(message); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
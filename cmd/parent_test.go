@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestParentAndTree(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Checkout epic"}); err != nil {
+		t.Fatal(err)
+	}
+	createParent = "001"
+	if err := runCreate(nil, []string{"Cart"}); err != nil {
+		t.Fatalf("runCreate(--parent) failed: %v", err)
+	}
+	createParent = "404"
+	if err := runCreate(nil, []string{"Orphan"}); err == nil {
+		t.Error("runCreate() with a missing parent should fail")
+	}
+	createParent = ""
+	if err := runCreate(nil, []string{"Payment"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runParent(nil, []string{"003", "001"}); err != nil {
+		t.Fatalf("runParent() failed: %v", err)
+	}
+	if err := runParent(nil, []string{"001", "003"}); err == nil {
+		t.Error("runParent() creating a cycle should fail")
+	}
+
+	issue, _, _ := pkg.LoadIssue("003")
+	if issue.Parent != "001" {
+		t.Errorf("Parent = %q, want 001", issue.Parent)
+	}
+
+	listTree = true
+	if err := runList(nil, []string{}); err != nil {
+		t.Errorf("runList(--tree) failed: %v", err)
+	}
+	if err := runShow(nil, []string{"001"}); err != nil {
+		t.Errorf("runShow() with children failed: %v", err)
+	}
+
+	parentClear = true
+	if err := runParent(nil, []string{"003"}); err != nil {
+		t.Fatalf("runParent(--clear) failed: %v", err)
+	}
+	issue, _, _ = pkg.LoadIssue("003")
+	if issue.Parent != "" {
+		t.Errorf("Parent = %q after --clear", issue.Parent)
+	}
+}
+
+func TestTreeOrder(t *testing.T) {
+	item := func(id, parent string) issueWithStatus {
+		return issueWithStatus{issue: &pkg.Issue{ID: id, Title: "T" + id, Parent: parent}}
+	}
+	items := []issueWithStatus{
+		item("001", ""), item("002", "001"), item("003", "001"),
+		item("004", "003"), item("005", "404"), item("006", "007"), item("007", "006"),
+	}
+
+	var got []string
+	for _, it := range treeOrder(items) {
+		got = append(got, it.branch+it.issue.ID)
+	}
+	want := []string{"001", "├─ 002", "└─ 003", "   └─ 004", "005", "006", "└─ 007"}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("treeOrder() = %q, want %q", got, want)
+	}
+}
+
+func TestCloseParentWithOpenChildren(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Epic"}); err != nil {
+		t.Fatal(err)
+	}
+	createParent = "001"
+	if err := runCreate(nil, []string{"Child"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a terminal to prompt on, closing needs --force
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = stdin
+		_ = r.Close()
+	}()
+
+	err = runClose(nil, []string{"001"})
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("runClose() error = %v, want a hint about --force", err)
+	}
+
+	closeForce = true
+	if err := runClose(nil, []string{"001"}); err != nil {
+		t.Fatalf("runClose(--force) failed: %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	renumberCommit bool
+	renumberDryRun bool
+	renumberForce  bool
+)
+
+var renumberCmd = &cobra.Command{
+	Use:   "renumber",
+	Short: "Repair duplicate issue IDs and finalize provisional ones",
+	Long: `Repair duplicate issue IDs and finalize provisional ones.
+
+When two branches each create an issue with the same ID, merging them leaves
+two files such as 007-login.md and 007-export.md, and only one of them can be
+reached by ID. 'gi renumber' finds such collisions across open/ and closed/,
+keeps the ID on the oldest issue and gives the others the next free numbers
+from .issues/.counter.
+
+With 'ids: {strategy: provisional}' in .issues/config.yaml, issues created on
+feature branches get branch-scoped IDs such as login_fix.1; 'gi renumber'
+gives them their final numbers after merging.
+
+Renumbered files are renamed, their id field is rewritten and #ID references in
+other issues are updated. A reference to a duplicated ID from an issue last
+updated before the renumbered issue was created means the issue keeping the
+ID and is left alone; for any other, gi asks which issue it means, or fails
+when standard input isn't a terminal. --force leaves those references
+unchanged for you to check.
+
+Examples:
+  gi renumber --dry-run  # Show what would change
+  gi renumber            # Renumber issues
+  gi renumber --commit   # Renumber and commit the result`,
+	Args: cobra.NoArgs,
+	RunE: runRenumber,
+}
+
+func init() {
+	rootCmd.AddCommand(renumberCmd)
+	renumberCmd.Flags().BoolVarP(&renumberCommit, "commit", "c", false, "Auto-commit the change to git")
+	renumberCmd.Flags().BoolVar(&renumberDryRun, "dry-run", false, "Show the new IDs without changing any files")
+	renumberCmd.Flags().BoolVarP(&renumberForce, "force", "f", false, "Leave ambiguous references to duplicate IDs unchanged instead of asking")
+}
+
+func runRenumber(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+	if renumberDryRun && renumberCommit {
+		return fmt.Errorf("--dry-run and --commit can't be used together")
+	}
+
+	// Find the ambiguous references first, so the questions aren't asked while
+	// holding the lock other gi commands wait for
+	changes, err := pkg.Renumber(pkg.RenumberOptions{DryRun: true})
+	if err != nil {
+		return fmt.Errorf("failed to renumber issues: %w", err)
+	}
+
+	if !renumberDryRun {
+		meant, err := resolveReferences(changes)
+		if err != nil {
+			return err
+		}
+		changes, err = pkg.Renumber(pkg.RenumberOptions{Resolve: func(change pkg.Renumbering, referrer string) bool {
+			return meant[change.OldName+" "+referrer]
+		}})
+		if err != nil {
+			return fmt.Errorf("failed to renumber issues: %w", err)
+		}
+	}
+
+	if len(changes) == 0 {
+		fmt.Println("No duplicate or provisional IDs to renumber")
+		return nil
+	}
+
+	renderRenumberTable(changes)
+	fmt.Println()
+
+	// References to a duplicated ID left for the user to check
+	for _, change := range changes {
+		var left []string
+		for _, id := range change.Ambiguous {
+			if !slices.Contains(change.References, id) {
+				left = append(left, id)
+			}
+		}
+		if len(left) == 0 {
+			continue
+		}
+		if renumberDryRun {
+			_, _ = color.New(color.FgYellow).Printf("! #%s was a duplicate ID: the references to it in %s could mean either issue\n",
+				change.OldID, formatIssueRefs(left))
+		} else if renumberForce {
+			_, _ = color.New(color.FgYellow).Printf("! #%s was a duplicate ID: check the references to it left in %s\n",
+				change.OldID, formatIssueRefs(left))
+		}
+	}
+
+	if renumberDryRun {
+		fmt.Printf("Dry run: %d issue(s) would be renumbered\n", len(changes))
+		return nil
+	}
+	fmt.Printf("✓ Renumbered %d issue(s)\n", len(changes))
+
+	// Handle git commit if requested
+	if renumberCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Renumber %d issue(s)", len(changes))); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
+
+// resolveReferences asks which issue each ambiguous reference to a duplicated
+// ID means, returning the referrers (keyed by the renumbered file and the
+// referrer's ID) whose references should follow the renumbered issue. With
+// --force nothing is rewritten; without a terminal to ask on it fails.
+func resolveReferences(changes []pkg.Renumbering) (map[string]bool, error) {
+	meant := make(map[string]bool)
+	if renumberForce {
+		return meant, nil
+	}
+
+	var reader *bufio.Reader
+	for _, change := range changes {
+		if len(change.Ambiguous) == 0 {
+			continue
+		}
+		if !stdinIsTerminal() {
+			return nil, fmt.Errorf("#%s was a duplicate ID and %s reference it ambiguously (run in a terminal to choose, or use --force to leave them unchanged)",
+				change.OldID, formatIssueRefs(change.Ambiguous))
+		}
+		if reader == nil {
+			reader = bufio.NewReader(os.Stdin)
+		}
+		for _, referrer := range change.Ambiguous {
+			fmt.Printf("Does #%s's reference to #%s mean %q, renumbered to #%s? [y/N] ", referrer, change.OldID, change.Title, change.NewID)
+			answer, _ := reader.ReadString('\n')
+			if answer = strings.ToLower(strings.TrimSpace(answer)); answer == "y" || answer == "yes" {
+				meant[change.OldName+" "+referrer] = true
+			}
+		}
+	}
+	return meant, nil
+}
+
+// renderRenumberTable prints the old-to-new ID mapping
+func renderRenumberTable(changes []pkg.Renumbering) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Old ID", "New ID", "Title", "Reason", "References"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+	table.SetAutoWrapText(false)
+
+	for _, change := range changes {
+		reason := "provisional"
+		if change.Collision {
+			reason = "duplicate"
+		}
+
+		refs := "-"
+		if len(change.References) > 0 {
+			refs = formatIssueRefs(change.References)
+		}
+
+		table.Append([]string{"#" + change.OldID, "#" + change.NewID, change.Title, reason, refs})
+	}
+
+	table.Render()
+}
+
+// formatIssueRefs renders IDs as a comma-separated list of #ID references
+func formatIssueRefs(ids []string) string {
+	refs := make([]string, len(ids))
+	for i, id := range ids {
+		refs[i] = "#" + id
+	}
+	return strings.Join(refs, ", ")
+}
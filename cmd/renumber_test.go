@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestCreateProvisionalAndRenumber(t *testing.T) {
+	tmpDir, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	initGitRepository(t, tmpDir)
+	runGitCommand(t, tmpDir, "checkout", "-b", "feature/login")
+	runGitCommand(t, tmpDir, "commit", "--allow-empty", "-m", "init")
+
+	config := "ids:\n  strategy: provisional\n"
+	if err := os.WriteFile(filepath.Join(pkg.IssuesDir, pkg.ConfigFile), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	if err := runCreate(nil, []string{"Login form"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+	if _, _, err := pkg.LoadIssue("feature_login.1"); err != nil {
+		t.Fatalf("expected provisional ID on a feature branch: %v", err)
+	}
+
+	// A unique prefix is enough to address the issue
+	if err := runClose(nil, []string{"feature_login"}); err != nil {
+		t.Fatalf("runClose() with ID prefix failed: %v", err)
+	}
+
+	if err := runRenumber(nil, []string{}); err != nil {
+		t.Fatalf("runRenumber() failed: %v", err)
+	}
+
+	issue, dir, err := pkg.LoadIssue("001")
+	if err != nil {
+		t.Fatalf("renumbered issue not found: %v", err)
+	}
+	if issue.Title != "Login form" || dir != pkg.ClosedDir {
+		t.Errorf("renumbered issue = %q in %s, want Login form in closed", issue.Title, dir)
+	}
+	if _, err := os.Stat(filepath.Join(pkg.GetClosedPath(), "001-login-form.md")); err != nil {
+		t.Errorf("expected renamed file: %v", err)
+	}
+}
+
+func TestRenumberDuplicateIDs(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	for _, title := range []string{"Login", "Export"} {
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatalf("runCreate() failed: %v", err)
+		}
+	}
+
+	// Simulate a merge that brought in a second 001
+	duplicate := filepath.Join(pkg.GetOpenPath(), "001-export.md")
+	if err := os.Rename(filepath.Join(pkg.GetOpenPath(), "002-export.md"), duplicate); err != nil {
+		t.Fatal(err)
+	}
+
+	renumberDryRun = true
+	if err := runRenumber(nil, []string{}); err != nil {
+		t.Fatalf("runRenumber(--dry-run) failed: %v", err)
+	}
+	if _, err := os.Stat(duplicate); err != nil {
+		t.Fatalf("dry run should leave files alone: %v", err)
+	}
+
+	// A reference written after the merge could mean either 001
+	createParent = ""
+	if err := runCreate(nil, []string{"Audit"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+	audit, dir, err := pkg.LoadIssue("003")
+	if err != nil {
+		t.Fatal(err)
+	}
+	audit.Body = "Follow-up to #001."
+	if err := pkg.SaveIssue(audit, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a terminal to ask on, renumbering needs --force
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = stdin
+		_ = r.Close()
+	}()
+
+	renumberDryRun = false
+	err = runRenumber(nil, []string{})
+	if err == nil || !strings.Contains(err.Error(), "#003 reference it ambiguously") {
+		t.Fatalf("runRenumber() without a terminal = %v, want an ambiguity error", err)
+	}
+	if _, err := os.Stat(duplicate); err != nil {
+		t.Fatalf("a failed renumber should leave files alone: %v", err)
+	}
+
+	renumberForce = true
+	if err := runRenumber(nil, []string{}); err != nil {
+		t.Fatalf("runRenumber(--force) failed: %v", err)
+	}
+	if audit, _, err := pkg.LoadIssue("003"); err != nil || audit.Body != "Follow-up to #001." {
+		t.Errorf("--force should leave the reference alone: %q, %v", audit.Body, err)
+	}
+	issue, _, err := pkg.LoadIssue("004")
+	if err != nil || issue.Title != "Export" {
+		t.Fatalf("LoadIssue(004) = %v, %v; want Export", issue, err)
+	}
+	if issue, _, err := pkg.LoadIssue("001"); err != nil || issue.Title != "Login" {
+		t.Errorf("LoadIssue(001) = %v, %v; want Login", issue, err)
+	}
+}
@@ -2,11 +2,16 @@ package cmd
 
 import (
 	"fmt"
+	"path/filepath"
 
+	"github.com/Allra-Fintech/git-issue/pkg"
 	"github.com/spf13/cobra"
 )
 
-var version = "dev"
+var (
+	version  = "dev"
+	repoPath string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   "gi",
@@ -14,6 +19,9 @@ var rootCmd = &cobra.Command{
 	Long: `gi (git-issue) is a CLI tool for managing issues as Markdown files in your git repository.
 It provides AI agents and developers direct access to issue context without external integrations.`,
 	Version: version,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applyRepoPath()
+	},
 }
 
 // Execute runs the root command
@@ -26,6 +34,23 @@ func init() {
 
 	// Enable completion command
 	rootCmd.CompletionOptions.DisableDefaultCmd = false
+
+	rootCmd.PersistentFlags().StringVar(&repoPath, "repo", "", "Repository (or .issues directory) to operate on, overrides discovery and $"+pkg.EnvIssuesDir)
+}
+
+// applyRepoPath points the storage layer at the repository given with --repo.
+// Without the flag, pkg discovers .issues from the working directory.
+func applyRepoPath() {
+	if repoPath == "" {
+		pkg.SetIssuesDir("")
+		return
+	}
+
+	if filepath.Base(filepath.Clean(repoPath)) == pkg.IssuesDir {
+		pkg.SetIssuesDir(repoPath)
+		return
+	}
+	pkg.SetIssuesDir(filepath.Join(repoPath, pkg.IssuesDir))
 }
 
 // SetVersion overrides the default CLI version (useful for ldflags injection).
@@ -6,8 +6,6 @@ import (
 	"strings"
 
 	"github.com/Allra-Fintech/git-issue/pkg"
-	"github.com/fatih/color"
-	"github.com/olekukonko/tablewriter"
 	"github.com/spf13/cobra"
 )
 
@@ -15,28 +13,47 @@ var (
 	searchStatus   string
 	searchAssignee string
 	searchLabel    string
+	searchFields   []string
+	searchNoIndex  bool
+	searchOutput   outputOptions
 )
 
 var searchCmd = &cobra.Command{
 	Use:   "search [query]",
 	Short: "Search issues by text",
-	Long: `Search for issues by text in title and body.
+	Long: `Search for issues by text in title, body and comments.
 
-The search is case-insensitive and searches both the issue title and description.
+The search is case-insensitive and searches the issue title, description and
+comments. Every word must appear in the text, on its own or as part of a longer
+word (auth matches authentication and OAuth), and quoted phrases must appear
+as a whole. The query can also use the qualifiers and operators of
+'gi list --query'; text that isn't a valid query, such as an error message or
+a URL, is searched for word by word. Results are ranked by relevance.
+
+Words are looked up in a search index kept in .issues/.cache/, which is
+updated as issue files change. Use --no-index to scan every issue instead;
+it finds the same issues, unranked.
 
 Examples:
   gi search "Redis"
   gi search "authentication" --status open
-  gi search "bug" --label backend --assignee john`,
+  gi search "bug" --label backend --assignee john
+  gi search "timeout" --field priority=high
+  gi search "auth" --status open
+  gi search '"connection reset" label:bug -status:closed'
+  gi search "timeout" --format ndjson`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runSearch,
 }
 
 func init() {
 	rootCmd.AddCommand(searchCmd)
-	searchCmd.Flags().StringVar(&searchStatus, "status", "", "Filter by status (open/closed)")
+	searchCmd.Flags().StringVar(&searchStatus, "status", "", "Filter by status (open, closed or any workflow state)")
 	searchCmd.Flags().StringVar(&searchAssignee, "assignee", "", "Filter by assignee")
 	searchCmd.Flags().StringVar(&searchLabel, "label", "", "Filter by label")
+	searchCmd.Flags().StringArrayVar(&searchFields, "field", []string{}, "Filter by custom field as key=value (can be specified multiple times)")
+	searchCmd.Flags().BoolVar(&searchNoIndex, "no-index", false, "Scan every issue instead of using the search index")
+	addOutputFlags(searchCmd, &searchOutput)
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
@@ -45,6 +62,10 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
 	}
 
+	if err := searchOutput.validate(); err != nil {
+		return err
+	}
+
 	// Join all args as search query (in case query has spaces and wasn't quoted)
 	query := strings.TrimSpace(strings.Join(args, " "))
 
@@ -53,61 +74,62 @@ func runSearch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("search query cannot be empty")
 	}
 
-	// Convert query to lowercase for case-insensitive search
-	queryLower := strings.ToLower(query)
+	// Load custom field schema and workflow
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		return err
+	}
 
 	// Determine which directories to search
 	var dirsToSearch []string
+	var stateFilter string
 	if searchStatus != "" {
 		// Filter by specific status
-		switch searchStatus {
-		case "open":
-			dirsToSearch = []string{pkg.OpenDir}
-		case "closed":
-			dirsToSearch = []string{pkg.ClosedDir}
-		default:
-			return fmt.Errorf("invalid status: %s (must be 'open' or 'closed')", searchStatus)
+		dirsToSearch, stateFilter, err = resolveStatusFilter(searchStatus, &cfg.Workflow)
+		if err != nil {
+			return err
 		}
 	} else {
 		// Default: search all issues
 		dirsToSearch = []string{pkg.OpenDir, pkg.ClosedDir}
 	}
 
-	// Collect issues from all directories
-	type issueWithStatus struct {
-		issue  *pkg.Issue
-		status string
+	fieldFilters, err := parseFieldFilters(searchFields)
+	if err != nil {
+		return err
 	}
-	var allIssues []issueWithStatus
 
-	for _, dir := range dirsToSearch {
-		issues, err := pkg.ListIssues(dir)
-		if err != nil {
-			// If directory doesn't exist yet, just skip it
-			continue
-		}
+	q, err := pkg.ParseSearch(query, cfg)
+	if err != nil {
+		return err
+	}
 
-		status := "open"
-		if dir == pkg.ClosedDir {
-			status = "closed"
+	// Find matching issues, best first unless scanning
+	var found []issueWithStatus
+	if searchNoIndex {
+		for _, item := range collectIssues(dirsToSearch, &cfg.Workflow) {
+			// Words match the title, body and comments (case-insensitive)
+			if q.Match(item.issue, item.dir, item.status) {
+				found = append(found, item)
+			}
 		}
-
-		for _, issue := range issues {
-			allIssues = append(allIssues, issueWithStatus{
-				issue:  issue,
-				status: status,
-			})
+	} else {
+		hits, err := pkg.Search(q)
+		if err != nil {
+			return err
+		}
+		for _, hit := range hits {
+			if containsKey(dirsToSearch, hit.Dir) {
+				found = append(found, issueWithStatus{issue: hit.Issue, dir: hit.Dir, status: hit.Status})
+			}
 		}
 	}
 
-	// Search and filter issues
+	// Filter issues
 	var matchedIssues []issueWithStatus
-	for _, item := range allIssues {
-		// Search in title and body (case-insensitive)
-		titleLower := strings.ToLower(item.issue.Title)
-		bodyLower := strings.ToLower(item.issue.Body)
-
-		if !strings.Contains(titleLower, queryLower) && !strings.Contains(bodyLower, queryLower) {
+	for _, item := range found {
+		// Filter by workflow state
+		if stateFilter != "" && item.status != stateFilter {
 			continue
 		}
 
@@ -121,66 +143,26 @@ func runSearch(cmd *cobra.Command, args []string) error {
 			continue
 		}
 
+		// Filter by custom fields
+		if !matchesFieldFilters(item.issue, fieldFilters) {
+			continue
+		}
+
 		matchedIssues = append(matchedIssues, item)
 	}
 
+	// Machine-readable output, including an empty list
+	if !searchOutput.tabular() {
+		return writeIssues(os.Stdout, matchedIssues, cfg, searchOutput)
+	}
+
 	// Display results
 	if len(matchedIssues) == 0 {
 		fmt.Printf("No issues found matching '%s'.\n", query)
 		return nil
 	}
 
-	// Create table
-	table := tablewriter.NewWriter(os.Stdout)
-	table.SetHeader([]string{"ID", "Title", "Status", "Assignee", "Labels"})
-	table.SetBorder(false)
-	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
-	table.SetAlignment(tablewriter.ALIGN_LEFT)
-	table.SetCenterSeparator("")
-	table.SetColumnSeparator("")
-	table.SetRowSeparator("")
-	table.SetHeaderLine(false)
-	table.SetTablePadding("\t")
-	table.SetNoWhiteSpace(true)
-
-	// Add rows
-	green := color.New(color.FgGreen).SprintFunc()
-	red := color.New(color.FgRed).SprintFunc()
-
-	for _, item := range matchedIssues {
-		issue := item.issue
-		status := item.status
-
-		// Color-code status
-		var statusStr string
-		if status == "open" {
-			statusStr = green(status)
-		} else {
-			statusStr = red(status)
-		}
-
-		// Format labels
-		labelsStr := "-"
-		if len(issue.Labels) > 0 {
-			labelsStr = strings.Join(issue.Labels, ", ")
-		}
-
-		// Format assignee
-		assigneeStr := "-"
-		if issue.Assignee != "" {
-			assigneeStr = issue.Assignee
-		}
-
-		table.Append([]string{
-			"#" + issue.ID,
-			issue.Title,
-			statusStr,
-			assigneeStr,
-			labelsStr,
-		})
-	}
-
-	table.Render()
+	renderIssueTable(matchedIssues, cfg.Fields, nil, nil)
 
 	// Summary
 	fmt.Printf("\nFound %d issue(s) matching '%s'\n", len(matchedIssues), query)
@@ -1,7 +1,9 @@
 package cmd
 
 import (
+	"fmt"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/Allra-Fintech/git-issue/pkg"
@@ -28,6 +30,8 @@ func setupSearchTest(t *testing.T) (string, func()) {
 		searchStatus = ""
 		searchAssignee = ""
 		searchLabel = ""
+		searchFields = []string{}
+		searchNoIndex = false
 	}
 
 	// Change to temp directory
@@ -335,3 +339,92 @@ func TestSearchEmptyRepo(t *testing.T) {
 		t.Errorf("runSearch() should handle empty repo, got error: %v", err)
 	}
 }
+
+func TestSearchCommandIndex(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	for _, title := range []string{"Fix Redis connection timeout", "Add user authentication", "로그인을 할 수 없음"} {
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The index and a scan find the same issues, including parts of words
+	searchOutput.template = "{{.ID}}"
+	for _, noIndex := range []bool{false, true} {
+		searchNoIndex = noIndex
+		for _, tt := range []struct{ query, want string }{
+			{"redis", "001\n"},
+			{"auth", "002\n"},
+			{"로그인", "003\n"},
+			{"connection timeout", "001\n"},
+			{"memcached", ""},
+		} {
+			out, err := captureStdout(t, func() error { return runSearch(nil, []string{tt.query}) })
+			if err != nil {
+				t.Fatalf("runSearch(%q) failed: %v", tt.query, err)
+			}
+			if out != tt.want {
+				t.Errorf("runSearch(%q) with --no-index=%v printed %q, want %q", tt.query, noIndex, out, tt.want)
+			}
+		}
+		if noIndex {
+			break
+		}
+
+		if _, err := os.Stat(filepath.Join(pkg.IssuesDir, pkg.SearchIndexFile)); err != nil {
+			t.Errorf("search index was not written: %v", err)
+		}
+		if err := os.RemoveAll(filepath.Join(pkg.IssuesDir, pkg.CacheDir)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(pkg.IssuesDir, pkg.CacheDir)); err == nil {
+		t.Error("--no-index should not write the search index")
+	}
+}
+
+func TestSearchCommandPunctuation(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	bodies := []string{
+		"Saving fails with TypeError: cannot read properties of undefined",
+		"The login page at https://example.com/login is blank",
+		"Needs a fix (urgent) before the release",
+	}
+	for i, body := range bodies {
+		if err := runCreate(nil, []string{fmt.Sprintf("Issue %d", i+1)}); err != nil {
+			t.Fatal(err)
+		}
+		issue, dir, err := pkg.LoadIssue(pkg.FormatID(i + 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		issue.Body = body
+		if err := pkg.SaveIssue(issue, dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Text that isn't a valid query is searched for as text
+	searchOutput.template = "{{.ID}}"
+	for _, noIndex := range []bool{false, true} {
+		searchNoIndex = noIndex
+		for _, tt := range []struct{ query, want string }{
+			{"TypeError: cannot", "001\n"},
+			{"https://example.com/login", "002\n"},
+			{"fix (urgent", "003\n"},
+			{"label:bug", ""}, // still a query
+		} {
+			out, err := captureStdout(t, func() error { return runSearch(nil, []string{tt.query}) })
+			if err != nil {
+				t.Fatalf("runSearch(%q) failed: %v", tt.query, err)
+			}
+			if out != tt.want {
+				t.Errorf("runSearch(%q) with --no-index=%v printed %q, want %q", tt.query, noIndex, out, tt.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,438 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/spf13/cobra"
+)
+
+var serveAddr string
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve a JSON REST API for issues over HTTP",
+	Long: `Serve a JSON REST API for the issues in .issues/, for dashboards and
+editor plugins. Changes go through the same code as the gi commands.
+
+  GET    /issues                 List issues (?q=, status, sort, limit, offset)
+  POST   /issues                 Create an issue
+  GET    /issues/{id}            Get an issue
+  PATCH  /issues/{id}            Change an issue
+  GET    /issues/{id}/markdown   Get an issue's Markdown file
+  PUT    /issues/{id}/markdown   Replace an issue's Markdown file, as 'gi edit' does
+  POST   /issues/{id}/close      Close an issue ({"force": true} to close a parent,
+                                 {"strict": true} to refuse with open blockers
+                                 or unchecked success criteria)
+  POST   /issues/{id}/comments   Comment on an issue
+  GET    /search                 Full-text search (?q=, status, limit)
+  GET    /config                 Workflow states, priorities and custom fields
+
+Responses for a single issue carry an ETag, a hash of the issue file. Send it
+back in If-Match with PATCH, PUT or close, and the change fails with 412
+Precondition Failed if the issue was modified in the meantime, instead of
+overwriting someone else's edit.
+
+Request bodies must be JSON with Content-Type application/json, except the
+Markdown file PUT as text/markdown. Requests from web pages of other sites
+(an Origin other than the server's own) are refused, and when listening on
+localhost so are requests for any other host name, which a site rebinding its
+DNS name to 127.0.0.1 would make.
+
+The API has no authentication; it listens on localhost unless --addr says
+otherwise.`,
+	Example: `  gi serve
+  gi serve --addr :8080
+  curl localhost:8080/issues?q=label:bug
+  curl -X PATCH -H 'Content-Type: application/json' -H 'If-Match: "<etag>"' \
+    -d '{"assignee": "mina"}' localhost:8080/issues/001`,
+	Args: cobra.NoArgs,
+	RunE: runServe,
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().StringVar(&serveAddr, "addr", "localhost:8080", "Address to listen on")
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	return listenAndServe(serveAddr, newAPIServer(pkg.DefaultRepository()), func(url string) {
+		fmt.Printf("✓ Serving issues on %s (Ctrl-C to stop)\n", url)
+	})
+}
+
+// listenAndServe serves HTTP on addr until interrupted, calling started with
+// the server's URL once it's listening
+func listenAndServe(addr string, handler http.Handler, started func(url string)) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	started("http://" + listener.Addr().String())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// maxRequestBody limits the size of request bodies
+const maxRequestBody = 10 << 20
+
+// apiServer serves the REST API against a repository
+type apiServer struct {
+	*issueAPI
+	mux *http.ServeMux
+}
+
+func newAPIServer(repo *pkg.Repository) *apiServer {
+	s := &apiServer{issueAPI: newIssueAPI(repo), mux: http.NewServeMux()}
+	s.mux.HandleFunc("GET /issues", s.handleList)
+	s.mux.HandleFunc("POST /issues", s.handleCreate)
+	s.mux.HandleFunc("GET /issues/{id}", s.handleGet)
+	s.mux.HandleFunc("PATCH /issues/{id}", s.handleUpdate)
+	s.mux.HandleFunc("GET /issues/{id}/markdown", s.handleGetMarkdown)
+	s.mux.HandleFunc("PUT /issues/{id}/markdown", s.handleReplace)
+	s.mux.HandleFunc("POST /issues/{id}/close", s.handleClose)
+	s.mux.HandleFunc("POST /issues/{id}/comments", s.handleComment)
+	s.mux.HandleFunc("GET /search", s.handleSearch)
+	s.mux.HandleFunc("GET /config", s.handleConfig)
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeAPIError(w, http.StatusNotFound, fmt.Errorf("no such endpoint: %s %s", r.Method, r.URL.Path))
+	})
+	return s
+}
+
+func (s *apiServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := checkRequestSource(r); err != nil {
+		writeAPIError(w, http.StatusForbidden, err)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// checkRequestSource refuses requests made by web pages of other sites: those
+// with an Origin other than the server's own, and, when the request came in on
+// a loopback address, those for a host name other than localhost, as a site
+// that rebinds its DNS name to 127.0.0.1 makes
+func checkRequestSource(r *http.Request) error {
+	if local, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr); ok && isLoopbackHost(local.String()) && !isLoopbackHost(r.Host) {
+		return fmt.Errorf("unexpected host %q: the server only answers to localhost", r.Host)
+	}
+	if origin := r.Header.Get("Origin"); origin != "" {
+		u, err := url.Parse(origin)
+		if err != nil || !strings.EqualFold(u.Host, r.Host) {
+			return fmt.Errorf("requests from %s aren't allowed", origin)
+		}
+	}
+	return nil
+}
+
+// isLoopbackHost reports whether host, with or without a port, is localhost
+// or a loopback address
+func isLoopbackHost(host string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// writeJSON writes v as the response body
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeAPIError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// fail reports an error from an operation. Unknown issues are 404, stale
+// versions 412 and bodies of the wrong type 415; anything else gets status.
+func (s *apiServer) fail(w http.ResponseWriter, status int, err error) {
+	switch {
+	case errors.Is(err, pkg.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, pkg.ErrIssueModified):
+		status = http.StatusPreconditionFailed
+	case errors.Is(err, errUnsupportedMediaType):
+		status = http.StatusUnsupportedMediaType
+	}
+	writeAPIError(w, status, err)
+}
+
+// writeRecord writes an issue with its version as the ETag
+func writeRecord(w http.ResponseWriter, status int, record issueRecord) {
+	w.Header().Set("ETag", etag(record.version))
+	writeJSON(w, status, record)
+}
+
+func etag(version string) string {
+	return `"` + version + `"`
+}
+
+// errUnsupportedMediaType is returned for a request body of the wrong type
+var errUnsupportedMediaType = errors.New("unsupported media type")
+
+// checkContentType makes sure a request body, if any, is of media type want.
+// Web pages can send plain text and form bodies to any site without the
+// browser asking it first, so only the types they can't send are accepted.
+func checkContentType(r *http.Request, want string) error {
+	if r.ContentLength == 0 {
+		return nil
+	}
+	got, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || got != want {
+		return fmt.Errorf("%w: the request body must be %s", errUnsupportedMediaType, want)
+	}
+	return nil
+}
+
+// readBody decodes a JSON request body, rejecting unknown keys
+func readBody(w http.ResponseWriter, r *http.Request, v interface{}) error {
+	if err := checkContentType(r, "application/json"); err != nil {
+		return err
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	if err != nil {
+		return err
+	}
+	return decodeArgs(data, v)
+}
+
+// ifMatch returns the version an If-Match header asks for, "" when any
+// version will do. Of several entity tags, the current version is picked if
+// it's among them; the repository checks it again when writing.
+func (s *apiServer) ifMatch(r *http.Request, id string) string {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+	if header == "" || header == "*" {
+		return ""
+	}
+
+	var tags []string
+	for _, tag := range strings.Split(header, ",") {
+		tags = append(tags, strings.TrimSpace(tag))
+	}
+	if len(tags) > 1 {
+		if issue, _, err := s.repo.LoadIssue(id); err == nil && containsKey(tags, etag(issue.Version)) {
+			return issue.Version
+		}
+	}
+	// Weak and malformed tags are passed on as they are and never match
+	// (If-Match compares strongly)
+	if tag := tags[0]; len(tag) > 2 && strings.HasPrefix(tag, `"`) && strings.HasSuffix(tag, `"`) {
+		return tag[1 : len(tag)-1]
+	}
+	return header
+}
+
+// queryInt parses an optional integer query parameter
+func queryInt(r *http.Request, name string) (*int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", name, value)
+	}
+	return &n, nil
+}
+
+func (s *apiServer) handleList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	args := listArgs{Query: query.Get("q"), Status: query.Get("status"), Sort: query.Get("sort")}
+	var err error
+	if args.Limit, err = queryInt(r, "limit"); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	offset, err := queryInt(r, "offset")
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	if offset != nil {
+		args.Offset = *offset
+	}
+
+	list, err := s.listIssues(args)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *apiServer) handleSearch(w http.ResponseWriter, r *http.Request) {
+	args := searchArgs{Query: r.URL.Query().Get("q"), Status: r.URL.Query().Get("status")}
+	var err error
+	if args.Limit, err = queryInt(r, "limit"); err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	list, err := s.searchIssues(args)
+	if err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, list)
+}
+
+func (s *apiServer) handleGet(w http.ResponseWriter, r *http.Request) {
+	record, err := s.getIssue(idArgs{ID: r.PathValue("id")})
+	if err != nil {
+		s.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	if match := r.Header.Get("If-None-Match"); match != "" && match == etag(record.version) {
+		w.Header().Set("ETag", etag(record.version))
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeRecord(w, http.StatusOK, record)
+}
+
+func (s *apiServer) handleGetMarkdown(w http.ResponseWriter, r *http.Request) {
+	content, version, err := s.issueMarkdown(idArgs{ID: r.PathValue("id")})
+	if err != nil {
+		s.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	w.Header().Set("ETag", etag(version))
+	_, _ = io.WriteString(w, content)
+}
+
+func (s *apiServer) handleReplace(w http.ResponseWriter, r *http.Request) {
+	if err := checkContentType(r, "text/markdown"); err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxRequestBody))
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, err)
+		return
+	}
+	args := markdownArgs{ID: r.PathValue("id"), Content: string(data)}
+	args.version = s.ifMatch(r, args.ID)
+
+	record, err := s.replaceIssue(args)
+	if err != nil {
+		s.fail(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeRecord(w, http.StatusOK, record)
+}
+
+func (s *apiServer) handleConfig(w http.ResponseWriter, r *http.Request) {
+	info, err := s.config()
+	if err != nil {
+		s.fail(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, info)
+}
+
+func (s *apiServer) handleCreate(w http.ResponseWriter, r *http.Request) {
+	var args createArgs
+	if err := readBody(w, r, &args); err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+
+	record, err := s.createIssue(args)
+	if err != nil {
+		s.fail(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	w.Header().Set("Location", "/issues/"+record.ID)
+	writeRecord(w, http.StatusCreated, record)
+}
+
+func (s *apiServer) handleUpdate(w http.ResponseWriter, r *http.Request) {
+	var args updateArgs
+	if err := readBody(w, r, &args); err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	args.ID = r.PathValue("id")
+	args.version = s.ifMatch(r, args.ID)
+
+	record, err := s.updateIssue(args)
+	if err != nil {
+		s.fail(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeRecord(w, http.StatusOK, record)
+}
+
+func (s *apiServer) handleClose(w http.ResponseWriter, r *http.Request) {
+	var args closeArgs
+	if err := readBody(w, r, &args); err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	args.ID = r.PathValue("id")
+	args.version = s.ifMatch(r, args.ID)
+
+	result, err := s.closeIssue(args)
+	if err != nil {
+		s.fail(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	w.Header().Set("ETag", etag(result.Issue.version))
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *apiServer) handleComment(w http.ResponseWriter, r *http.Request) {
+	var args commentArgs
+	if err := readBody(w, r, &args); err != nil {
+		s.fail(w, http.StatusBadRequest, err)
+		return
+	}
+	args.ID = r.PathValue("id")
+
+	result, err := s.comment(args)
+	if err != nil {
+		s.fail(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, result)
+}
@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+// apiRequest sends a request to the API server and decodes the JSON response into v
+func apiRequest(t *testing.T, server *httptest.Server, method, path, body string, header map[string]string, v interface{}) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, server.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for key, value := range header {
+		req.Header.Set(key, value)
+	}
+	req.Host = req.Header.Get("Host")
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s %s: invalid response: %v", method, path, err)
+		}
+	}
+	return resp
+}
+
+func TestServeAPI(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	createLabels = []string{"bug"}
+	if err := runCreate(nil, []string{"Fix Redis connection timeout"}); err != nil {
+		t.Fatal(err)
+	}
+	createLabels = []string{}
+	if err := runCreate(nil, []string{"Add user authentication"}); err != nil {
+		t.Fatal(err)
+	}
+	setCriteria(t, "002", "Sessions expire")
+
+	api := newAPIServer(pkg.DefaultRepository())
+	api.branch = func() string { return "main" }
+	api.author = func() string { return "dashboard" }
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	var list issueList
+	if resp := apiRequest(t, server, "GET", "/issues?q=label:bug", "", nil, &list); resp.StatusCode != http.StatusOK || list.Total != 1 || list.Issues[0].ID != "001" {
+		t.Errorf("GET /issues?q=label:bug = %d %+v", resp.StatusCode, list)
+	}
+	if resp := apiRequest(t, server, "GET", "/issues?limit=1&offset=1", "", nil, &list); resp.StatusCode != http.StatusOK || list.Total != 2 || len(list.Issues) != 1 || list.Issues[0].ID != "002" {
+		t.Errorf("GET /issues?limit=1&offset=1 = %d %+v", resp.StatusCode, list)
+	}
+
+	// Create
+	var created pkg.IssueRecord
+	resp := apiRequest(t, server, "POST", "/issues", `{"title": "Rotate API keys", "labels": ["security"], "priority": "high"}`, nil, &created)
+	if resp.StatusCode != http.StatusCreated || created.ID != "003" || resp.Header.Get("Location") != "/issues/003" || resp.Header.Get("ETag") == "" {
+		t.Errorf("POST /issues = %d %+v (headers %v)", resp.StatusCode, created, resp.Header)
+	}
+	if resp := apiRequest(t, server, "POST", "/issues", `{"title": "Bad", "priority": "urgent"}`, nil, nil); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("POST /issues with an invalid priority = %d, want 422", resp.StatusCode)
+	}
+	if resp := apiRequest(t, server, "POST", "/issues", `{"title": "Bad", "colour": "red"}`, nil, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("POST /issues with an unknown key = %d, want 400", resp.StatusCode)
+	}
+
+	// Get
+	var got pkg.IssueRecord
+	resp = apiRequest(t, server, "GET", "/issues/001", "", nil, &got)
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || got.Title != "Fix Redis connection timeout" || etag == "" {
+		t.Errorf("GET /issues/001 = %d %+v, ETag %q", resp.StatusCode, got, etag)
+	}
+	if resp := apiRequest(t, server, "GET", "/issues/001", "", map[string]string{"If-None-Match": etag}, nil); resp.StatusCode != http.StatusNotModified {
+		t.Errorf("GET /issues/001 with a current If-None-Match = %d, want 304", resp.StatusCode)
+	}
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	if resp := apiRequest(t, server, "GET", "/issues/999", "", nil, &apiErr); resp.StatusCode != http.StatusNotFound || !strings.Contains(apiErr.Error, "not found") {
+		t.Errorf("GET /issues/999 = %d %+v, want 404", resp.StatusCode, apiErr)
+	}
+
+	// Update with optimistic concurrency: the first edit wins, the second is
+	// based on a stale version and is refused
+	var updated pkg.IssueRecord
+	resp = apiRequest(t, server, "PATCH", "/issues/001", `{"assignee": "mina", "add_labels": ["backend"]}`, map[string]string{"If-Match": etag}, &updated)
+	newETag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || updated.Assignee != "mina" || strings.Join(updated.Labels, ",") != "bug,backend" || newETag == etag {
+		t.Errorf("PATCH /issues/001 = %d %+v, ETag %q", resp.StatusCode, updated, newETag)
+	}
+	if resp := apiRequest(t, server, "PATCH", "/issues/001", `{"assignee": "joe"}`, map[string]string{"If-Match": etag}, &apiErr); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("PATCH /issues/001 with a stale If-Match = %d, want 412", resp.StatusCode)
+	}
+	if issue, _, _ := pkg.LoadIssue("001"); issue.Assignee != "mina" {
+		t.Errorf("a refused PATCH changed the assignee to %q", issue.Assignee)
+	}
+	if resp := apiRequest(t, server, "PATCH", "/issues/001", `{"title": "Fix Redis timeouts"}`, map[string]string{"If-Match": `"stale", ` + newETag}, &updated); resp.StatusCode != http.StatusOK {
+		t.Errorf("PATCH /issues/001 with one current If-Match tag = %d, want 200", resp.StatusCode)
+	}
+	if resp := apiRequest(t, server, "PATCH", "/issues/002", `{"due": "soon"}`, nil, nil); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("PATCH /issues/002 with an invalid due date = %d, want 422", resp.StatusCode)
+	}
+
+	// Comment
+	var commented commentResult
+	if resp := apiRequest(t, server, "POST", "/issues/002/comments", `{"text": "On it"}`, nil, &commented); resp.StatusCode != http.StatusCreated || commented.Comment.Author != "dashboard" {
+		t.Errorf("POST /issues/002/comments = %d %+v", resp.StatusCode, commented)
+	}
+
+	// Close
+	if resp := apiRequest(t, server, "POST", "/issues/002/close", "", map[string]string{"If-Match": etag}, nil); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("POST /issues/002/close with another issue's ETag = %d, want 412", resp.StatusCode)
+	}
+	if resp := apiRequest(t, server, "POST", "/issues/002/close", `{"strict": true}`, nil, &apiErr); resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(apiErr.Error, "unchecked success criteria") {
+		t.Errorf("POST /issues/002/close with strict = %d %+v, want 422", resp.StatusCode, apiErr)
+	}
+	var closed struct {
+		Issue    pkg.IssueRecord `json:"issue"`
+		Warnings []string        `json:"warnings"`
+	}
+	if resp := apiRequest(t, server, "POST", "/issues/002/close", "", nil, &closed); resp.StatusCode != http.StatusOK || !closed.Issue.Closed {
+		t.Errorf("POST /issues/002/close = %d %+v", resp.StatusCode, closed)
+	}
+	if len(closed.Warnings) != 1 || !strings.Contains(closed.Warnings[0], "unchecked success criteria") {
+		t.Errorf("POST /issues/002/close warnings = %q", closed.Warnings)
+	}
+	if resp := apiRequest(t, server, "POST", "/issues/002/close", "", nil, &apiErr); resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(apiErr.Error, "already closed") {
+		t.Errorf("closing a closed issue = %d %+v", resp.StatusCode, apiErr)
+	}
+
+	// Search
+	if resp := apiRequest(t, server, "GET", "/search?q=redis", "", nil, &list); resp.StatusCode != http.StatusOK || list.Total != 1 || list.Issues[0].ID != "001" {
+		t.Errorf("GET /search?q=redis = %d %+v", resp.StatusCode, list)
+	}
+	if resp := apiRequest(t, server, "GET", "/search", "", nil, nil); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("GET /search without a query = %d, want 400", resp.StatusCode)
+	}
+
+	if resp := apiRequest(t, server, "GET", "/nope", "", nil, nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET /nope = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestServeAPIStatusChecksClosing(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Epic"}); err != nil {
+		t.Fatal(err)
+	}
+	createParent = "001"
+	if err := runCreate(nil, []string{"Child"}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(newAPIServer(pkg.DefaultRepository()))
+	defer server.Close()
+
+	var apiErr struct {
+		Error string `json:"error"`
+	}
+	if resp := apiRequest(t, server, "PATCH", "/issues/001", `{"status": "closed"}`, nil, &apiErr); resp.StatusCode != http.StatusUnprocessableEntity || !strings.Contains(apiErr.Error, "set force") {
+		t.Errorf("PATCH /issues/001 to closed with an open child = %d %+v, want 422", resp.StatusCode, apiErr)
+	}
+	var updated pkg.IssueRecord
+	if resp := apiRequest(t, server, "PATCH", "/issues/001", `{"status": "closed", "force": true}`, nil, &updated); resp.StatusCode != http.StatusOK || !updated.Closed {
+		t.Errorf("PATCH /issues/001 to closed with force = %d %+v", resp.StatusCode, updated)
+	}
+}
+
+func TestServeAPIMarkdown(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Fix Redis connection timeout"}); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(newAPIServer(pkg.DefaultRepository()))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/issues/001/markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(data), "# Fix Redis connection timeout") || etag == "" {
+		t.Fatalf("GET /issues/001/markdown = %d %q, ETag %q", resp.StatusCode, data, etag)
+	}
+
+	// Saving the edited file moves the ETag on, so saving again from the old copy fails
+	edited := strings.Replace(string(data), "# Fix Redis connection timeout", "# Fix Redis timeouts", 1)
+	var saved pkg.IssueRecord
+	resp = apiRequest(t, server, "PUT", "/issues/001/markdown", edited, map[string]string{"Content-Type": "text/markdown", "If-Match": etag}, &saved)
+	if resp.StatusCode != http.StatusOK || saved.Title != "Fix Redis timeouts" || resp.Header.Get("ETag") == etag {
+		t.Errorf("PUT /issues/001/markdown = %d %+v", resp.StatusCode, saved)
+	}
+	if resp := apiRequest(t, server, "PUT", "/issues/001/markdown", edited, map[string]string{"Content-Type": "text/markdown", "If-Match": etag}, nil); resp.StatusCode != http.StatusPreconditionFailed {
+		t.Errorf("PUT /issues/001/markdown with a stale If-Match = %d, want 412", resp.StatusCode)
+	}
+	if resp := apiRequest(t, server, "PUT", "/issues/001/markdown", "no frontmatter", map[string]string{"Content-Type": "text/markdown; charset=utf-8"}, nil); resp.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("PUT /issues/001/markdown with an invalid file = %d, want 422", resp.StatusCode)
+	}
+
+	var info configInfo
+	if resp := apiRequest(t, server, "GET", "/config", "", nil, &info); resp.StatusCode != http.StatusOK || len(info.States) != 2 || info.States[0].Name != "open" || info.States[1].Name != "closed" {
+		t.Errorf("GET /config = %d %+v", resp.StatusCode, info)
+	}
+}
+
+func TestServeAPIRejectsOtherSites(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Fix Redis connection timeout"}); err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(newAPIServer(pkg.DefaultRepository()))
+	defer server.Close()
+	port := server.URL[strings.LastIndex(server.URL, ":"):]
+
+	tests := []struct {
+		name         string
+		method, path string
+		body         string
+		header       map[string]string
+		want         int
+	}{
+		{"a form body", "POST", "/issues", "title=Spam", map[string]string{"Content-Type": "application/x-www-form-urlencoded"}, http.StatusUnsupportedMediaType},
+		{"a text body", "PATCH", "/issues/001", `{"assignee": "mallory"}`, map[string]string{"Content-Type": "text/plain"}, http.StatusUnsupportedMediaType},
+		{"a JSON issue file", "PUT", "/issues/001/markdown", `{"title": "Spam"}`, nil, http.StatusUnsupportedMediaType},
+		{"another origin", "POST", "/issues/001/close", "", map[string]string{"Origin": "http://evil.example"}, http.StatusForbidden},
+		{"a rebound host name", "GET", "/issues", "", map[string]string{"Host": "evil.example" + port}, http.StatusForbidden},
+		{"its own origin", "GET", "/issues", "", map[string]string{"Origin": server.URL}, http.StatusOK},
+		{"localhost", "GET", "/issues", "", map[string]string{"Host": "localhost" + port}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		if resp := apiRequest(t, server, tt.method, tt.path, tt.body, tt.header, nil); resp.StatusCode != tt.want {
+			t.Errorf("%s %s with %s = %d, want %d", tt.method, tt.path, tt.name, resp.StatusCode, tt.want)
+		}
+	}
+
+	issue, dir, err := pkg.LoadIssue("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dir != pkg.OpenDir || issue.Assignee != "" || issue.Title != "Fix Redis connection timeout" {
+		t.Errorf("a refused request changed the issue: %s %q %q", dir, issue.Assignee, issue.Title)
+	}
+	if issues, _ := pkg.ListIssues(pkg.OpenDir); len(issues) != 1 {
+		t.Errorf("a refused request created an issue, %d open", len(issues))
+	}
+}
@@ -2,14 +2,19 @@ package cmd
 
 import (
 	"fmt"
+	"os"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/Allra-Fintech/git-issue/pkg"
 	"github.com/fatih/color"
 	"github.com/spf13/cobra"
 )
 
+var showOutput outputOptions
+
 var showCmd = &cobra.Command{
 	Use:   "show [issue-id]",
 	Short: "Show detailed information about an issue",
@@ -17,13 +22,16 @@ var showCmd = &cobra.Command{
 
 Examples:
   gi show 001
-  gi show 42`,
+  gi show 42
+  gi show 001 --format json
+  gi show 001 --template '{{.Title}} ({{.Status}})'`,
 	Args: cobra.ExactArgs(1),
 	RunE: runShow,
 }
 
 func init() {
 	rootCmd.AddCommand(showCmd)
+	addOutputFlags(showCmd, &showOutput)
 }
 
 func runShow(cmd *cobra.Command, args []string) error {
@@ -32,6 +40,10 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
 	}
 
+	if err := showOutput.validate(); err != nil {
+		return err
+	}
+
 	// Get issue ID
 	issueID := args[0]
 
@@ -48,16 +60,19 @@ func runShow(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("issue #%s not found", issueID)
 	}
 
-	// Determine status from directory
-	status := "open"
-	if dir == pkg.ClosedDir {
-		status = "closed"
+	// Determine status from directory and workflow state
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		return err
+	}
+	item := issueWithStatus{issue: issue, dir: dir, status: cfg.Workflow.StatusOf(issue, dir)}
+
+	if !showOutput.tabular() {
+		return writeIssue(os.Stdout, item, cfg, showOutput)
 	}
 
 	// Display issue details
 	bold := color.New(color.Bold).SprintFunc()
-	green := color.New(color.FgGreen, color.Bold).SprintFunc()
-	red := color.New(color.FgRed, color.Bold).SprintFunc()
 
 	// Header
 	fmt.Printf("%s %s\n", bold("Issue"), bold("#"+issue.ID))
@@ -69,13 +84,12 @@ func runShow(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	// Status
-	var statusStr string
-	if status == "open" {
-		statusStr = green(status)
-	} else {
-		statusStr = red(status)
+	fmt.Printf("%s %s\n", bold("Status:"), colorStatus(item, true))
+
+	// Parent
+	if issue.Parent != "" {
+		fmt.Printf("%s #%s\n", bold("Parent:"), issue.Parent)
 	}
-	fmt.Printf("%s %s\n", bold("Status:"), statusStr)
 
 	// Assignee
 	if issue.Assignee != "" {
@@ -87,10 +101,57 @@ func runShow(cmd *cobra.Command, args []string) error {
 		fmt.Printf("%s %s\n", bold("Labels:"), strings.Join(issue.Labels, ", "))
 	}
 
+	// Custom fields: declared ones in schema order, then any others
+	for _, name := range customFieldNames(issue, cfg) {
+		value, _ := issue.Field(name)
+		valueStr := pkg.FormatFieldValue(value)
+		if name == pkg.DueField && isOverdue(item) {
+			valueStr = color.New(color.FgRed, color.Bold).Sprintf("%s (%d day(s) overdue)", valueStr, issue.DaysOverdue(time.Now()))
+		}
+		fmt.Printf("%s %s\n", bold(fieldLabel(name)+":"), valueStr)
+	}
+
+	// Checked task list items
+	if tasks := issue.TaskProgress(); tasks.Total > 0 {
+		fmt.Printf("%s %s checked\n", bold("Tasks:"), tasks)
+	}
+
+	// Progress of child issues
+	children, err := pkg.Children(issue.ID)
+	if err != nil {
+		return err
+	}
+	if len(children) > 0 {
+		fmt.Printf("%s %s\n", bold("Progress:"), pkg.ProgressOf(children))
+	}
+
 	// Timestamps
 	fmt.Printf("%s %s\n", bold("Created:"), issue.Created.Format("2006-01-02 15:04:05"))
 	fmt.Printf("%s %s\n", bold("Updated:"), issue.Updated.Format("2006-01-02 15:04:05"))
 
+	// Links, including those stored on the other issue
+	relations, err := pkg.Relations(issue.ID)
+	if err != nil {
+		return err
+	}
+	if len(relations) > 0 {
+		fmt.Println()
+		fmt.Println(bold("Links:"))
+		for _, rel := range relations {
+			fmt.Printf("  %s #%s %s\n", rel.Kind, rel.ID, relationState(rel))
+		}
+	}
+
+	// Children and their status
+	if len(children) > 0 {
+		fmt.Println()
+		fmt.Println(bold("Children:"))
+		for _, child := range children {
+			childItem := issueWithStatus{issue: child.Issue, dir: child.Dir, status: cfg.Workflow.StatusOf(child.Issue, child.Dir)}
+			fmt.Printf("  #%s %s %s\n", child.Issue.ID, child.Issue.Title, colorStatus(childItem, false))
+		}
+	}
+
 	// Body
 	if issue.Body != "" {
 		fmt.Println()
@@ -99,5 +160,56 @@ func runShow(cmd *cobra.Command, args []string) error {
 		fmt.Println(issue.Body)
 	}
 
+	// Comment thread
+	if len(issue.Comments) > 0 {
+		fmt.Println()
+		fmt.Println(strings.Repeat("-", 60))
+		fmt.Println(bold(fmt.Sprintf("Comments (%d)", len(issue.Comments))))
+		for _, comment := range issue.Comments {
+			fmt.Println()
+			fmt.Printf("%s %s\n", bold(comment.Author), color.New(color.FgHiBlack).Sprint(comment.Created.Format("2006-01-02 15:04:05")))
+			for _, line := range strings.Split(comment.Body, "\n") {
+				fmt.Printf("  %s\n", line)
+			}
+		}
+	}
+
 	return nil
 }
+
+// customFieldNames orders an issue's custom fields: those declared in
+// config.yaml first (in declaration order), then the rest alphabetically
+func customFieldNames(issue *pkg.Issue, cfg *pkg.Config) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, field := range cfg.Fields {
+		if _, ok := issue.Field(field.Name); ok {
+			names = append(names, field.Name)
+			seen[field.Name] = true
+		}
+	}
+
+	var others []string
+	for name := range issue.Extra {
+		if !seen[name] {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+
+	return append(names, others...)
+}
+
+// relationState describes the linked issue of a relation: its title and
+// whether it's closed or missing
+func relationState(rel pkg.Relation) string {
+	gray := color.New(color.FgHiBlack).SprintFunc()
+	switch rel.Dir {
+	case "":
+		return gray("(missing)")
+	case pkg.ClosedDir:
+		return rel.Title + " " + gray("(closed)")
+	}
+	return rel.Title
+}
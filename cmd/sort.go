@@ -0,0 +1,199 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+// sortKeys are the built-in values accepted by --sort; custom fields declared
+// in config.yaml are accepted too
+var sortKeys = []string{"id", "title", "status", "assignee", "created", "updated", "priority", "due", "milestone", "parent", "path"}
+
+// sortKey is one --sort key with its direction
+type sortKey struct {
+	name string
+	desc bool
+}
+
+// parseSortKeys splits a --sort value ("priority,created:desc") and checks each key
+func parseSortKeys(value string, cfg *pkg.Config) ([]sortKey, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+
+	var keys []sortKey
+	for _, part := range strings.Split(value, ",") {
+		name, direction, _ := strings.Cut(strings.TrimSpace(part), ":")
+		key := sortKey{name: strings.TrimSpace(name)}
+
+		switch strings.TrimSpace(direction) {
+		case "", "asc":
+		case "desc":
+			key.desc = true
+		default:
+			return nil, fmt.Errorf("invalid sort direction in %q (must be asc or desc)", part)
+		}
+
+		if !containsKey(sortKeys, key.name) && cfg.Field(key.name) == nil {
+			return nil, fmt.Errorf("invalid sort key: %q (must be one of: %s, or a custom field)", key.name, strings.Join(sortKeys, ", "))
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// sortIssues orders issues by the keys in turn. Issues without a value for a
+// key sort after those with one, in either direction; ties keep their order.
+func sortIssues(items []issueWithStatus, keys []sortKey, cfg *pkg.Config) {
+	if len(keys) == 0 {
+		return
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		for _, key := range keys {
+			if c := compareIssues(items[i], items[j], key, cfg); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// compareIssues compares two issues on a single sort key
+func compareIssues(a, b issueWithStatus, key sortKey, cfg *pkg.Config) int {
+	aOK, bOK := hasSortValue(a, key.name), hasSortValue(b, key.name)
+	switch {
+	case !aOK && !bOK:
+		return 0
+	case !bOK:
+		return -1
+	case !aOK:
+		return 1
+	}
+
+	c := compareValues(a, b, key.name, cfg)
+	if key.desc {
+		return -c
+	}
+	return c
+}
+
+// hasSortValue reports whether an issue has a value to sort on
+func hasSortValue(item issueWithStatus, name string) bool {
+	switch name {
+	case "id", "title", "status", "created", "updated", "path":
+		return true
+	case "assignee":
+		return item.issue.Assignee != ""
+	case "parent":
+		return item.issue.Parent != ""
+	case "due":
+		_, ok := item.issue.Due()
+		return ok
+	}
+	value, _ := item.issue.Field(name)
+	return pkg.FormatFieldValue(value) != ""
+}
+
+// compareValues compares the values two issues have for a sort key
+func compareValues(a, b issueWithStatus, name string, cfg *pkg.Config) int {
+	switch name {
+	case "id":
+		return compareIDs(a.issue.ID, b.issue.ID)
+	case "title":
+		return strings.Compare(strings.ToLower(a.issue.Title), strings.ToLower(b.issue.Title))
+	case "status":
+		return strings.Compare(a.status, b.status)
+	case "assignee":
+		return strings.Compare(a.issue.Assignee, b.issue.Assignee)
+	case "created":
+		return a.issue.Created.Compare(b.issue.Created)
+	case "updated":
+		return a.issue.Updated.Compare(b.issue.Updated)
+	case "parent":
+		return compareIDs(a.issue.Parent, b.issue.Parent)
+	case "path":
+		return strings.Compare(a.issue.Path, b.issue.Path)
+	case "priority":
+		return cfg.PriorityRank(a.issue.Priority()) - cfg.PriorityRank(b.issue.Priority())
+	case "due":
+		aDue, _ := a.issue.Due()
+		bDue, _ := b.issue.Due()
+		return aDue.Compare(bDue)
+	}
+
+	aValue, _ := a.issue.Field(name)
+	bValue, _ := b.issue.Field(name)
+	return compareFieldValues(cfg.Field(name), pkg.FormatFieldValue(aValue), pkg.FormatFieldValue(bValue))
+}
+
+// compareFieldValues compares custom field values by the field's type: ints
+// numerically, enums in declaration order and everything else as text (which
+// orders YYYY-MM-DD dates too)
+func compareFieldValues(field *pkg.FieldDef, a, b string) int {
+	if field != nil {
+		switch field.Type {
+		case pkg.FieldInt:
+			aNum, aErr := strconv.Atoi(a)
+			bNum, bErr := strconv.Atoi(b)
+			if aErr == nil && bErr == nil {
+				return aNum - bNum
+			}
+		case pkg.FieldEnum:
+			return enumRank(field.Values, a) - enumRank(field.Values, b)
+		}
+	}
+	return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+}
+
+// enumRank returns the position of value in values, or len(values) if absent
+func enumRank(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return len(values)
+}
+
+// compareIDs orders numeric IDs by value and others as strings
+func compareIDs(a, b string) int {
+	if len(a) != len(b) && isDigits(a) && isDigits(b) {
+		return len(a) - len(b)
+	}
+	return strings.Compare(a, b)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// paginate applies --offset and --limit; a limit of 0 means no limit
+func paginate(items []issueWithStatus, offset, limit int) []issueWithStatus {
+	if offset >= len(items) {
+		return nil
+	}
+	items = items[offset:]
+	if limit > 0 && limit < len(items) {
+		items = items[:limit]
+	}
+	return items
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestSortIssuesByCustomField(t *testing.T) {
+	cfg, err := pkg.ParseConfig([]byte("fields:\n  - name: estimate\n    type: int\n  - name: size\n    type: enum\n    values: [s, m, l]\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	item := func(id string, estimate int, size string) issueWithStatus {
+		issue := &pkg.Issue{ID: id}
+		if estimate > 0 {
+			issue.SetField("estimate", estimate)
+		}
+		if size != "" {
+			issue.SetField("size", size)
+		}
+		return issueWithStatus{issue: issue, dir: pkg.OpenDir}
+	}
+	ids := func(items []issueWithStatus) string {
+		var ids []string
+		for _, item := range items {
+			ids = append(ids, item.issue.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+	items := []issueWithStatus{
+		item("001", 3, "l"),
+		item("002", 0, "s"),
+		item("003", 10, "m"),
+		item("004", 2, ""),
+	}
+
+	tests := []struct {
+		sort string
+		want string
+	}{
+		{"estimate", "004,001,003,002"},
+		// Issues without a value stay last when descending
+		{"estimate:desc", "003,001,004,002"},
+		{"size", "002,003,001,004"},
+		{"size:desc,id", "001,003,002,004"},
+		{"id:desc", "004,003,002,001"},
+	}
+	for _, tt := range tests {
+		keys, err := parseSortKeys(tt.sort, cfg)
+		if err != nil {
+			t.Fatalf("parseSortKeys(%q) error = %v", tt.sort, err)
+		}
+		sorted := append([]issueWithStatus(nil), items...)
+		sortIssues(sorted, keys, cfg)
+		if got := ids(sorted); got != tt.want {
+			t.Errorf("sort %q = %s, want %s", tt.sort, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"estimate:down", "color"} {
+		if _, err := parseSortKeys(bad, cfg); err == nil {
+			t.Errorf("parseSortKeys(%q) should fail", bad)
+		}
+	}
+
+	if got := ids(paginate(items, 1, 2)); got != "002,003" {
+		t.Errorf("paginate(1, 2) = %s, want 002,003", got)
+	}
+	if got := paginate(items, 5, 0); len(got) != 0 {
+		t.Errorf("paginate() past the end = %d issue(s), want 0", len(got))
+	}
+}
+
+func TestListColumnsAndPagination(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	for _, title := range []string{"First", "Second", "Third"} {
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := &pkg.Config{}
+	if _, err := parseColumns("id,title,size", cfg); err == nil {
+		t.Error("parseColumns() should reject an unknown column")
+	}
+	columns, err := parseColumns("id, updated ,path", cfg)
+	if err != nil || strings.Join(columns, ",") != "id,updated,path" {
+		t.Fatalf("parseColumns() = %v, %v", columns, err)
+	}
+
+	issue, _, _ := pkg.LoadIssue("002")
+	item := issueWithStatus{issue: issue, dir: pkg.OpenDir}
+	if got := issueCell(item, "path", nil); !strings.HasPrefix(got, ".issues/open/002-") {
+		t.Errorf("path cell = %q, want a path relative to the working directory", got)
+	}
+	if got := issueCell(item, "assignee", nil); got != "-" {
+		t.Errorf("empty assignee cell = %q, want -", got)
+	}
+
+	listSort = "id:desc"
+	listLimit = 1
+	listOffset = 1
+	listColumns = "id,title,created,path"
+	if err := runList(nil, nil); err != nil {
+		t.Errorf("runList() failed: %v", err)
+	}
+
+	listOutput.format = "json"
+	if err := runList(nil, nil); err == nil {
+		t.Error("runList() should reject --columns with JSON output")
+	}
+	listColumns = ""
+	listOffset = -1
+	if err := runList(nil, nil); err == nil {
+		t.Error("runList() should reject a negative offset")
+	}
+}
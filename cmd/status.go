@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	statusCommit bool
+	statusStrict bool
+	statusForce  bool
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status <issue-id> [state]",
+	Short: "Show or change an issue's workflow state",
+	Long: `Show or change an issue's workflow state.
+
+Without a state, prints the issue's current state and the states it can move to.
+States and allowed transitions are declared under 'workflow' in .issues/config.yaml;
+open and closed are always available. Issues in closed states are stored in
+.issues/closed/, all others in .issues/open/.
+
+Moving an open issue to a closed state is checked as 'gi close' checks it:
+open blockers and unchecked success criteria are warned about, or refused
+with --strict, and open children need confirming unless --force is given.
+
+Examples:
+  gi status 001               # Show current state and allowed transitions
+  gi status 001 in-progress   # Move issue to in-progress
+  gi status 001 done --commit # Move issue to done and commit the change`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().BoolVarP(&statusCommit, "commit", "c", false, "Auto-commit the change to git")
+	statusCmd.Flags().BoolVar(&statusStrict, "strict", false, "Refuse to close an issue with open blockers or unchecked success criteria")
+	statusCmd.Flags().BoolVarP(&statusForce, "force", "f", false, "Close a parent issue even if it has open children")
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	issueID := args[0]
+
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Load the issue to check its current state
+	issue, currentDir, err := pkg.LoadIssue(issueID)
+	if err != nil {
+		return fmt.Errorf("failed to load issue: %w", err)
+	}
+	current := issueWithStatus{issue: issue, dir: currentDir, status: cfg.Workflow.StatusOf(issue, currentDir)}
+
+	if len(args) == 1 {
+		fmt.Printf("Issue #%s is %s\n", issue.ID, colorStatus(current, true))
+		if allowed := cfg.Workflow.AllowedTransitions(current.status); len(allowed) > 0 {
+			fmt.Printf("Can move to: %s\n", strings.Join(allowed, ", "))
+		} else {
+			fmt.Println("No transitions allowed from this state")
+		}
+		return nil
+	}
+
+	state := args[1]
+	var opts pkg.CloseOptions
+	if dir, err := cfg.Workflow.DirFor(state); err == nil && dir == pkg.ClosedDir && currentDir == pkg.OpenDir && cfg.Workflow.CanTransition(current.status, state) {
+		if opts, err = prepareClose(issue.ID, statusStrict, statusForce); err != nil {
+			return err
+		}
+	}
+	if err := pkg.TransitionIssue(issue.ID, state, opts); err != nil {
+		return fmt.Errorf("failed to change status: %w", closeError(err, issue.ID))
+	}
+
+	fmt.Printf("✓ Moved issue #%s from %s to %s\n", issue.ID, current.status, state)
+
+	// Handle git commit if requested
+	if statusCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Move issue #%s to %s", issue.ID, state)); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func writeWorkflowConfig(t *testing.T) {
+	t.Helper()
+
+	config := `workflow:
+  states:
+    - name: in-progress
+    - name: done
+      closed: true
+  transitions:
+    open: [in-progress]
+    in-progress: [done]
+    done: [open]
+`
+	if err := os.WriteFile(filepath.Join(pkg.IssuesDir, pkg.ConfigFile), []byte(config), 0644); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+}
+
+func TestRunStatusTransitions(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+	writeWorkflowConfig(t)
+
+	if err := runCreate(nil, []string{"Workflow issue"}); err != nil {
+		t.Fatalf("runCreate() failed: %v", err)
+	}
+
+	if err := runStatus(nil, []string{"001"}); err != nil {
+		t.Fatalf("runStatus() without state failed: %v", err)
+	}
+
+	err := runStatus(nil, []string{"001", "done"})
+	if err == nil || !strings.Contains(err.Error(), "can't move issue") {
+		t.Fatalf("runStatus(open -> done) error = %v, want disallowed transition", err)
+	}
+
+	if err := runStatus(nil, []string{"001", "in-progress"}); err != nil {
+		t.Fatalf("runStatus(in-progress) failed: %v", err)
+	}
+	issue, dir, err := pkg.LoadIssue("001")
+	if err != nil {
+		t.Fatalf("failed to load issue: %v", err)
+	}
+	if dir != pkg.OpenDir || issue.Status != "in-progress" {
+		t.Errorf("issue = %s in %s, want in-progress in open", issue.Status, dir)
+	}
+
+	if err := runStatus(nil, []string{"001", "done"}); err != nil {
+		t.Fatalf("runStatus(done) failed: %v", err)
+	}
+	if _, dir, _ := pkg.LoadIssue("001"); dir != pkg.ClosedDir {
+		t.Errorf("done issue should be in closed dir, got %s", dir)
+	}
+}
+
+func TestRunStatusChecksClosing(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Epic"}); err != nil {
+		t.Fatal(err)
+	}
+	createParent = "001"
+	if err := runCreate(nil, []string{"Child"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without a terminal to prompt on, closing a parent needs --force
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() {
+		os.Stdin = stdin
+		_ = r.Close()
+	}()
+
+	err = runStatus(nil, []string{"001", "closed"})
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("runStatus(closed) error = %v, want a hint about --force", err)
+	}
+	if _, dir, _ := pkg.LoadIssue("001"); dir != pkg.OpenDir {
+		t.Fatalf("a refused status change moved the issue to %s", dir)
+	}
+
+	statusForce = true
+	if err := runStatus(nil, []string{"001", "closed"}); err != nil {
+		t.Fatalf("runStatus(closed, --force) failed: %v", err)
+	}
+	if _, dir, _ := pkg.LoadIssue("001"); dir != pkg.ClosedDir {
+		t.Errorf("closed issue should be in closed dir, got %s", dir)
+	}
+}
+
+func TestListByWorkflowState(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+	defer func() { listStatus = "" }()
+	writeWorkflowConfig(t)
+
+	for _, title := range []string{"First", "Second"} {
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatalf("runCreate() failed: %v", err)
+		}
+	}
+	if err := runStatus(nil, []string{"002", "in-progress"}); err != nil {
+		t.Fatalf("runStatus() failed: %v", err)
+	}
+
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dirs, state, err := resolveStatusFilter("in-progress", &cfg.Workflow)
+	if err != nil {
+		t.Fatalf("resolveStatusFilter() error = %v", err)
+	}
+	var matched []string
+	for _, item := range collectIssues(dirs, &cfg.Workflow) {
+		if item.status == state {
+			matched = append(matched, item.issue.ID)
+		}
+	}
+	if len(matched) != 1 || matched[0] != "002" {
+		t.Errorf("in-progress issues = %v, want [002]", matched)
+	}
+
+	// open still selects everything in open/, whatever its workflow state
+	dirs, state, err = resolveStatusFilter("open", &cfg.Workflow)
+	if err != nil || state != "" || len(collectIssues(dirs, &cfg.Workflow)) != 2 {
+		t.Errorf("--status open should match both open issues")
+	}
+
+	for _, status := range []string{"in-progress", "done", "open", "closed"} {
+		listStatus = status
+		if err := runList(nil, []string{}); err != nil {
+			t.Errorf("runList(--status %s) failed: %v", status, err)
+		}
+	}
+
+	listStatus = "someday"
+	if err := runList(nil, []string{}); err == nil || !strings.Contains(err.Error(), "in-progress") {
+		t.Errorf("runList() with unknown state error = %v, want list of valid states", err)
+	}
+}
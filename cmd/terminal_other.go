@@ -0,0 +1,25 @@
+//go:build !(aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris)
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+)
+
+// terminal is a stub for platforms without termios; openTerminal always fails
+type terminal struct{}
+
+func openTerminal(in, out *os.File) (*terminal, error) {
+	return nil, fmt.Errorf("the terminal UI isn't supported on this platform")
+}
+
+func (t *terminal) makeRaw() error { return nil }
+
+func (t *terminal) restore() error { return nil }
+
+func (t *terminal) size() (int, int, error) { return 0, 0, fmt.Errorf("no terminal") }
+
+func notifyResize(ch chan<- os.Signal) {}
+
+func terminalWidth(f *os.File) int { return 0 }
@@ -0,0 +1,10 @@
+//go:build aix || linux || solaris
+
+package cmd
+
+import "golang.org/x/sys/unix"
+
+const (
+	ioctlGetTermios = unix.TCGETS
+	ioctlSetTermios = unix.TCSETS
+)
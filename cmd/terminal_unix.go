@@ -0,0 +1,80 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminal switches a terminal between raw mode, for reading single key
+// presses, and the mode it was in before
+type terminal struct {
+	in    *os.File
+	out   *os.File
+	saved *unix.Termios
+}
+
+// openTerminal puts the terminal on in into raw mode
+func openTerminal(in, out *os.File) (*terminal, error) {
+	saved, err := unix.IoctlGetTermios(int(in.Fd()), ioctlGetTermios)
+	if err != nil {
+		return nil, fmt.Errorf("standard input isn't a terminal")
+	}
+	t := &terminal{in: in, out: out, saved: saved}
+	if err := t.makeRaw(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// makeRaw turns off line editing, echo, signal keys and output processing
+func (t *terminal) makeRaw() error {
+	raw := *t.saved
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, &raw); err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	return nil
+}
+
+// restore puts the terminal back into the mode it was in when opened
+func (t *terminal) restore() error {
+	if err := unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, t.saved); err != nil {
+		return fmt.Errorf("failed to restore the terminal: %w", err)
+	}
+	return nil
+}
+
+// size returns the width and height of the terminal in cells
+func (t *terminal) size() (int, int, error) {
+	ws, err := unix.IoctlGetWinsize(int(t.out.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get the terminal size: %w", err)
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// notifyResize sends on ch when the terminal is resized
+func notifyResize(ch chan<- os.Signal) {
+	signal.Notify(ch, unix.SIGWINCH)
+}
+
+// terminalWidth returns the width in cells of the terminal f writes to, or 0
+// when f isn't a terminal
+func terminalWidth(f *os.File) int {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0
+	}
+	return int(ws.Col)
+}
@@ -0,0 +1,45 @@
+// Checks the web UI's Markdown renderer: node markdown_test.js <markdown.js>
+"use strict";
+
+global.window = {};
+require(require("path").resolve(process.argv[2]));
+var render = window.markdown.render;
+
+var failed = 0;
+function expect(name, markdown, want) {
+  var got = render(markdown);
+  if (got !== want) {
+    console.log(name + ":\n  render(" + JSON.stringify(markdown) + ")\n  = " + got + "\n  want " + want);
+    failed++;
+  }
+}
+
+expect(
+  "inline markup",
+  "See **the logs**, [the *runbook*](https://example.com), `x := 1` and #12",
+  '<p>See <strong>the logs</strong>, <a href="https://example.com" rel="noopener noreferrer" target="_blank">the <em>runbook</em></a>, <code>x := 1</code> and <a href="#/issues/12">#12</a></p>'
+);
+expect(
+  "bare URL",
+  "Logs at https://example.com/a_b_c",
+  '<p>Logs at <a href="https://example.com/a_b_c" rel="noopener noreferrer" target="_blank">https://example.com/a_b_c</a></p>'
+);
+expect(
+  "unsafe link",
+  "[click](javascript:alert(1))",
+  '<p><a href="#" rel="noopener noreferrer" target="_blank">click</a>)</p>'
+);
+// A URL inside a link's target must not become a second link breaking the
+// href open
+expect(
+  "URL inside a link",
+  "[click](/x(https://e/onmouseover=location='javascript:alert\\x281\\x29'//)",
+  '<p><a href="/x(https://e/onmouseover=location=&#39;javascript:alert\\x281\\x29&#39;//" rel="noopener noreferrer" target="_blank">click</a></p>'
+);
+expect(
+  "URL inside an image",
+  "![x](/x(https://e/onerror=alert(1)//)",
+  '<p><img alt="x" src="/x(https://e/onerror=alert(1">//)</p>'
+);
+
+process.exit(failed ? 1 : 0);
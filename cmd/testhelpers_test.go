@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -33,19 +34,106 @@ func setupCommandTestRepo(t *testing.T) (string, func()) {
 
 	createAssignee = ""
 	createLabels = []string{}
+	createFields = []string{}
+	createParent = ""
+	createPriority = ""
+	createDue = ""
+	createMilestone = ""
 
 	cleanup := func() {
 		_ = os.Chdir(originalDir)
 		_ = os.RemoveAll(tmpDir)
 		closeCommit = false
+		closeStrict = false
+		linkCommit = false
+		unlinkCommit = false
 		openCommit = false
+		statusCommit = false
+		statusStrict = false
+		statusForce = false
+		renumberCommit = false
+		renumberDryRun = false
+		renumberForce = false
+		doctorFix = false
+		commentAuthor = ""
+		commentFile = ""
+		commentCommit = false
+		listOutput.reset()
+		searchOutput.reset()
+		searchNoIndex = false
+		showOutput.reset()
 		createAssignee = ""
 		createLabels = []string{}
+		createFields = []string{}
+		createParent = ""
+		createPriority = ""
+		createDue = ""
+		listSort = ""
+		listLimit = 0
+		listOffset = 0
+		listColumns = ""
+		listQuery = ""
+		listView = ""
+		viewSaveQuery = ""
+		viewSaveSort = ""
+		viewSaveColumns = ""
+		viewSaveAll = false
+		viewSaveDescription = ""
+		viewSaveForce = false
+		viewPersonal = false
+		viewCommit = false
+		overdueDays = 0
+		overdueOutput.reset()
+		closeForce = false
+		checkUncheck = false
+		checkCommit = false
+		listTree = false
+		parentClear = false
+		parentCommit = false
+		createMilestone = ""
+		listMilestone = ""
+		milestoneStart = ""
+		milestoneDue = ""
+		milestoneDescription = ""
+		milestoneAll = false
+		milestoneCommit = false
+		boardBy = boardByStatus
+		boardAll = false
+		boardQuery = ""
+		boardSort = ""
+		boardLimit = 0
+		boardWidth = 0
+		boardFormat = formatTable
 	}
 
 	return tmpDir, cleanup
 }
 
+// captureStdout returns what fn prints to standard output
+func captureStdout(t *testing.T, fn func() error) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(r)
+		done <- string(data)
+	}()
+
+	fnErr := fn()
+	_ = w.Close()
+	out := <-done
+	_ = r.Close()
+	return out, fnErr
+}
+
 func runGitCommand(t *testing.T, dir string, args ...string) {
 	t.Helper()
 
@@ -76,3 +164,17 @@ func gitLastCommitMessage(t *testing.T, dir string) string {
 
 	return strings.TrimSpace(string(output))
 }
+
+// setCriteria replaces the body of issue id with unchecked success criteria,
+// which unlike the template's placeholders count as work left undone
+func setCriteria(t *testing.T, id string, criteria ...string) {
+	t.Helper()
+
+	body := "## Success Criteria\n\n"
+	for _, criterion := range criteria {
+		body += "- [ ] " + criterion + "\n"
+	}
+	if _, _, err := pkg.UpdateIssue(id, pkg.IssueUpdate{Body: &body}); err != nil {
+		t.Fatalf("failed to set the success criteria of issue %s: %v", id, err)
+	}
+}
@@ -0,0 +1,671 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/spf13/cobra"
+)
+
+var (
+	tuiAll   bool
+	tuiQuery string
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Browse and triage issues in an interactive terminal UI",
+	Long: `Browse and triage issues in a full-screen terminal UI: a scrollable list
+with a live fuzzy filter next to the details of the selected issue.
+
+Keys:
+  ↑/↓ j/k         Move through the list (pgup/pgdn, home/end, g/G jump)
+  /               Filter as you type; enter keeps the filter, esc clears it
+  enter, tab      Focus the details to scroll them (narrow terminals show
+                  them instead of the list)
+  c / o           Close / reopen the selected issue
+  e               Edit the issue in $EDITOR
+  a               Set the assignee (empty unassigns)
+  l               Set the labels, comma-separated
+  r               Reload the issues from disk
+  q, ctrl+c       Quit
+
+By default only open issues are listed; --all includes closed ones and
+--query narrows the list with a query expression (see 'gi list --help').`,
+	Args: cobra.NoArgs,
+	RunE: runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+	tuiCmd.Flags().BoolVarP(&tuiAll, "all", "a", false, "Include closed issues")
+	tuiCmd.Flags().StringVarP(&tuiQuery, "query", "q", "", "Only list issues matching a query expression")
+}
+
+// tuiAction tells the event loop what to do after a key press
+type tuiAction int
+
+const (
+	tuiContinue tuiAction = iota
+	tuiQuit
+	tuiEdit
+)
+
+// tuiPrompt is a line of input the user is typing, such as a new assignee
+type tuiPrompt struct {
+	label   string
+	text    string
+	confirm bool // a y/n question answered by a single key
+	submit  func(text string)
+}
+
+// tuiModel holds the state of the terminal UI. Keys change it through
+// handleKey and render draws it, so both work without a terminal.
+type tuiModel struct {
+	cfg     *pkg.Config
+	all     bool
+	queries []*pkg.Query
+
+	issues  []issueWithStatus // everything loaded
+	visible []issueWithStatus // the issues matching the filter, best first
+
+	width, height int
+	cursor        int // index into visible
+	offset        int // first visible row of the list
+	detailScroll  int
+	showDetail    bool // narrow terminals: show the details instead of the list
+	focusDetail   bool // the arrow keys scroll the details
+
+	filtering bool
+	filter    string
+	prompt    *tuiPrompt
+
+	message    string
+	messageErr bool
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	// Check if repository is initialized
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+	if !stdinIsTerminal() {
+		return fmt.Errorf("gi tui needs a terminal")
+	}
+
+	m, err := newTUIModel(tuiAll, tuiQuery)
+	if err != nil {
+		return err
+	}
+
+	term, err := openTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l") // alternate screen, hide the cursor
+	defer func() {
+		fmt.Print("\x1b[?25h\x1b[?1049l")
+		_ = term.restore()
+	}()
+
+	keys := make(chan []string)
+	next := make(chan struct{})
+	go readKeys(os.Stdin, next, keys)
+	resized := make(chan os.Signal, 1)
+	notifyResize(resized)
+
+	next <- struct{}{}
+	for {
+		m.width, m.height, err = term.size()
+		if err != nil {
+			return err
+		}
+		fmt.Print("\x1b[H" + strings.Join(m.render(), "\r\n"))
+
+		select {
+		case <-resized:
+			fmt.Print("\x1b[2J")
+			continue
+		case pressed, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, key := range pressed {
+				switch m.handleKey(key) {
+				case tuiQuit:
+					return nil
+				case tuiEdit:
+					m.editSelected(term)
+				}
+			}
+		}
+		// Only read again once the keys have been handled, so an editor
+		// started for one of them gets the keyboard to itself
+		next <- struct{}{}
+	}
+}
+
+// readKeys reads key presses from in each time it's told to on next
+func readKeys(in *os.File, next <-chan struct{}, keys chan<- []string) {
+	buf := make([]byte, 256)
+	for range next {
+		n, err := in.Read(buf)
+		if err != nil {
+			close(keys)
+			return
+		}
+		keys <- parseKeys(buf[:n])
+	}
+}
+
+// escapeKeys names the escape sequences of the special keys
+var escapeKeys = map[string]string{
+	"\x1b[A": "up", "\x1b[B": "down", "\x1b[C": "right", "\x1b[D": "left",
+	"\x1bOA": "up", "\x1bOB": "down", "\x1bOC": "right", "\x1bOD": "left",
+	"\x1b[H": "home", "\x1b[F": "end", "\x1bOH": "home", "\x1bOF": "end",
+	"\x1b[1~": "home", "\x1b[4~": "end", "\x1b[7~": "home", "\x1b[8~": "end",
+	"\x1b[5~": "pgup", "\x1b[6~": "pgdown", "\x1b[3~": "delete",
+}
+
+// parseKeys splits terminal input into keys: "up", "enter", "ctrl+c" and so
+// on for special keys, the character itself for anything else
+func parseKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		if input[0] == 0x1b {
+			if len(input) == 1 {
+				keys = append(keys, "esc")
+				break
+			}
+			// A CSI or SS3 sequence runs to its final byte
+			end := 0
+			if input[1] == '[' || input[1] == 'O' {
+				for i := 2; i < len(input); i++ {
+					if input[i] >= 0x40 && input[i] <= 0x7e {
+						end = i + 1
+						break
+					}
+				}
+			}
+			if end == 0 {
+				keys = append(keys, "esc")
+				input = input[1:]
+				continue
+			}
+			if name, ok := escapeKeys[string(input[:end])]; ok {
+				keys = append(keys, name)
+			}
+			input = input[end:]
+			continue
+		}
+
+		switch c := input[0]; {
+		case c == '\r' || c == '\n':
+			keys = append(keys, "enter")
+		case c == '\t':
+			keys = append(keys, "tab")
+		case c == 0x7f || c == 0x08:
+			keys = append(keys, "backspace")
+		case c < 0x20:
+			keys = append(keys, "ctrl+"+string(rune('a'+c-1)))
+		default:
+			r, size := utf8.DecodeRune(input)
+			keys = append(keys, string(r))
+			input = input[size:]
+			continue
+		}
+		input = input[1:]
+	}
+	return keys
+}
+
+// newTUIModel loads the issues to browse
+func newTUIModel(all bool, query string) (*tuiModel, error) {
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	m := &tuiModel{cfg: cfg, all: all, width: 80, height: 24}
+	if query != "" {
+		q, err := pkg.ParseQuery(query, cfg)
+		if err != nil {
+			return nil, err
+		}
+		m.queries = append(m.queries, q)
+	}
+	m.reload()
+	return m, nil
+}
+
+// reload reads the issues again, keeping the selected issue selected if it's
+// still listed
+func (m *tuiModel) reload() {
+	var selectedID string
+	if item := m.selected(); item != nil {
+		selectedID = item.issue.ID
+	}
+
+	dirs := []string{pkg.OpenDir}
+	if m.all || queriesUseStatus(m.queries) {
+		dirs = append(dirs, pkg.ClosedDir)
+	}
+	m.issues = m.issues[:0]
+	for _, item := range collectIssues(dirs, &m.cfg.Workflow) {
+		if matchesQueries(item, m.queries) {
+			m.issues = append(m.issues, item)
+		}
+	}
+	m.applyFilter()
+
+	for i, item := range m.visible {
+		if item.issue.ID == selectedID {
+			m.cursor = i
+		}
+	}
+	m.clampCursor()
+}
+
+// applyFilter narrows the list to the issues fuzzy-matching the filter, best
+// matches first
+func (m *tuiModel) applyFilter() {
+	if m.filter == "" {
+		m.visible = append(m.visible[:0], m.issues...)
+		return
+	}
+
+	type match struct {
+		item  issueWithStatus
+		score int
+	}
+	var matches []match
+	for _, item := range m.issues {
+		if score, ok := fuzzyScore(m.filter, tuiSearchText(item)); ok {
+			matches = append(matches, match{item, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+
+	m.visible = m.visible[:0]
+	for _, match := range matches {
+		m.visible = append(m.visible, match.item)
+	}
+}
+
+// tuiSearchText is what the filter matches against
+func tuiSearchText(item issueWithStatus) string {
+	issue := item.issue
+	return strings.Join([]string{"#" + issue.ID, issue.Title, strings.Join(issue.Labels, " "), issue.Assignee, item.status}, " ")
+}
+
+// fuzzyScore reports whether every space-separated term of pattern appears in
+// text with its characters in order, ignoring case, and scores the match:
+// characters that follow each other or start a word count for more
+func fuzzyScore(pattern, text string) (int, bool) {
+	target := []rune(strings.ToLower(text))
+	total := 0
+	for _, term := range strings.Fields(strings.ToLower(pattern)) {
+		score, pos, prev := 0, 0, -2
+		for _, r := range term {
+			for pos < len(target) && target[pos] != r {
+				pos++
+			}
+			if pos == len(target) {
+				return 0, false
+			}
+			score++
+			if pos == prev+1 {
+				score += 4
+			}
+			if pos == 0 || !unicode.IsLetter(target[pos-1]) && !unicode.IsDigit(target[pos-1]) {
+				score += 2
+			}
+			prev = pos
+			pos++
+		}
+		if strings.Contains(string(target), term) {
+			score += 2 * utf8.RuneCountInString(term)
+		}
+		total += score
+	}
+	return total, true
+}
+
+// selected returns the issue under the cursor, or nil when the list is empty
+func (m *tuiModel) selected() *issueWithStatus {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return nil
+	}
+	return &m.visible[m.cursor]
+}
+
+func (m *tuiModel) clampCursor() {
+	m.cursor = min(m.cursor, len(m.visible)-1)
+	m.cursor = max(m.cursor, 0)
+}
+
+// moveCursor moves the selection by delta rows
+func (m *tuiModel) moveCursor(delta int) {
+	before := m.cursor
+	m.cursor += delta
+	m.clampCursor()
+	if m.cursor != before {
+		m.detailScroll = 0
+	}
+}
+
+// pageSize is the number of list rows on the screen
+func (m *tuiModel) pageSize() int {
+	return max(m.height-4, 1)
+}
+
+// notify shows a message on the status line
+func (m *tuiModel) notify(format string, args ...interface{}) {
+	m.message, m.messageErr = fmt.Sprintf(format, args...), false
+}
+
+// fail shows an error on the status line
+func (m *tuiModel) fail(err error) {
+	m.message, m.messageErr = err.Error(), true
+	if errors.Is(err, pkg.ErrIssueModified) {
+		m.message += " (press r to reload)"
+	}
+}
+
+// handleKey applies a key press
+func (m *tuiModel) handleKey(key string) tuiAction {
+	if key == "ctrl+c" {
+		return tuiQuit
+	}
+	if m.prompt != nil {
+		m.promptKey(key)
+		return tuiContinue
+	}
+	if m.filtering {
+		m.filterKey(key)
+		return tuiContinue
+	}
+	m.message = ""
+
+	if m.focusDetail {
+		switch key {
+		case "up", "k":
+			m.detailScroll = max(m.detailScroll-1, 0)
+		case "down", "j":
+			m.detailScroll++
+		case "pgup", "ctrl+b":
+			m.detailScroll = max(m.detailScroll-m.pageSize(), 0)
+		case "pgdown", "ctrl+f", " ":
+			m.detailScroll += m.pageSize()
+		case "home", "g":
+			m.detailScroll = 0
+		case "tab", "enter", "esc", "left", "h":
+			m.focusDetail, m.showDetail = false, false
+		case "q":
+			return tuiQuit
+		default:
+			return m.actionKey(key)
+		}
+		return tuiContinue
+	}
+
+	switch key {
+	case "up", "k":
+		m.moveCursor(-1)
+	case "down", "j":
+		m.moveCursor(1)
+	case "pgup", "ctrl+b":
+		m.moveCursor(-m.pageSize())
+	case "pgdown", "ctrl+f":
+		m.moveCursor(m.pageSize())
+	case "home", "g":
+		m.moveCursor(-len(m.visible))
+	case "end", "G":
+		m.moveCursor(len(m.visible))
+	case "/":
+		m.filtering = true
+	case "esc":
+		if m.filter != "" {
+			m.filter = ""
+			m.reload()
+		}
+	case "tab", "enter", "right":
+		if m.selected() != nil {
+			m.focusDetail = true
+			m.showDetail = m.width < tuiSplitWidth
+		}
+	case "q":
+		return tuiQuit
+	default:
+		return m.actionKey(key)
+	}
+	return tuiContinue
+}
+
+// actionKey handles the keys that change the selected issue
+func (m *tuiModel) actionKey(key string) tuiAction {
+	switch key {
+	case "r":
+		m.reload()
+		m.notify("Reloaded %d issue(s)", len(m.issues))
+	case "c":
+		m.closeSelected()
+	case "o":
+		m.reopenSelected()
+	case "e":
+		if m.selected() != nil {
+			return tuiEdit
+		}
+	case "a":
+		m.assignSelected()
+	case "l":
+		m.labelSelected()
+	}
+	return tuiContinue
+}
+
+// filterKey edits the filter, narrowing the list as it changes
+func (m *tuiModel) filterKey(key string) {
+	switch key {
+	case "enter", "down", "up":
+		m.filtering = false
+		if key != "enter" {
+			m.handleKey(key)
+		}
+		return
+	case "esc":
+		m.filtering = false
+		m.filter = ""
+	case "backspace":
+		_, size := utf8.DecodeLastRuneInString(m.filter)
+		m.filter = m.filter[:len(m.filter)-size]
+	case "ctrl+u":
+		m.filter = ""
+	default:
+		if utf8.RuneCountInString(key) != 1 {
+			return
+		}
+		m.filter += key
+	}
+	m.applyFilter()
+	m.cursor, m.offset, m.detailScroll = 0, 0, 0
+}
+
+// promptKey edits the prompt's text or answers its question
+func (m *tuiModel) promptKey(key string) {
+	p := m.prompt
+	if p.confirm {
+		m.prompt = nil
+		if key == "y" || key == "Y" {
+			p.submit("")
+		} else {
+			m.notify("Cancelled")
+		}
+		return
+	}
+
+	switch key {
+	case "enter":
+		m.prompt = nil
+		p.submit(strings.TrimSpace(p.text))
+	case "esc":
+		m.prompt = nil
+		m.notify("Cancelled")
+	case "backspace":
+		_, size := utf8.DecodeLastRuneInString(p.text)
+		p.text = p.text[:len(p.text)-size]
+	case "ctrl+u":
+		p.text = ""
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			p.text += key
+		}
+	}
+}
+
+// closeSelected closes the selected issue, asking first when it has open
+// children, as 'gi close' does
+func (m *tuiModel) closeSelected() {
+	item := m.selected()
+	if item == nil {
+		return
+	}
+	id := item.issue.ID
+	if item.dir == pkg.ClosedDir {
+		m.fail(fmt.Errorf("issue #%s is already closed", id))
+		return
+	}
+
+	check, err := pkg.CloseChecks(id)
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	if open := check.OpenChildren; len(open) > 0 {
+		m.prompt = &tuiPrompt{
+			label:   fmt.Sprintf("Issue #%s has %d open child issue(s): %s. Close it anyway? [y/N] ", id, len(open), formatIssueRefs(open)),
+			confirm: true,
+			submit:  func(string) { m.close(id, check) },
+		}
+		return
+	}
+	m.close(id, check)
+}
+
+// close closes issue id, warning about what check found left undone as 'gi
+// close' does
+func (m *tuiModel) close(id string, check *pkg.CloseCheck) {
+	// Open children were confirmed by closeSelected
+	if _, err := pkg.CloseIssue(id, "", pkg.CloseOptions{Force: true}); err != nil {
+		m.fail(err)
+		return
+	}
+	m.reload()
+	m.notify("✓ Closed issue #%s", id)
+
+	if len(check.Blockers) > 0 {
+		m.message += fmt.Sprintf(" (still blocked by open issue(s) %s)", formatIssueRefs(check.Blockers))
+	}
+	if len(check.Unchecked) > 0 {
+		m.message += fmt.Sprintf(" (%d unchecked success criteria)", len(check.Unchecked))
+	}
+}
+
+func (m *tuiModel) reopenSelected() {
+	item := m.selected()
+	if item == nil {
+		return
+	}
+	id := item.issue.ID
+	if item.dir == pkg.OpenDir {
+		m.fail(fmt.Errorf("issue #%s is already open", id))
+		return
+	}
+	if err := pkg.MoveIssue(id, pkg.ClosedDir, pkg.OpenDir); err != nil {
+		m.fail(fmt.Errorf("failed to move issue: %w", err))
+		return
+	}
+	m.reload()
+	m.notify("✓ Reopened issue #%s", id)
+}
+
+// assignSelected asks for the selected issue's assignee
+func (m *tuiModel) assignSelected() {
+	item := m.selected()
+	if item == nil {
+		return
+	}
+	id, version := item.issue.ID, item.issue.Version
+	m.prompt = &tuiPrompt{
+		label: fmt.Sprintf("Assign #%s to: ", id),
+		text:  item.issue.Assignee,
+		submit: func(assignee string) {
+			if _, _, err := pkg.UpdateIssue(id, pkg.IssueUpdate{Assignee: &assignee, IfVersion: version}); err != nil {
+				m.fail(err)
+				return
+			}
+			m.reload()
+			if assignee == "" {
+				m.notify("✓ Unassigned issue #%s", id)
+			} else {
+				m.notify("✓ Assigned issue #%s to %s", id, assignee)
+			}
+		},
+	}
+}
+
+// labelSelected asks for the selected issue's labels
+func (m *tuiModel) labelSelected() {
+	item := m.selected()
+	if item == nil {
+		return
+	}
+	id, version := item.issue.ID, item.issue.Version
+	m.prompt = &tuiPrompt{
+		label: fmt.Sprintf("Labels of #%s: ", id),
+		text:  strings.Join(item.issue.Labels, ", "),
+		submit: func(text string) {
+			labels := []string{}
+			for _, label := range strings.Split(text, ",") {
+				if label = strings.TrimSpace(label); label != "" {
+					labels = append(labels, label)
+				}
+			}
+			if _, _, err := pkg.UpdateIssue(id, pkg.IssueUpdate{Labels: &labels, IfVersion: version}); err != nil {
+				m.fail(err)
+				return
+			}
+			m.reload()
+			m.notify("✓ Set the labels of issue #%s to %s", id, strings.Join(labels, ", "))
+		},
+	}
+}
+
+// editSelected suspends the UI while the selected issue is open in $EDITOR
+func (m *tuiModel) editSelected(term *terminal) {
+	item := m.selected()
+	if item == nil {
+		return
+	}
+	id := item.issue.ID
+
+	fmt.Print("\x1b[?25h\x1b[?1049l")
+	_ = term.restore()
+	err := editIssue(id)
+	if rawErr := term.makeRaw(); rawErr != nil && err == nil {
+		err = rawErr
+	}
+	fmt.Print("\x1b[?1049h\x1b[?25l\x1b[2J")
+
+	m.reload()
+	if err != nil {
+		m.fail(err)
+		return
+	}
+	m.notify("✓ Updated issue #%s", id)
+}
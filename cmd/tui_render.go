@@ -0,0 +1,407 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/mattn/go-runewidth"
+)
+
+// styledLine is a line of terminal output drawn in one style
+type styledLine struct {
+	text  string
+	style *color.Color // nil for plain text
+}
+
+var (
+	tuiBold     = color.New(color.Bold)
+	tuiDim      = color.New(color.FgHiBlack)
+	tuiCode     = color.New(color.FgCyan)
+	tuiSelected = color.New(color.ReverseVideo)
+	tuiBar      = color.New(color.ReverseVideo, color.Bold)
+	tuiError    = color.New(color.FgRed)
+)
+
+// render draws a line padded or truncated to exactly width cells
+func (l styledLine) render(width int) string {
+	text := fitWidth(l.text, width)
+	if l.style == nil {
+		return text
+	}
+	return l.style.Sprint(text)
+}
+
+// displayWidth returns the number of terminal cells s takes up, counting
+// wide (e.g. CJK) characters as two
+func displayWidth(s string) int {
+	return runewidth.StringWidth(s)
+}
+
+// truncateWidth shortens s to at most width cells, ending it with "…" when cut
+func truncateWidth(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	return runewidth.Truncate(s, width, "…")
+}
+
+// fitWidth truncates or pads s with spaces to exactly width cells
+func fitWidth(s string, width int) string {
+	return runewidth.FillRight(truncateWidth(s, width), width)
+}
+
+// wrapText breaks s into lines of at most width cells at spaces, splitting
+// words that don't fit on a line of their own
+func wrapText(s string, width int) []string {
+	if width <= 0 {
+		return []string{""}
+	}
+
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for displayWidth(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			head := runewidth.Truncate(word, width, "")
+			lines = append(lines, head)
+			word = word[len(head):]
+		}
+		switch {
+		case line == "":
+			line = word
+		case displayWidth(line)+1+displayWidth(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	return append(lines, line)
+}
+
+var (
+	mdHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	mdFence     = regexp.MustCompile("^\\s*(```|~~~)")
+	mdRule      = regexp.MustCompile(`^\s*([-*_])(\s*[-*_]){2,}\s*$`)
+	mdQuote     = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	mdListItem  = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	mdTask      = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	mdTableRow  = regexp.MustCompile(`^\s*\|.*\|\s*$`)
+	mdImage     = regexp.MustCompile(`!\[([^\]]*)\]\(([^)\s]+)\)`)
+	mdLink      = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	mdEmphasis  = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__|~~([^~]+)~~`)
+	mdCodeSpans = regexp.MustCompile("`([^`]+)`")
+)
+
+// plainInline removes inline Markdown markup, keeping link targets
+func plainInline(s string) string {
+	s = mdImage.ReplaceAllString(s, "[image: $1]")
+	s = mdLink.ReplaceAllString(s, "$1 <$2>")
+	s = mdEmphasis.ReplaceAllString(s, "$1$2$3")
+	return mdCodeSpans.ReplaceAllString(s, "$1")
+}
+
+// markdownLines renders Markdown for the terminal: headings in bold, lists
+// with bullets and check boxes, code blocks and quotes set off, and
+// paragraphs wrapped to width
+func markdownLines(markdown string, width int) []styledLine {
+	var lines []styledLine
+	blank := true // suppresses repeated and leading blank lines
+	add := func(text string, style *color.Color) {
+		if text == "" {
+			if blank {
+				return
+			}
+			blank = true
+		} else {
+			blank = false
+		}
+		lines = append(lines, styledLine{text: text, style: style})
+	}
+
+	fence := ""
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		if m := mdFence.FindStringSubmatch(line); m != nil && (fence == "" || m[1] == fence) {
+			if fence == "" {
+				fence = m[1]
+			} else {
+				fence = ""
+			}
+			continue
+		}
+		if fence != "" {
+			// Code keeps its layout and is cut at the edge rather than wrapped
+			add("  "+strings.ReplaceAll(line, "\t", "    "), tuiCode)
+			continue
+		}
+
+		switch m := mdListItem.FindStringSubmatch(line); {
+		case strings.TrimSpace(line) == "":
+			add("", nil)
+		case mdHeading.MatchString(line):
+			if !blank {
+				add("", nil)
+			}
+			for _, text := range wrapText(plainInline(mdHeading.FindStringSubmatch(line)[2]), width) {
+				add(text, tuiBold)
+			}
+		case mdRule.MatchString(line):
+			add(strings.Repeat("─", width), tuiDim)
+		case mdQuote.MatchString(line):
+			for _, text := range wrapText(plainInline(mdQuote.FindStringSubmatch(line)[1]), width-2) {
+				add("│ "+text, tuiDim)
+			}
+		case mdTableRow.MatchString(line):
+			add(strings.TrimSpace(line), nil)
+		case m != nil:
+			indent := strings.Repeat(" ", len(strings.ReplaceAll(m[1], "\t", "  ")))
+			marker, text := "•", m[3]
+			if m[2][0] >= '0' && m[2][0] <= '9' {
+				marker = m[2]
+			}
+			if task := mdTask.FindStringSubmatch(text); task != nil {
+				marker, text = "☐", task[2]
+				if task[1] != " " {
+					marker = "☑"
+				}
+			}
+			hang := strings.Repeat(" ", displayWidth(indent+marker+" "))
+			for i, wrapped := range wrapText(plainInline(text), width-len(hang)) {
+				if i == 0 {
+					add(indent+marker+" "+wrapped, nil)
+				} else {
+					add(hang+wrapped, nil)
+				}
+			}
+		default:
+			for _, text := range wrapText(plainInline(strings.TrimSpace(line)), width) {
+				add(text, nil)
+			}
+		}
+	}
+
+	// Drop a trailing blank line
+	if len(lines) > 0 && lines[len(lines)-1].text == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// issueDetailLines renders an issue for the detail pane: title, attributes,
+// description and comments
+func issueDetailLines(item issueWithStatus, cfg *pkg.Config, width int) []styledLine {
+	issue := item.issue
+	var lines []styledLine
+
+	for _, text := range wrapText("#"+issue.ID+" "+issue.Title, width) {
+		lines = append(lines, styledLine{text: text, style: tuiBold})
+	}
+	lines = append(lines, styledLine{})
+
+	attribute := func(name, value string) {
+		if value != "" {
+			lines = append(lines, styledLine{text: fmt.Sprintf("%-10s %s", name, value)})
+		}
+	}
+	attribute("Status", item.status)
+	attribute("Assignee", issue.Assignee)
+	attribute("Labels", strings.Join(issue.Labels, ", "))
+	if issue.Parent != "" {
+		attribute("Parent", "#"+issue.Parent)
+	}
+	for _, name := range customFieldNames(issue, cfg) {
+		value, _ := issue.Field(name)
+		attribute(fieldLabel(name), pkg.FormatFieldValue(value))
+	}
+	if tasks := issue.TaskProgress(); tasks.Total > 0 {
+		attribute("Tasks", tasks.String()+" checked")
+	}
+	attribute("Created", issue.Created.Format("2006-01-02 15:04"))
+	attribute("Updated", issue.Updated.Format("2006-01-02 15:04"))
+
+	if body := markdownLines(issue.Body, width); len(body) > 0 {
+		lines = append(lines, styledLine{text: strings.Repeat("─", width), style: tuiDim})
+		lines = append(lines, body...)
+	}
+
+	if len(issue.Comments) > 0 {
+		lines = append(lines, styledLine{text: strings.Repeat("─", width), style: tuiDim})
+		lines = append(lines, styledLine{text: fmt.Sprintf("Comments (%d)", len(issue.Comments)), style: tuiBold})
+		for _, comment := range issue.Comments {
+			lines = append(lines, styledLine{})
+			lines = append(lines, styledLine{text: comment.Author + " · " + comment.Created.Format("2006-01-02 15:04"), style: tuiDim})
+			for _, line := range markdownLines(comment.Body, width-2) {
+				line.text = "  " + line.text
+				lines = append(lines, line)
+			}
+		}
+	}
+
+	return lines
+}
+
+// tuiSplitWidth is the narrowest terminal that shows the list and the
+// detail pane side by side
+const tuiSplitWidth = 100
+
+// render draws the whole screen as exactly height lines of width cells
+func (m *tuiModel) render() []string {
+	width, height := m.width, m.height
+	if width < 1 || height < 1 {
+		return nil
+	}
+	screen := make([]string, 0, height)
+
+	// Title bar
+	title := fmt.Sprintf(" Issues %d", len(m.visible))
+	if m.filter != "" || len(m.visible) != len(m.issues) {
+		title = fmt.Sprintf(" Issues %d of %d", len(m.visible), len(m.issues))
+	}
+	if m.filter != "" {
+		title += "  /" + m.filter
+	}
+	screen = append(screen, tuiBar.Sprint(fitWidth(title, width)))
+
+	rows := height - 3
+	if rows < 1 {
+		rows = 1
+	}
+	var panes []string
+	switch {
+	case width >= tuiSplitWidth:
+		listWidth := width * 2 / 5
+		detailWidth := width - listWidth - 1
+		list := m.listLines(listWidth, rows)
+		detail := m.detailPane(detailWidth, rows)
+		separator := tuiDim.Sprint("│")
+		for i := 0; i < rows; i++ {
+			panes = append(panes, list[i]+separator+detail[i])
+		}
+	case m.showDetail:
+		panes = m.detailPane(width, rows)
+	default:
+		panes = m.listLines(width, rows)
+	}
+	screen = append(screen, panes...)
+
+	// Prompt or message line, then the keys
+	switch {
+	case m.prompt != nil:
+		screen = append(screen, fitWidth(m.prompt.label+m.prompt.text+"▏", width))
+	case m.filtering:
+		screen = append(screen, fitWidth("/"+m.filter+"▏", width))
+	case m.messageErr:
+		screen = append(screen, tuiError.Sprint(fitWidth(m.message, width)))
+	default:
+		screen = append(screen, fitWidth(m.message, width))
+	}
+	screen = append(screen, tuiDim.Sprint(fitWidth(m.keyHelp(), width)))
+
+	if len(screen) > height {
+		screen = screen[len(screen)-height:]
+	}
+	return screen
+}
+
+// keyHelp describes the keys that work in the current mode
+func (m *tuiModel) keyHelp() string {
+	switch {
+	case m.prompt != nil && m.prompt.confirm:
+		return " y confirm  any other key cancels"
+	case m.prompt != nil:
+		return " enter save  esc cancel  ctrl+u clear"
+	case m.filtering:
+		return " type to filter  enter keep  esc clear"
+	case m.focusDetail:
+		return " ↑↓ scroll  pgup/pgdn page  tab/esc back to list  q quit"
+	}
+	return " ↑↓ move  / filter  enter details  c close  o reopen  e edit  a assign  l label  r reload  q quit"
+}
+
+// listLines draws the issue list, scrolled to keep the cursor in view
+func (m *tuiModel) listLines(width, rows int) []string {
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+rows {
+		m.offset = m.cursor - rows + 1
+	}
+
+	idWidth, statusWidth := 0, 0
+	for _, item := range m.visible {
+		idWidth = max(idWidth, displayWidth(item.issue.ID)+1)
+		statusWidth = max(statusWidth, displayWidth(item.status))
+	}
+
+	lines := make([]string, rows)
+	for i := range lines {
+		n := m.offset + i
+		if n >= len(m.visible) {
+			text := ""
+			if n == 0 {
+				text = " No issues"
+				if m.filter != "" {
+					text = " No issues match /" + m.filter
+				}
+			}
+			lines[i] = tuiDim.Sprint(fitWidth(text, width))
+			continue
+		}
+
+		item := m.visible[n]
+		id := fitWidth("#"+item.issue.ID, idWidth)
+		status := fitWidth(item.status, statusWidth)
+		titleWidth := width - idWidth - statusWidth - 3
+		title := item.issue.Title
+		if item.issue.Assignee != "" {
+			suffix := " @" + item.issue.Assignee
+			if displayWidth(title)+displayWidth(suffix) <= titleWidth {
+				title += suffix
+			}
+		}
+
+		switch {
+		case n == m.cursor:
+			lines[i] = tuiSelected.Sprint(fitWidth(" "+id+" "+status+" "+title, width))
+		case titleWidth < 1:
+			lines[i] = fitWidth(" "+id+" "+status, width)
+		default:
+			statusColor := color.New(color.FgGreen)
+			if item.dir == pkg.ClosedDir {
+				statusColor = color.New(color.FgRed)
+			}
+			lines[i] = " " + tuiDim.Sprint(id) + " " + statusColor.Sprint(status) + " " + fitWidth(title, titleWidth)
+		}
+	}
+	return lines
+}
+
+// detailPane draws the selected issue, scrolled by detailScroll
+func (m *tuiModel) detailPane(width, rows int) []string {
+	var content []styledLine
+	if item := m.selected(); item != nil {
+		content = issueDetailLines(*item, m.cfg, width-2)
+	}
+
+	// Don't scroll past the end
+	if m.detailScroll > len(content)-rows {
+		m.detailScroll = max(len(content)-rows, 0)
+	}
+
+	lines := make([]string, rows)
+	for i := range lines {
+		n := m.detailScroll + i
+		if n >= len(content) {
+			lines[i] = strings.Repeat(" ", width)
+			continue
+		}
+		lines[i] = " " + content[n].render(width-1)
+	}
+	return lines
+}
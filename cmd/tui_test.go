@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+// pressKeys sends key presses to the model
+func pressKeys(m *tuiModel, keys ...string) {
+	for _, key := range keys {
+		m.handleKey(key)
+	}
+}
+
+// typeText sends the characters of text to the model one at a time
+func typeText(m *tuiModel, text string) {
+	for _, r := range text {
+		m.handleKey(string(r))
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{"j", []string{"j"}},
+		{"\x1b[A\x1b[B", []string{"up", "down"}},
+		{"\x1bOA", []string{"up"}},
+		{"\x1b[5~\x1b[6~", []string{"pgup", "pgdown"}},
+		{"\x1b", []string{"esc"}},
+		{"\r\t\x7f\x03", []string{"enter", "tab", "backspace", "ctrl+c"}},
+		{"가a", []string{"가", "a"}},
+		{"\x1b[1;5A", nil}, // unknown sequences are dropped
+	}
+	for _, tt := range tests {
+		if got := parseKeys([]byte(tt.input)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseKeys(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestFuzzyScore(t *testing.T) {
+	if _, ok := fuzzyScore("rdto", "#001 Fix Redis timeout"); !ok {
+		t.Error("rdto should match Redis timeout")
+	}
+	if _, ok := fuzzyScore("redis auth", "#001 Fix Redis timeout"); ok {
+		t.Error("every term must match")
+	}
+	exact, _ := fuzzyScore("redis", "#001 Fix Redis timeout")
+	scattered, _ := fuzzyScore("redis", "#002 Refactor the editor design system")
+	if exact <= scattered {
+		t.Errorf("a contiguous match scored %d, not more than a scattered one (%d)", exact, scattered)
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	if got := fitWidth("레디스 연결", 8); got != "레디스 …" || displayWidth(got) != 8 {
+		t.Errorf("fitWidth = %q (%d cells)", got, displayWidth(got))
+	}
+	if got := fitWidth("ab", 4); got != "ab  " {
+		t.Errorf("fitWidth pads: got %q", got)
+	}
+	if got := wrapText("the quick brown fox", 9); !reflect.DeepEqual(got, []string{"the quick", "brown fox"}) {
+		t.Errorf("wrapText = %q", got)
+	}
+	if got := wrapText("abcdefghij", 4); !reflect.DeepEqual(got, []string{"abcd", "efgh", "ij"}) {
+		t.Errorf("wrapText splits long words: got %q", got)
+	}
+}
+
+func TestMarkdownLines(t *testing.T) {
+	body := "## Description\n\nSee **the logs** and [the runbook](https://example.com).\n\n- [ ] Retry\n- [x] Alert\n1. First\n\n```go\nx := 1\n```\n> quoted\n"
+	var got []string
+	for _, line := range markdownLines(body, 60) {
+		got = append(got, line.text)
+	}
+	want := []string{
+		"Description",
+		"",
+		"See the logs and the runbook <https://example.com>.",
+		"",
+		"☐ Retry",
+		"☑ Alert",
+		"1. First",
+		"",
+		"  x := 1",
+		"│ quoted",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("markdownLines =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestTUIModel(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	for _, title := range []string{"Fix Redis connection timeout", "Add user authentication", "Update documentation"} {
+		if err := runCreate(nil, []string{title}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createParent = "002"
+	if err := runCreate(nil, []string{"Add login page"}); err != nil {
+		t.Fatal(err)
+	}
+	createParent = ""
+
+	m, err := newTUIModel(false, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.width, m.height = 120, 20
+
+	// Rendering fills the screen exactly
+	screen := m.render()
+	if len(screen) != 20 {
+		t.Fatalf("render returned %d lines, want 20", len(screen))
+	}
+	for i, line := range screen {
+		if displayWidth(line) != 120 {
+			t.Errorf("line %d is %d cells wide: %q", i, displayWidth(line), line)
+		}
+	}
+	if !strings.Contains(strings.Join(screen, "\n"), "#001 Fix Redis connection timeout") {
+		t.Error("the detail pane doesn't show the selected issue")
+	}
+
+	// Filter
+	pressKeys(m, "/")
+	typeText(m, "redis")
+	if len(m.visible) != 1 || m.selected().issue.ID != "001" {
+		t.Fatalf("filtering for redis left %d issue(s)", len(m.visible))
+	}
+	pressKeys(m, "esc")
+	if m.filtering || len(m.visible) != 4 {
+		t.Errorf("esc should clear the filter, got %d issue(s)", len(m.visible))
+	}
+
+	// Assign and label through prompts
+	pressKeys(m, "a")
+	typeText(m, "mina")
+	pressKeys(m, "enter")
+	pressKeys(m, "l", "ctrl+u")
+	typeText(m, "bug, backend")
+	pressKeys(m, "enter")
+	issue, _, _ := pkg.LoadIssue("001")
+	if issue.Assignee != "mina" || strings.Join(issue.Labels, ",") != "bug,backend" {
+		t.Errorf("after a and l: assignee %q, labels %v (%s)", issue.Assignee, issue.Labels, m.message)
+	}
+	pressKeys(m, "a", "ctrl+u", "enter")
+	if issue, _, _ := pkg.LoadIssue("001"); issue.Assignee != "" {
+		t.Errorf("an empty assignee should unassign, got %q", issue.Assignee)
+	}
+
+	// A stale version is refused rather than overwriting the other change
+	m.selected().issue.Version = "stale"
+	pressKeys(m, "a")
+	typeText(m, "joe")
+	pressKeys(m, "enter")
+	if !m.messageErr || !strings.Contains(m.message, "press r to reload") {
+		t.Errorf("assigning a modified issue: %q", m.message)
+	}
+
+	// Close: a parent with open children needs confirming
+	pressKeys(m, "down")
+	if m.selected().issue.ID != "002" {
+		t.Fatalf("cursor on #%s, want #002", m.selected().issue.ID)
+	}
+	pressKeys(m, "c", "n")
+	if _, dir, _ := pkg.LoadIssue("002"); dir != pkg.OpenDir {
+		t.Error("declining the confirmation closed the issue")
+	}
+	pressKeys(m, "c", "y")
+	if _, dir, _ := pkg.LoadIssue("002"); dir != pkg.ClosedDir {
+		t.Errorf("confirming didn't close the issue: %s", m.message)
+	}
+	if len(m.visible) != 3 {
+		t.Errorf("a closed issue should leave the list of open issues, %d listed", len(m.visible))
+	}
+
+	// Reopen, listing closed issues too
+	m, err = newTUIModel(true, "status:closed")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.visible) != 1 || m.selected().issue.ID != "002" {
+		t.Fatalf("status:closed listed %d issue(s)", len(m.visible))
+	}
+	pressKeys(m, "o")
+	if _, dir, _ := pkg.LoadIssue("002"); dir != pkg.OpenDir || !strings.Contains(m.message, "Reopened") {
+		t.Errorf("o didn't reopen the issue: %s", m.message)
+	}
+
+	if m.handleKey("q") != tuiQuit {
+		t.Error("q should quit")
+	}
+}
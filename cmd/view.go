@@ -13,9 +13,11 @@ import (
 var viewCmd = &cobra.Command{
 	Use:   "view <issue-id>",
 	Short: "Open an issue in the default program",
-	Long:  `Open an issue's markdown file in the system's default program (e.g., Typora, VS Code, Obsidian).`,
-	Args:  cobra.ExactArgs(1),
-	RunE:  runView,
+	Long: `Open an issue's markdown file in the system's default program (e.g., Typora, VS Code, Obsidian).
+
+'gi view save' and 'gi view delete' manage saved views of 'gi list'; see 'gi views'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runView,
 }
 
 func init() {
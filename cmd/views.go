@@ -0,0 +1,210 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	viewSaveQuery       string
+	viewSaveSort        string
+	viewSaveColumns     string
+	viewSaveAll         bool
+	viewSaveDescription string
+	viewSaveForce       bool
+	viewPersonal        bool
+	viewCommit          bool
+)
+
+var viewSaveCmd = &cobra.Command{
+	Use:   "save <name>",
+	Short: "Save list options as a named view",
+	Long: `Save a query, sort order and columns under a name, to be run with
+'gi list --view <name>'.
+
+Views are saved in .issues/config.yaml, where they're shared with everyone
+working on the repository. With --personal they're saved in your user config
+file instead; a personal view hides a shared view with the same name.
+
+Examples:
+  gi view save my-bugs -q 'label:bug assignee:jonghun' --sort priority,updated:desc
+  gi view save stale -q 'updated:<30d' --columns id,title,assignee,updated --personal`,
+	Args: cobra.ExactArgs(1),
+	RunE: runViewSave,
+}
+
+var viewDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete a saved view",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runViewDelete,
+}
+
+var viewsCmd = &cobra.Command{
+	Use:   "views",
+	Short: "List saved views",
+	Args:  cobra.NoArgs,
+	RunE:  runViews,
+}
+
+func init() {
+	viewCmd.AddCommand(viewSaveCmd, viewDeleteCmd)
+	rootCmd.AddCommand(viewsCmd)
+
+	viewSaveCmd.Flags().StringVarP(&viewSaveQuery, "query", "q", "", "Query expression, as for 'gi list --query'")
+	viewSaveCmd.Flags().StringVar(&viewSaveSort, "sort", "", "Sort keys, as for 'gi list --sort'")
+	viewSaveCmd.Flags().StringVar(&viewSaveColumns, "columns", "", "Table columns, as for 'gi list --columns'")
+	viewSaveCmd.Flags().BoolVarP(&viewSaveAll, "all", "a", false, "Include closed issues")
+	viewSaveCmd.Flags().StringVarP(&viewSaveDescription, "description", "d", "", "Describe the view")
+	viewSaveCmd.Flags().BoolVarP(&viewSaveForce, "force", "f", false, "Replace an existing view with the same name")
+	viewSaveCmd.Flags().BoolVar(&viewPersonal, "personal", false, "Save in your user config instead of .issues/config.yaml")
+	viewSaveCmd.Flags().BoolVarP(&viewCommit, "commit", "c", false, "Auto-commit the change to git (shared views only)")
+	viewDeleteCmd.Flags().BoolVar(&viewPersonal, "personal", false, "Delete from your user config instead of .issues/config.yaml")
+	viewDeleteCmd.Flags().BoolVarP(&viewCommit, "commit", "c", false, "Auto-commit the change to git (shared views only)")
+}
+
+func runViewSave(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+	if viewPersonal && viewCommit {
+		return fmt.Errorf("--commit only applies to shared views")
+	}
+
+	cfg, err := pkg.LoadConfig()
+	if err != nil {
+		return err
+	}
+
+	// Check the options the way 'gi list' will
+	if viewSaveQuery != "" {
+		if _, err := pkg.ParseQuery(viewSaveQuery, cfg); err != nil {
+			return err
+		}
+	}
+	if _, err := parseSortKeys(viewSaveSort, cfg); err != nil {
+		return err
+	}
+	if _, err := parseColumns(viewSaveColumns, cfg); err != nil {
+		return err
+	}
+
+	view := pkg.View{
+		Name:        args[0],
+		Description: viewSaveDescription,
+		Query:       viewSaveQuery,
+		Sort:        viewSaveSort,
+		Columns:     viewSaveColumns,
+		All:         viewSaveAll,
+	}
+
+	scope := pkg.ViewShared
+	if viewPersonal {
+		scope = pkg.ViewPersonal
+		err = pkg.SaveUserView(view, viewSaveForce)
+	} else {
+		err = pkg.SaveView(view, viewSaveForce)
+	}
+	if errors.Is(err, pkg.ErrViewExists) {
+		return fmt.Errorf("%w (use --force to replace it)", err)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Saved %s view %s\n", scope, view.Name)
+	fmt.Printf("Run it with: gi list --view %s\n", view.Name)
+
+	if viewCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Save view %s", view.Name)); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
+
+func runViewDelete(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+	if viewPersonal && viewCommit {
+		return fmt.Errorf("--commit only applies to shared views")
+	}
+
+	var err error
+	scope := pkg.ViewShared
+	if viewPersonal {
+		scope = pkg.ViewPersonal
+		err = pkg.DeleteUserView(args[0])
+	} else {
+		err = pkg.DeleteView(args[0])
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("✓ Deleted %s view %s\n", scope, args[0])
+
+	if viewCommit {
+		if err := gitCommitChanges(fmt.Sprintf("Delete view %s", args[0])); err != nil {
+			return fmt.Errorf("failed to commit changes: %w", err)
+		}
+		fmt.Println("✓ Changes committed to git")
+	}
+
+	return nil
+}
+
+func runViews(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	views, err := pkg.Views()
+	if err != nil {
+		return err
+	}
+	if len(views) == 0 {
+		fmt.Println("No saved views. Save one with 'gi view save <name> -q <query>'.")
+		return nil
+	}
+
+	gray := color.New(color.FgHiBlack).SprintFunc()
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Name", "Scope", "Query", "Sort", "Columns", "Description"})
+	table.SetBorder(false)
+	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
+	table.SetAlignment(tablewriter.ALIGN_LEFT)
+	table.SetCenterSeparator("")
+	table.SetColumnSeparator("")
+	table.SetRowSeparator("")
+	table.SetHeaderLine(false)
+	table.SetTablePadding("\t")
+	table.SetNoWhiteSpace(true)
+	table.SetAutoWrapText(false)
+
+	for _, view := range views {
+		query := orDash(view.Query)
+		if view.All {
+			query += gray(" (all)")
+		}
+		table.Append([]string{
+			view.Name,
+			view.Scope,
+			query,
+			orDash(view.Sort),
+			orDash(view.Columns),
+			orDash(view.Description),
+		})
+	}
+	table.Render()
+
+	return nil
+}
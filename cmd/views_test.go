@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestRunViewSaveAndList(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	createLabels = []string{"bug"}
+	if err := runCreate(nil, []string{"Crash on save"}); err != nil {
+		t.Fatal(err)
+	}
+	createLabels = []string{}
+
+	viewSaveQuery = "label:bug AND"
+	if err := runViewSave(nil, []string{"my-bugs"}); err == nil {
+		t.Error("runViewSave() should reject an invalid query")
+	}
+	viewSaveQuery = "label:bug"
+	viewSaveSort = "updated:desc"
+	viewSaveColumns = "id,title,updated"
+	if err := runViewSave(nil, []string{"my-bugs"}); err != nil {
+		t.Fatalf("runViewSave() failed: %v", err)
+	}
+	if err := runViewSave(nil, []string{"my-bugs"}); err == nil {
+		t.Error("runViewSave() should refuse to replace a view without --force")
+	}
+
+	viewPersonal = true
+	viewSaveQuery = "status:closed"
+	if err := runViewSave(nil, []string{"done"}); err != nil {
+		t.Fatalf("runViewSave(--personal) failed: %v", err)
+	}
+	viewPersonal = false
+
+	views, err := pkg.Views()
+	if err != nil || len(views) != 2 {
+		t.Fatalf("Views() = %+v, %v", views, err)
+	}
+	if err := runViews(nil, nil); err != nil {
+		t.Errorf("runViews() failed: %v", err)
+	}
+
+	listView = "my-bugs"
+	if err := runList(nil, nil); err != nil {
+		t.Errorf("runList(--view) failed: %v", err)
+	}
+	listView = "missing"
+	if err := runList(nil, nil); err == nil {
+		t.Error("runList() with an unknown view should fail")
+	}
+	listView = ""
+
+	if err := runViewDelete(nil, []string{"my-bugs"}); err != nil {
+		t.Fatalf("runViewDelete() failed: %v", err)
+	}
+	if _, err := pkg.FindView("my-bugs"); err == nil {
+		t.Error("view should be deleted")
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os/exec"
+	"runtime"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+)
+
+//go:embed web
+var webFiles embed.FS
+
+var (
+	webAddr string
+	webOpen bool
+)
+
+var webCmd = &cobra.Command{
+	Use:   "web",
+	Short: "Browse, triage and edit issues in a web browser",
+	Long: `Serve a web UI for the issues in .issues/, for teammates who don't use the
+command line. It lists and filters issues, renders their Markdown, shows a
+board with a column per workflow state (drag a card to change its state), and
+edits issues in place.
+
+Edits are saved the way the gi commands save them, so they end up as ordinary
+changes to the issue files for you to commit. If an issue changes on disk
+while it's open in the editor, saving fails instead of overwriting the change.
+
+The REST API of 'gi serve' is available under /api. The UI has no
+authentication; it listens on localhost unless --addr says otherwise.`,
+	Example: `  gi web
+  gi web --open
+  gi web --addr :8080`,
+	Args: cobra.NoArgs,
+	RunE: runWeb,
+}
+
+func init() {
+	rootCmd.AddCommand(webCmd)
+	webCmd.Flags().StringVar(&webAddr, "addr", "localhost:8080", "Address to listen on")
+	webCmd.Flags().BoolVar(&webOpen, "open", false, "Open the UI in the default browser")
+}
+
+func runWeb(cmd *cobra.Command, args []string) error {
+	if !pkg.RepoExists() {
+		return fmt.Errorf(".issues directory not found. Run 'gi init' first")
+	}
+
+	return listenAndServe(webAddr, newWebHandler(pkg.DefaultRepository()), func(url string) {
+		fmt.Printf("✓ Serving the issue UI on %s (Ctrl-C to stop)\n", url)
+		if webOpen {
+			if err := openBrowser(url); err != nil {
+				_, _ = color.New(color.FgYellow).Printf("! %v\n", err)
+			}
+		}
+	})
+}
+
+// newWebHandler serves the embedded UI, with the REST API under /api
+func newWebHandler(repo *pkg.Repository) http.Handler {
+	static, err := fs.Sub(webFiles, "web")
+	if err != nil {
+		panic(err) // The files are embedded at build time
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/api/", http.StripPrefix("/api", newAPIServer(repo)))
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	return mux
+}
+
+// openBrowser opens a URL in the default browser
+func openBrowser(url string) error {
+	var opener string
+	switch runtime.GOOS {
+	case "darwin":
+		opener = "open"
+	case "linux":
+		opener = "xdg-open"
+	default:
+		return fmt.Errorf("can't open a browser on %s; visit %s", runtime.GOOS, url)
+	}
+	if err := exec.Command(opener, url).Start(); err != nil {
+		return fmt.Errorf("failed to open browser: %w", err)
+	}
+	return nil
+}
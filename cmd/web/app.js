@@ -0,0 +1,652 @@
+// The gi web UI: a single page on top of the REST API under /api.
+(function () {
+  "use strict";
+
+  var app = document.getElementById("app");
+  var notice = document.getElementById("notice");
+  var config = null;
+  var flash = ""; // Shown after the next navigation
+  var cardsPerColumn = 50;
+  var pageSize = 50;
+
+  // h builds an element. Text children are added as text, never as HTML.
+  function h(tag, attrs, children) {
+    var el = document.createElement(tag);
+    Object.keys(attrs || {}).forEach(function (key) {
+      var value = attrs[key];
+      if (value === null || value === undefined || value === false) {
+        return;
+      }
+      if (key.indexOf("on") === 0) {
+        el.addEventListener(key.slice(2), value);
+      } else if (key === "html") {
+        el.innerHTML = value; // Only used with markdown.render output, which is escaped
+      } else if (value === true) {
+        el.setAttribute(key, "");
+      } else {
+        el.setAttribute(key, value);
+      }
+    });
+    append(el, children);
+    return el;
+  }
+
+  // append adds children, which may be nested in arrays, to an element
+  function append(el, children) {
+    [].concat(children === undefined ? [] : children).forEach(function (child) {
+      if (child === null || child === undefined || child === false) {
+        return;
+      }
+      if (Array.isArray(child)) {
+        append(el, child);
+        return;
+      }
+      el.appendChild(typeof child === "string" || typeof child === "number" ? document.createTextNode(String(child)) : child);
+    });
+  }
+
+  function show() {
+    app.innerHTML = "";
+    [].slice.call(arguments).forEach(function (el) {
+      app.appendChild(el);
+    });
+  }
+
+  function say(message, isError) {
+    if (!message) {
+      notice.hidden = true;
+      return;
+    }
+    notice.textContent = message;
+    notice.className = isError ? "error" : "";
+    notice.hidden = false;
+  }
+
+  // api calls the REST API. It resolves to {data, etag} and rejects with an
+  // Error carrying the HTTP status.
+  function api(method, path, options) {
+    options = options || {};
+    var headers = {};
+    var body;
+    if (options.json !== undefined) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(options.json);
+    } else if (options.text !== undefined) {
+      headers["Content-Type"] = "text/markdown; charset=utf-8";
+      body = options.text;
+    }
+    if (options.etag) {
+      headers["If-Match"] = options.etag;
+    }
+
+    return fetch("api" + path, { method: method, headers: headers, body: body }).then(function (resp) {
+      var type = resp.headers.get("Content-Type") || "";
+      var read = type.indexOf("application/json") === 0 ? resp.json() : resp.text();
+      return read.then(function (data) {
+        if (!resp.ok) {
+          var err = new Error((data && data.error) || resp.statusText);
+          err.status = resp.status;
+          throw err;
+        }
+        return { data: data, etag: resp.headers.get("ETag") };
+      });
+    });
+  }
+
+  function failed(err) {
+    if (err.status === 412) {
+      say("This issue was changed by someone else since you opened it. Reload it to see their changes, then make yours again.", true);
+    } else {
+      say(err.message, true);
+    }
+  }
+
+  function loadConfig() {
+    if (config) {
+      return Promise.resolve(config);
+    }
+    return api("GET", "/config").then(function (resp) {
+      config = resp.data;
+      return config;
+    });
+  }
+
+  function stateNames() {
+    return config.states.map(function (s) {
+      return s.name;
+    });
+  }
+
+  function isClosedState(name) {
+    return config.states.some(function (s) {
+      return s.name === name && s.closed;
+    });
+  }
+
+  function statusBadge(status) {
+    return h("span", { class: "status" + (isClosedState(status) ? " closed" : "") }, status);
+  }
+
+  function labels(list) {
+    return (list || []).map(function (label) {
+      return h("span", { class: "label" }, label);
+    });
+  }
+
+  function today() {
+    var d = new Date();
+    var pad = function (n) {
+      return (n < 10 ? "0" : "") + n;
+    };
+    return d.getFullYear() + "-" + pad(d.getMonth() + 1) + "-" + pad(d.getDate());
+  }
+
+  function formatTime(value) {
+    if (!value) {
+      return "";
+    }
+    var d = new Date(value);
+    return isNaN(d) ? value : d.toLocaleString();
+  }
+
+  // quote quotes a query value containing spaces
+  function quote(value) {
+    return /[\s"()]/.test(value) ? '"' + value.replace(/"/g, '\\"') + '"' : value;
+  }
+
+  function hashParams() {
+    var hash = location.hash.replace(/^#/, "");
+    var i = hash.indexOf("?");
+    return { path: i < 0 ? hash : hash.slice(0, i), params: new URLSearchParams(i < 0 ? "" : hash.slice(i + 1)) };
+  }
+
+  function markNav(name) {
+    [].forEach.call(document.querySelectorAll("header nav a"), function (a) {
+      a.classList.toggle("active", a.getAttribute("data-nav") === name);
+    });
+  }
+
+  // List
+
+  function listView(params) {
+    markNav("list");
+    var filters = {
+      q: params.get("q") || "",
+      status: params.get("status") || "open",
+      assignee: params.get("assignee") || "",
+      label: params.get("label") || "",
+      sort: params.get("sort") || "id",
+      page: Number(params.get("page")) || 1,
+    };
+
+    Promise.all([loadConfig(), api("GET", "/issues?status=all&limit=0")]).then(function (results) {
+      var everything = results[1].data.issues;
+      var assignees = {};
+      var allLabels = {};
+      everything.forEach(function (issue) {
+        if (issue.assignee) {
+          assignees[issue.assignee] = true;
+        }
+        (issue.labels || []).forEach(function (label) {
+          allLabels[label] = true;
+        });
+      });
+
+      var query = filters.q;
+      if (filters.assignee) {
+        query += " assignee:" + quote(filters.assignee);
+      }
+      if (filters.label) {
+        query += " label:" + quote(filters.label);
+      }
+      var search = new URLSearchParams({
+        q: query.trim(),
+        status: filters.status,
+        sort: filters.sort,
+        limit: pageSize,
+        offset: (filters.page - 1) * pageSize,
+      });
+      return api("GET", "/issues?" + search.toString()).then(function (resp) {
+        renderList(filters, resp.data, Object.keys(assignees).sort(), Object.keys(allLabels).sort());
+      });
+    }).catch(failed);
+  }
+
+  function select(name, value, options) {
+    return h(
+      "select",
+      { name: name },
+      options.map(function (option) {
+        var v = typeof option === "string" ? option : option[0];
+        var label = typeof option === "string" ? option : option[1];
+        return h("option", { value: v, selected: v === value }, label);
+      })
+    );
+  }
+
+  function renderList(filters, list, assignees, allLabels) {
+    var form = h("form", { class: "filters" }, [
+      h("input", { name: "q", type: "search", value: filters.q, placeholder: "Search or filter, e.g. timeout priority:high due:<2w" }),
+      select("status", filters.status, ["open", "closed", "all"].concat(stateNames().filter(function (s) {
+        return s !== "open" && s !== "closed";
+      }))),
+      select("assignee", filters.assignee, [["", "Any assignee"]].concat(assignees)),
+      select("label", filters.label, [["", "Any label"]].concat(allLabels)),
+      select("sort", filters.sort, [
+        ["id", "Sort by ID"],
+        ["updated:desc", "Recently updated"],
+        ["priority", "Priority"],
+        ["due", "Due date"],
+        ["title", "Title"],
+      ]),
+      h("button", { type: "submit" }, "Filter"),
+    ]);
+    var apply = function (e) {
+      if (e) {
+        e.preventDefault();
+      }
+      var params = new URLSearchParams();
+      ["q", "status", "assignee", "label", "sort"].forEach(function (name) {
+        var value = form.elements[name].value;
+        if (value) {
+          params.set(name, value);
+        }
+      });
+      location.hash = "#/?" + params.toString();
+    };
+    form.addEventListener("submit", apply);
+    [].forEach.call(form.querySelectorAll("select"), function (el) {
+      el.addEventListener("change", apply);
+    });
+
+    var rows = list.issues.map(function (issue) {
+      var overdue = issue.due && !issue.closed && issue.due < today();
+      return h("tr", {}, [
+        h("td", {}, h("a", { href: "#/issues/" + issue.id }, "#" + issue.id)),
+        h("td", {}, [h("a", { href: "#/issues/" + issue.id }, issue.title), " ", labels(issue.labels)]),
+        h("td", {}, statusBadge(issue.status)),
+        h("td", {}, issue.assignee || ""),
+        h("td", {}, issue.priority || ""),
+        h("td", { class: overdue ? "overdue" : null }, issue.due || ""),
+        h("td", { class: "muted" }, formatTime(issue.updated)),
+      ]);
+    });
+
+    var table = list.issues.length
+      ? h("table", { class: "issues" }, [
+          h("thead", {}, h("tr", {}, ["ID", "Title", "Status", "Assignee", "Priority", "Due", "Updated"].map(function (c) {
+            return h("th", {}, c);
+          }))),
+          h("tbody", {}, rows),
+        ])
+      : h("p", { class: "muted" }, "No issues match.");
+
+    var pages = Math.max(1, Math.ceil(list.total / pageSize));
+    var goTo = function (page) {
+      return function () {
+        var current = hashParams().params;
+        current.set("page", page);
+        location.hash = "#/?" + current.toString();
+      };
+    };
+    var pager = h("div", { class: "pager" }, [
+      h("span", { class: "muted" }, list.total + (list.total === 1 ? " issue" : " issues")),
+      pages > 1 ? h("button", { disabled: filters.page <= 1, onclick: goTo(filters.page - 1) }, "Previous") : null,
+      pages > 1 ? h("span", {}, "Page " + filters.page + " of " + pages) : null,
+      pages > 1 ? h("button", { disabled: filters.page >= pages, onclick: goTo(filters.page + 1) }, "Next") : null,
+    ]);
+
+    show(form, table, pager);
+  }
+
+  // Detail
+
+  function detailView(id) {
+    markNav("");
+    Promise.all([loadConfig(), api("GET", "/issues/" + encodeURIComponent(id))]).then(function (results) {
+      renderDetail(results[1].data, results[1].etag);
+    }).catch(failed);
+  }
+
+  function renderDetail(issue, etag) {
+    var state = config.states.filter(function (s) {
+      return s.name === issue.status;
+    })[0];
+    var statuses = [issue.status].concat(state ? state.transitions : []);
+    var priorities = [["", "None"]].concat(config.priorities);
+    var due = (issue.fields && issue.fields.due) || "";
+
+    var triage = h("form", {}, [
+      h("label", {}, "Status"),
+      select("status", issue.status, statuses),
+      h("label", {}, "Assignee"),
+      h("input", { name: "assignee", value: issue.assignee }),
+      h("label", {}, "Labels (comma-separated)"),
+      h("input", { name: "labels", value: issue.labels.join(", ") }),
+      h("label", {}, "Priority"),
+      select("priority", (issue.fields && issue.fields.priority) || "", priorities),
+      h("label", {}, "Due"),
+      h("input", { name: "due", type: "date", value: due }),
+      h("div", { class: "actions" }, h("button", { type: "submit", class: "primary" }, "Save")),
+    ]);
+    triage.addEventListener("submit", function (e) {
+      e.preventDefault();
+      var change = {
+        assignee: triage.elements.assignee.value.trim(),
+        labels: triage.elements.labels.value.split(",").map(function (l) {
+          return l.trim();
+        }).filter(Boolean),
+        priority: triage.elements.priority.value,
+        due: triage.elements.due.value,
+      };
+      if (triage.elements.status.value !== issue.status) {
+        change.status = triage.elements.status.value;
+      }
+      var save = function () {
+        api("PATCH", "/issues/" + issue.id, { json: change, etag: etag }).then(function (resp) {
+          say("Saved.");
+          renderDetail(resp.data, resp.etag);
+        }).catch(function (err) {
+          if (confirmForce(err)) {
+            change.force = true;
+            save();
+            return;
+          }
+          failed(err);
+        });
+      };
+      save();
+    });
+
+    var closeButton = issue.closed
+      ? h("button", { onclick: function () {
+          api("PATCH", "/issues/" + issue.id, { json: { status: "open" }, etag: etag }).then(function (resp) {
+            say("Reopened #" + issue.id + ".");
+            renderDetail(resp.data, resp.etag);
+          }).catch(failed);
+        } }, "Reopen")
+      : h("button", { onclick: function () {
+          closeIssue(issue, etag, false);
+        } }, "Close issue");
+
+    var comments = issue.comments.map(function (comment) {
+      return h("div", { class: "comment" }, [
+        h("div", { class: "meta" }, [h("strong", {}, comment.author), " commented " + formatTime(comment.created)]),
+        h("div", { class: "markdown-body", html: markdown.render(comment.body) }),
+      ]);
+    });
+
+    var commentForm = h("form", { class: "comment" }, [
+      h("div", { class: "meta" }, [
+        "Comment as ",
+        h("input", { name: "author", value: localStorage.getItem("gi-author") || "", placeholder: "your name" }),
+      ]),
+      h("div", { style: "padding: 12px" }, [
+        h("textarea", { name: "text", rows: 4, placeholder: "Markdown" }),
+        h("div", { class: "actions" }, h("button", { type: "submit" }, "Comment")),
+      ]),
+    ]);
+    commentForm.addEventListener("submit", function (e) {
+      e.preventDefault();
+      var author = commentForm.elements.author.value.trim();
+      if (author) {
+        localStorage.setItem("gi-author", author);
+      }
+      api("POST", "/issues/" + issue.id + "/comments", { json: { text: commentForm.elements.text.value, author: author } }).then(function () {
+        say("Comment added.");
+        detailView(issue.id);
+      }).catch(failed);
+    });
+
+    var meta = ["#" + issue.id, " · created " + formatTime(issue.created), " · updated " + formatTime(issue.updated)];
+    if (issue.parent) {
+      meta.push(" · part of ", h("a", { href: "#/issues/" + issue.parent }, "#" + issue.parent));
+    }
+    Object.keys(issue.links || {}).forEach(function (type) {
+      meta.push(" · " + type + " ");
+      issue.links[type].forEach(function (linked, i) {
+        meta.push((i ? ", " : ""), h("a", { href: "#/issues/" + linked }, "#" + linked));
+      });
+    });
+
+    show(h("div", { class: "detail" }, [
+      h("section", {}, [
+        h("h1", {}, issue.title),
+        h("div", { class: "muted" }, [statusBadge(issue.status), " "].concat(meta)),
+        h("div", {}, labels(issue.labels)),
+        h("div", { class: "markdown-body", html: markdown.render(issue.body) || '<p class="muted">No description.</p>' }),
+        h("div", { class: "actions" }, [
+          h("a", { class: "button", href: "#/issues/" + issue.id + "/edit" }, "Edit"),
+          closeButton,
+        ]),
+        h("h2", {}, "Comments"),
+      ].concat(comments, [commentForm])),
+      h("aside", {}, triage),
+    ]));
+  }
+
+  function closeIssue(issue, etag, force) {
+    api("POST", "/issues/" + issue.id + "/close", { json: { force: force }, etag: etag }).then(function (resp) {
+      var warnings = resp.data.warnings || [];
+      say(warnings.length ? "Closed #" + issue.id + ". " + warnings.join(". ") + "." : "Closed #" + issue.id + ".");
+      renderDetail(resp.data.issue, resp.etag);
+    }).catch(function (err) {
+      if (confirmForce(err)) {
+        closeIssue(issue, etag, true);
+        return;
+      }
+      failed(err);
+    });
+  }
+
+  // confirmForce asks whether to close an issue the server refused to close
+  // for its open children
+  function confirmForce(err) {
+    return err.status === 422 && /open child issue/.test(err.message) && confirm(err.message.replace(/ \(set force.*\)$/, "") + ". Close it anyway?");
+  }
+
+  // Editor
+
+  // body returns the part of an issue file shown in the preview: everything
+  // after the frontmatter and the title
+  function body(text) {
+    return text.replace(/^---\n[\s\S]*?\n---\n/, "").replace(/^\s*# .*\n?/, "");
+  }
+
+  function editView(id) {
+    markNav("");
+    api("GET", "/issues/" + encodeURIComponent(id) + "/markdown").then(function (resp) {
+      renderEditor(id, resp.data, resp.etag);
+    }).catch(failed);
+  }
+
+  function renderEditor(id, text, etag) {
+    var preview = h("div", { class: "markdown-body" });
+    var textarea = h("textarea", { spellcheck: "true" });
+    textarea.value = text;
+    var update = function () {
+      preview.innerHTML = markdown.render(body(textarea.value));
+    };
+    textarea.addEventListener("input", update);
+    update();
+
+    var save = h("button", { class: "primary", onclick: function () {
+      save.disabled = true;
+      api("PUT", "/issues/" + encodeURIComponent(id) + "/markdown", { text: textarea.value, etag: etag }).then(function (resp) {
+        flash = "Saved #" + resp.data.id + ".";
+        location.hash = "#/issues/" + resp.data.id;
+      }).catch(function (err) {
+        save.disabled = false;
+        failed(err);
+        if (err.status === 412) {
+          app.querySelector(".actions").appendChild(h("button", { onclick: function () {
+            say("");
+            editView(id);
+          } }, "Reload (discards your edits)"));
+        }
+      });
+    } }, "Save");
+
+    show(
+      h("h1", {}, "Edit #" + id),
+      h("p", { class: "muted" }, "The issue file as stored in .issues/: frontmatter between the --- lines, then the # title and the description."),
+      h("div", { class: "editor" }, [textarea, preview]),
+      h("div", { class: "actions" }, [save, h("a", { class: "button", href: "#/issues/" + id }, "Cancel")])
+    );
+  }
+
+  // New issue
+
+  function newView() {
+    markNav("new");
+    loadConfig().then(function () {
+      var form = h("form", { class: "form" }, [
+        h("h1", {}, "New issue"),
+        h("label", {}, "Title"),
+        h("input", { name: "title", required: true, autofocus: true }),
+        h("label", {}, "Description"),
+        h("textarea", { name: "body", rows: 12, placeholder: "Markdown (leave empty for the repository's issue template)" }),
+        h("label", {}, "Labels (comma-separated)"),
+        h("input", { name: "labels" }),
+        h("label", {}, "Assignee"),
+        h("input", { name: "assignee" }),
+        h("label", {}, "Priority"),
+        select("priority", "", [["", "None"]].concat(config.priorities)),
+        h("label", {}, "Due"),
+        h("input", { name: "due", type: "date" }),
+        h("div", { class: "actions" }, h("button", { type: "submit", class: "primary" }, "Create issue")),
+      ]);
+      form.addEventListener("submit", function (e) {
+        e.preventDefault();
+        var issue = {
+          title: form.elements.title.value,
+          body: form.elements.body.value,
+          labels: form.elements.labels.value.split(",").map(function (l) {
+            return l.trim();
+          }).filter(Boolean),
+          assignee: form.elements.assignee.value.trim(),
+          priority: form.elements.priority.value,
+          due: form.elements.due.value,
+        };
+        api("POST", "/issues", { json: issue }).then(function (resp) {
+          flash = "Created #" + resp.data.id + ".";
+          location.hash = "#/issues/" + resp.data.id;
+        }).catch(failed);
+      });
+      show(form);
+    }).catch(failed);
+  }
+
+  // Board
+
+  function boardView(params) {
+    markNav("board");
+    var q = params.get("q") || "";
+    var search = new URLSearchParams({ q: q, status: "all", sort: "priority,updated:desc", limit: 0 });
+    Promise.all([loadConfig(), api("GET", "/issues?" + search.toString())]).then(function (results) {
+      renderBoard(q, results[1].data.issues);
+    }).catch(failed);
+  }
+
+  function renderBoard(q, issues) {
+    var form = h("form", { class: "filters" }, [
+      h("input", { name: "q", type: "search", value: q, placeholder: "Filter the board, e.g. label:backend assignee:mina" }),
+      h("button", { type: "submit" }, "Filter"),
+    ]);
+    form.addEventListener("submit", function (e) {
+      e.preventDefault();
+      var value = form.elements.q.value.trim();
+      location.hash = "#/board" + (value ? "?" + new URLSearchParams({ q: value }).toString() : "");
+    });
+
+    var dragged = null;
+    var columns = config.states.map(function (state) {
+      var cards = issues.filter(function (issue) {
+        return issue.status === state.name;
+      });
+      var shown = cards.slice(0, cardsPerColumn);
+
+      var column = h("div", { class: "column" }, [
+        h("h2", {}, [statusBadge(state.name), " ", h("span", { class: "count" }, String(cards.length))]),
+      ].concat(shown.map(function (issue) {
+        var card = h("a", { class: "card", href: "#/issues/" + issue.id, draggable: "true" }, [
+          h("div", { class: "id" }, "#" + issue.id + (issue.assignee ? " · " + issue.assignee : "") + (issue.priority ? " · " + issue.priority : "")),
+          h("div", {}, issue.title),
+          h("div", {}, labels(issue.labels)),
+        ]);
+        card.addEventListener("dragstart", function (e) {
+          dragged = issue;
+          e.dataTransfer.setData("text/plain", issue.id);
+        });
+        return card;
+      }), cards.length > shown.length ? [h("a", { href: "#/?" + new URLSearchParams({ status: state.name, q: q }).toString() }, "+" + (cards.length - shown.length) + " more")] : []));
+
+      var accepts = function () {
+        if (!dragged || dragged.status === state.name) {
+          return false;
+        }
+        var from = config.states.filter(function (s) {
+          return s.name === dragged.status;
+        })[0];
+        return !from || from.transitions.indexOf(state.name) >= 0;
+      };
+      column.addEventListener("dragover", function (e) {
+        if (accepts()) {
+          e.preventDefault();
+          column.classList.add("drop");
+        }
+      });
+      column.addEventListener("dragleave", function () {
+        column.classList.remove("drop");
+      });
+      column.addEventListener("drop", function (e) {
+        e.preventDefault();
+        column.classList.remove("drop");
+        var issue = dragged;
+        dragged = null;
+        var move = function (force) {
+          api("PATCH", "/issues/" + issue.id, { json: { status: state.name, force: force } }).then(function () {
+            say("Moved #" + issue.id + " to " + state.name + ".");
+            boardView(hashParams().params);
+          }).catch(function (err) {
+            if (confirmForce(err)) {
+              move(true);
+              return;
+            }
+            failed(err);
+          });
+        };
+        move(false);
+      });
+      return column;
+    });
+
+    show(form, h("div", { class: "board" }, columns));
+  }
+
+  // Routing
+
+  function route() {
+    var current = hashParams();
+    var path = current.path || "/";
+    var m;
+    if ((m = path.match(/^\/issues\/([^/]+)\/edit$/))) {
+      editView(decodeURIComponent(m[1]));
+    } else if ((m = path.match(/^\/issues\/([^/]+)$/))) {
+      detailView(decodeURIComponent(m[1]));
+    } else if (path === "/board") {
+      boardView(current.params);
+    } else if (path === "/new") {
+      newView();
+    } else {
+      listView(current.params);
+    }
+  }
+
+  window.addEventListener("hashchange", function () {
+    say(flash);
+    flash = "";
+    route();
+  });
+  route();
+})();
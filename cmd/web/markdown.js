@@ -0,0 +1,198 @@
+// A small Markdown renderer for issue descriptions and comments. Everything is
+// HTML-escaped before any markup is added, so issue text can't inject HTML.
+(function () {
+  "use strict";
+
+  function escapeHTML(text) {
+    return text
+      .replace(/&/g, "&amp;")
+      .replace(/</g, "&lt;")
+      .replace(/>/g, "&gt;")
+      .replace(/"/g, "&quot;")
+      .replace(/'/g, "&#39;");
+  }
+
+  // safeURL allows web, mail and relative links only
+  function safeURL(url) {
+    var decoded = url.replace(/&amp;/g, "&").trim();
+    if (/^(https?:|mailto:|#|\/|\.)/i.test(decoded) || !/^[a-z][a-z0-9+.-]*:/i.test(decoded)) {
+      return url;
+    }
+    return "#";
+  }
+
+  // emphasis renders bold, italic and struck-through text
+  function emphasis(text) {
+    return text
+      .replace(/\*\*([^*]+)\*\*/g, "<strong>$1</strong>")
+      .replace(/__([^_]+)__/g, "<strong>$1</strong>")
+      .replace(/(^|[^*])\*([^*\s][^*]*)\*/g, "$1<em>$2</em>")
+      .replace(/(^|[^\w_])_([^_\s][^_]*)_(?!\w)/g, "$1<em>$2</em>")
+      .replace(/~~([^~]+)~~/g, "<del>$1</del>");
+  }
+
+  // inline renders code spans, links, emphasis and #123 issue references in
+  // escaped text. Each finished code span and link is held out of the text
+  // behind a placeholder, so later passes can't rewrite what's inside it, such
+  // as a URL in the attributes of a link.
+  function inline(text) {
+    var held = [];
+    function hold(html) {
+      held.push(html);
+      return "\u0000" + (held.length - 1) + "\u0000";
+    }
+    function restore(text) {
+      return text.replace(/\u0000(\d+)\u0000/g, function (_, i) {
+        return restore(held[Number(i)]);
+      });
+    }
+
+    text = text
+      .replace(/`([^`]+)`/g, function (_, code) {
+        return hold("<code>" + code + "</code>");
+      })
+      .replace(/!\[([^\]]*)\]\(([^)\s]+)\)/g, function (_, alt, url) {
+        return hold('<img alt="' + alt + '" src="' + safeURL(url) + '">');
+      })
+      .replace(/\[([^\]]+)\]\(([^)\s]+)\)/g, function (_, label, url) {
+        return hold('<a href="' + safeURL(url) + '" rel="noopener noreferrer" target="_blank">' + emphasis(label) + "</a>");
+      })
+      .replace(/(^|[\s(])(https?:\/\/[^\s<)\u0000]+)/g, function (_, before, url) {
+        return before + hold('<a href="' + url + '" rel="noopener noreferrer" target="_blank">' + url + "</a>");
+      })
+      .replace(/(^|[\s(])#(?=[\w-]*\d)([0-9a-z][\w-]*)/gi, function (_, before, id) {
+        return before + hold('<a href="#/issues/' + id + '">#' + id + "</a>");
+      });
+
+    return restore(emphasis(text));
+  }
+
+  function isTableRow(line) {
+    return /^\s*\|.*\|\s*$/.test(line);
+  }
+
+  function tableCells(line) {
+    return line.trim().replace(/^\||\|$/g, "").split("|").map(function (cell) {
+      return cell.trim();
+    });
+  }
+
+  // render converts Markdown to HTML
+  function render(markdown) {
+    var lines = escapeHTML(markdown || "").replace(/\r\n?/g, "\n").split("\n");
+    var html = [];
+    var i = 0;
+
+    while (i < lines.length) {
+      var line = lines[i];
+      var m;
+
+      if (/^\s*$/.test(line)) {
+        i++;
+        continue;
+      }
+
+      // Fenced code
+      if ((m = line.match(/^\s*(```|~~~)\s*([\w-]*)/))) {
+        var fence = m[1];
+        var code = [];
+        i++;
+        while (i < lines.length && lines[i].trim().indexOf(fence) !== 0) {
+          code.push(lines[i]);
+          i++;
+        }
+        i++;
+        html.push("<pre><code" + (m[2] ? ' class="language-' + m[2] + '"' : "") + ">" + code.join("\n") + "</code></pre>");
+        continue;
+      }
+
+      if ((m = line.match(/^(#{1,6})\s+(.*?)\s*#*\s*$/))) {
+        var level = m[1].length;
+        html.push("<h" + level + ">" + inline(m[2]) + "</h" + level + ">");
+        i++;
+        continue;
+      }
+
+      if (/^\s*([-*_])(\s*\1){2,}\s*$/.test(line)) {
+        html.push("<hr>");
+        i++;
+        continue;
+      }
+
+      if (/^\s*&gt;/.test(line)) {
+        var quote = [];
+        while (i < lines.length && /^\s*&gt;/.test(lines[i])) {
+          quote.push(lines[i].replace(/^\s*&gt;\s?/, ""));
+          i++;
+        }
+        // The quoted text is already escaped; render it without escaping again
+        html.push("<blockquote>" + render(quote.join("\n").replace(/&gt;/g, ">").replace(/&lt;/g, "<").replace(/&quot;/g, '"').replace(/&#39;/g, "'").replace(/&amp;/g, "&")) + "</blockquote>");
+        continue;
+      }
+
+      if (isTableRow(line) && i + 1 < lines.length && /^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$/.test(lines[i + 1])) {
+        var head = tableCells(line);
+        i += 2;
+        var rows = [];
+        while (i < lines.length && isTableRow(lines[i])) {
+          rows.push(tableCells(lines[i]));
+          i++;
+        }
+        html.push(
+          "<table><thead><tr>" +
+            head.map(function (c) { return "<th>" + inline(c) + "</th>"; }).join("") +
+            "</tr></thead><tbody>" +
+            rows.map(function (row) {
+              return "<tr>" + row.map(function (c) { return "<td>" + inline(c) + "</td>"; }).join("") + "</tr>";
+            }).join("") +
+            "</tbody></table>"
+        );
+        continue;
+      }
+
+      if ((m = line.match(/^(\s*)([-*+]|\d+[.)])\s+/))) {
+        var ordered = /\d/.test(m[2]);
+        var items = [];
+        while (i < lines.length && (m = lines[i].match(/^\s*([-*+]|\d+[.)])\s+(.*)$/)) && /\d/.test(m[1]) === ordered) {
+          var text = m[2];
+          i++;
+          // Continuation lines
+          while (i < lines.length && /^\s{2,}\S/.test(lines[i]) && !/^\s*([-*+]|\d+[.)])\s+/.test(lines[i])) {
+            text += " " + lines[i].trim();
+            i++;
+          }
+          var task = text.match(/^\[([ xX])\]\s+(.*)$/);
+          if (task) {
+            items.push('<li class="task"><input type="checkbox" disabled' + (task[1] === " " ? "" : " checked") + "> " + inline(task[2]) + "</li>");
+          } else {
+            items.push("<li>" + inline(text) + "</li>");
+          }
+        }
+        var tag = ordered ? "ol" : "ul";
+        html.push("<" + tag + ">" + items.join("") + "</" + tag + ">");
+        continue;
+      }
+
+      // Paragraph: consecutive lines up to a blank line or another block
+      var para = [];
+      while (
+        i < lines.length &&
+        !/^\s*$/.test(lines[i]) &&
+        !/^(#{1,6}\s|\s*(```|~~~)|\s*&gt;|\s*([-*+]|\d+[.)])\s+)/.test(lines[i])
+      ) {
+        para.push(lines[i].trim());
+        i++;
+      }
+      if (para.length === 0) {
+        // A line no block accepted; show it as text
+        para.push(lines[i].trim());
+        i++;
+      }
+      html.push("<p>" + inline(para.join("\n")).replace(/\n/g, "<br>") + "</p>");
+    }
+
+    return html.join("\n");
+  }
+
+  window.markdown = { render: render, escapeHTML: escapeHTML };
+})();
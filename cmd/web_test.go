@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/Allra-Fintech/git-issue/pkg"
+)
+
+func TestWebHandler(t *testing.T) {
+	_, cleanup := setupCommandTestRepo(t)
+	defer cleanup()
+
+	if err := runCreate(nil, []string{"Fix Redis connection timeout"}); err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(newWebHandler(pkg.DefaultRepository()))
+	defer server.Close()
+
+	tests := []struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		{"/", "text/html", `<script src="app.js">`},
+		{"/app.js", "javascript", "function route()"},
+		{"/markdown.js", "javascript", "window.markdown"},
+		{"/style.css", "text/css", ".board"},
+		{"/api/issues", "application/json", `"title": "Fix Redis connection timeout"`},
+		{"/api/config", "application/json", `"name": "open"`},
+	}
+	for _, tt := range tests {
+		resp, err := server.Client().Get(server.URL + tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK || !strings.Contains(resp.Header.Get("Content-Type"), tt.contentType) || !strings.Contains(string(body), tt.contains) {
+			t.Errorf("GET %s = %d %s, want %s containing %q", tt.path, resp.StatusCode, resp.Header.Get("Content-Type"), tt.contentType, tt.contains)
+		}
+	}
+
+	// The API keeps refusing other sites under /api
+	if resp := apiRequest(t, server, "POST", "/api/issues/001/close", "", map[string]string{"Origin": "http://evil.example"}, nil); resp.StatusCode != http.StatusForbidden {
+		t.Errorf("POST /api/issues/001/close from another origin = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestMarkdownRenderer(t *testing.T) {
+	node, err := exec.LookPath("node")
+	if err != nil {
+		t.Skip("node isn't installed")
+	}
+	out, err := exec.Command(node, "testdata/markdown_test.js", "web/markdown.js").CombinedOutput()
+	if err != nil {
+		t.Errorf("markdown_test.js failed: %v\n%s", err, out)
+	}
+}
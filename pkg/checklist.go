@@ -0,0 +1,173 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// SuccessCriteriaHeading is the template section whose task items must be
+// checked for gi close --strict
+const SuccessCriteriaHeading = "Success Criteria"
+
+// Task is an item of a Markdown task list ("- [ ] text") in an issue body
+type Task struct {
+	Text    string `json:"text" yaml:"text"`
+	Checked bool   `json:"checked" yaml:"checked"`
+	// Section is the heading the item is under, "" before the first heading
+	Section string `json:"section" yaml:"section"`
+
+	line int // line of the item in the body
+}
+
+// TaskProgress counts the checked items of a task list
+type TaskProgress struct {
+	Checked int
+	Total   int
+}
+
+// String renders the progress as "2/5"
+func (p TaskProgress) String() string {
+	return fmt.Sprintf("%d/%d", p.Checked, p.Total)
+}
+
+// taskRe matches a task list item: "- [ ] text", "* [x] text", "1. [X] text"
+var taskRe = regexp.MustCompile(`^(\s*(?:[-*+]|\d+[.)])\s+\[)([ xX])(\]\s+)(.*)$`)
+
+// parseTasks extracts the task list items of a Markdown body, ignoring code blocks
+func parseTasks(body string) []Task {
+	var tasks []Task
+	section := ""
+	fence := ""
+	for i, line := range strings.Split(body, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		// Skip fenced code blocks
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			fence = trimmed[:3]
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "#") {
+			section = strings.TrimSpace(strings.TrimLeft(trimmed, "#"))
+			continue
+		}
+
+		if m := taskRe.FindStringSubmatch(line); m != nil {
+			tasks = append(tasks, Task{
+				Text:    strings.TrimSpace(m[4]),
+				Checked: m[2] != " ",
+				Section: section,
+				line:    i,
+			})
+		}
+	}
+	return tasks
+}
+
+// Tasks returns the task list items of the issue body, in order
+func (i *Issue) Tasks() []Task {
+	return parseTasks(i.Body)
+}
+
+// TaskProgress counts the issue's checked task list items
+func (i *Issue) TaskProgress() TaskProgress {
+	tasks := i.Tasks()
+	p := TaskProgress{Total: len(tasks)}
+	for _, task := range tasks {
+		if task.Checked {
+			p.Checked++
+		}
+	}
+	return p
+}
+
+// UncheckedCriteria returns the unchecked items of the issue's Success
+// Criteria section, or of its whole task list when it has no such section
+func (i *Issue) UncheckedCriteria() []Task {
+	tasks := i.Tasks()
+
+	hasSection := false
+	for _, task := range tasks {
+		if strings.EqualFold(task.Section, SuccessCriteriaHeading) {
+			hasSection = true
+			break
+		}
+	}
+
+	var unchecked []Task
+	for _, task := range tasks {
+		if task.Checked || hasSection && !strings.EqualFold(task.Section, SuccessCriteriaHeading) {
+			continue
+		}
+		unchecked = append(unchecked, task)
+	}
+	return unchecked
+}
+
+// setTask checks or unchecks the nth (1-based) task list item of the body
+func (i *Issue) setTask(n int, checked bool) (Task, error) {
+	tasks := i.Tasks()
+	if n < 1 || n > len(tasks) {
+		if len(tasks) == 0 {
+			return Task{}, fmt.Errorf("issue %s has no task list items", i.ID)
+		}
+		return Task{}, fmt.Errorf("issue %s has no task %d (it has %d)", i.ID, n, len(tasks))
+	}
+
+	task := tasks[n-1]
+	mark := " "
+	if checked {
+		mark = "x"
+	}
+
+	lines := strings.Split(i.Body, "\n")
+	lines[task.line] = taskRe.ReplaceAllString(lines[task.line], "${1}"+mark+"${3}${4}")
+	i.Body = strings.Join(lines, "\n")
+
+	task.Checked = checked
+	return task, nil
+}
+
+// CheckTask checks (or, with checked false, unchecks) the nth task list item
+// of issue id, counting from 1, and returns it
+func (r *Repository) CheckTask(id string, n int, checked bool) (*Task, error) {
+	var task *Task
+	err := r.withLock(func() error {
+		var err error
+		task, err = r.checkTask(id, n, checked)
+		return err
+	})
+	return task, err
+}
+
+// checkTask implements CheckTask; the caller must hold the store lock
+func (r *Repository) checkTask(id string, n int, checked bool) (*Task, error) {
+	issue, dir, err := r.LoadIssue(id)
+	if err != nil {
+		return nil, err
+	}
+
+	task, err := issue.setTask(n, checked)
+	if err != nil {
+		return nil, err
+	}
+	issue.Updated = time.Now()
+
+	if err := r.saveIssue(issue, dir); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// CheckTask checks or unchecks a task list item of an issue in the resolved .issues directory
+func CheckTask(id string, n int, checked bool) (*Task, error) {
+	return DefaultRepository().CheckTask(id, n, checked)
+}
@@ -0,0 +1,91 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+const checklistBody = `Intro
+
+- [x] Write spec
+* [ ] Review spec
+  - [X] Nested item
+
+` + "```" + `
+- [ ] not a task, inside a code block
+` + "```" + `
+
+## Success Criteria
+
+1. [ ] Tests pass
+2. [x] Docs updated
+- [] not a task either`
+
+func TestParseTasks(t *testing.T) {
+	issue := &Issue{ID: "001", Body: checklistBody}
+
+	tasks := issue.Tasks()
+	want := []Task{
+		{Text: "Write spec", Checked: true},
+		{Text: "Review spec"},
+		{Text: "Nested item", Checked: true},
+		{Text: "Tests pass", Section: SuccessCriteriaHeading},
+		{Text: "Docs updated", Checked: true, Section: SuccessCriteriaHeading},
+	}
+	if len(tasks) != len(want) {
+		t.Fatalf("Tasks() = %+v, want %d items", tasks, len(want))
+	}
+	for i := range want {
+		if tasks[i].Text != want[i].Text || tasks[i].Checked != want[i].Checked || tasks[i].Section != want[i].Section {
+			t.Errorf("task %d = %+v, want %+v", i+1, tasks[i], want[i])
+		}
+	}
+
+	if got := issue.TaskProgress(); got != (TaskProgress{Checked: 3, Total: 5}) || got.String() != "3/5" {
+		t.Errorf("TaskProgress() = %+v", got)
+	}
+
+	// Only the Success Criteria section counts when there is one
+	unchecked := issue.UncheckedCriteria()
+	if len(unchecked) != 1 || unchecked[0].Text != "Tests pass" {
+		t.Errorf("UncheckedCriteria() = %+v", unchecked)
+	}
+	noSection := &Issue{Body: "- [ ] a\n- [x] b"}
+	if unchecked := noSection.UncheckedCriteria(); len(unchecked) != 1 || unchecked[0].Text != "a" {
+		t.Errorf("UncheckedCriteria() without a section = %+v", unchecked)
+	}
+}
+
+func TestCheckTask(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	created := createWithID(t, repo, "Checklist", "")
+	created.Body = checklistBody
+	if err := repo.SaveIssue(created, OpenDir); err != nil {
+		t.Fatal(err)
+	}
+
+	task, err := repo.CheckTask(created.ID, 4, true)
+	if err != nil {
+		t.Fatalf("CheckTask() error = %v", err)
+	}
+	if task.Text != "Tests pass" || !task.Checked {
+		t.Errorf("CheckTask() = %+v", task)
+	}
+	if _, err := repo.CheckTask(created.ID, 1, false); err != nil {
+		t.Fatal(err)
+	}
+
+	issue, _, _ := repo.LoadIssue(created.ID)
+	if !strings.Contains(issue.Body, "1. [x] Tests pass") || !strings.Contains(issue.Body, "- [ ] Write spec") {
+		t.Errorf("body after CheckTask():\n%s", issue.Body)
+	}
+	if strings.Replace(strings.Replace(issue.Body, "1. [x]", "1. [ ]", 1), "- [ ] Write", "- [x] Write", 1) != checklistBody {
+		t.Errorf("CheckTask() changed more than the check marks:\n%s", issue.Body)
+	}
+
+	for _, n := range []int{0, 6} {
+		if _, err := repo.CheckTask(created.ID, n, true); err == nil {
+			t.Errorf("CheckTask(%d) should fail", n)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package pkg
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// CommentsHeading starts the comment thread at the end of an issue file
+const CommentsHeading = "## Comments"
+
+// Comment is an entry in an issue's discussion thread. In the file it's a
+// "### author — timestamp" heading followed by the text, under ## Comments.
+type Comment struct {
+	Author  string    `json:"author" yaml:"author"`
+	Created time.Time `json:"created" yaml:"created"`
+	Body    string    `json:"body" yaml:"body"`
+}
+
+// commentHeadingRe matches a comment heading: ### author — 2026-10-18T10:00:00Z
+var commentHeadingRe = regexp.MustCompile(`^### (.+) — (\S+)$`)
+
+// parseCommentHeading returns the author and time of a comment heading line
+func parseCommentHeading(line string) (string, time.Time, bool) {
+	m := commentHeadingRe.FindStringSubmatch(strings.TrimRight(line, " \t\r"))
+	if m == nil {
+		return "", time.Time{}, false
+	}
+	created, err := time.Parse(time.RFC3339, m[2])
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return strings.TrimSpace(m[1]), created, true
+}
+
+// splitComments separates the comment thread from an issue body. The thread
+// starts at the first "## Comments" heading followed by a comment entry and
+// runs to the end, so a comment quoting a "## Comments" line stays part of
+// the thread; a section with any other content is left in the body.
+func splitComments(body string) (string, []Comment) {
+	lines := strings.Split(body, "\n")
+
+	start := -1
+	for i := 0; i < len(lines) && start < 0; i++ {
+		if strings.TrimRight(lines[i], " \t\r") != CommentsHeading {
+			continue
+		}
+		for _, line := range lines[i+1:] {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			if _, _, ok := parseCommentHeading(line); ok {
+				start = i
+			}
+			break
+		}
+	}
+	if start < 0 {
+		return body, nil
+	}
+
+	var comments []Comment
+	var text []string
+	flush := func() {
+		if len(comments) > 0 {
+			comments[len(comments)-1].Body = strings.TrimSpace(strings.Join(text, "\n"))
+		}
+		text = nil
+	}
+
+	for _, line := range lines[start+1:] {
+		if author, created, ok := parseCommentHeading(line); ok {
+			flush()
+			comments = append(comments, Comment{Author: author, Created: created})
+			continue
+		}
+		if len(comments) == 0 {
+			continue // blank lines before the first entry
+		}
+		text = append(text, line)
+	}
+	flush()
+
+	return strings.TrimSpace(strings.Join(lines[:start], "\n")), comments
+}
+
+// serializeComments renders the comment thread, or "" when there are no comments
+func serializeComments(comments []Comment) string {
+	if len(comments) == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(CommentsHeading + "\n")
+	for _, c := range comments {
+		fmt.Fprintf(&b, "\n### %s — %s\n", c.Author, c.Created.Format(time.RFC3339))
+		if c.Body != "" {
+			b.WriteString("\n" + c.Body + "\n")
+		}
+	}
+	return b.String()
+}
+
+// AddComment appends a comment by author to an issue's thread and returns it
+func (r *Repository) AddComment(id, author, text string) (*Comment, error) {
+	var comment *Comment
+	err := r.withLock(func() error {
+		var err error
+		comment, err = r.addComment(id, author, text)
+		return err
+	})
+	return comment, err
+}
+
+// addComment implements AddComment; the caller must hold the store lock
+func (r *Repository) addComment(id, author, text string) (*Comment, error) {
+	author = strings.TrimSpace(author)
+	text = strings.TrimSpace(text)
+	if author == "" {
+		return nil, fmt.Errorf("comment author cannot be empty")
+	}
+	if strings.Contains(author, "\n") {
+		return nil, fmt.Errorf("comment author cannot span several lines")
+	}
+	if text == "" {
+		return nil, fmt.Errorf("comment cannot be empty")
+	}
+
+	name, _, err := r.resolveIssue(id)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := r.store.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issue file: %w", err)
+	}
+
+	issue, err := ParseMarkdown(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse issue: %w", err)
+	}
+
+	now := time.Now().Truncate(time.Second)
+	issue.Comments = append(issue.Comments, Comment{Author: author, Created: now, Body: text})
+	issue.Updated = now
+
+	content, err := SerializeIssue(issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize issue: %w", err)
+	}
+
+	if err := r.store.WriteFile(name, []byte(content)); err != nil {
+		return nil, fmt.Errorf("failed to write issue file: %w", err)
+	}
+
+	return &issue.Comments[len(issue.Comments)-1], nil
+}
+
+// AddComment appends a comment to an issue in the resolved .issues directory
+func AddComment(id, author, text string) (*Comment, error) {
+	return DefaultRepository().AddComment(id, author, text)
+}
@@ -0,0 +1,141 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const issueWithComments = `---
+id: "001"
+assignee: alice
+labels: []
+created: 2026-09-01T10:00:00Z
+updated: 2026-09-01T10:00:00Z
+---
+
+# Login fails
+
+Steps to reproduce.
+
+## Comments
+
+### Jane Doe — 2026-09-02T09:30:00Z
+
+Reproduced on staging.
+
+Second paragraph.
+
+### bob — 2026-09-03T11:00:00+09:00
+
+Fixed in #42.
+`
+
+func TestParseMarkdownComments(t *testing.T) {
+	issue, err := ParseMarkdown(issueWithComments)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+
+	if issue.Body != "Steps to reproduce." {
+		t.Errorf("Body = %q, comments should not be part of it", issue.Body)
+	}
+	if len(issue.Comments) != 2 {
+		t.Fatalf("Comments = %+v, want 2", issue.Comments)
+	}
+
+	first := issue.Comments[0]
+	if first.Author != "Jane Doe" || first.Body != "Reproduced on staging.\n\nSecond paragraph." {
+		t.Errorf("first comment = %+v", first)
+	}
+	if !first.Created.Equal(time.Date(2026, 9, 2, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("first comment time = %v", first.Created)
+	}
+	if issue.Comments[1].Author != "bob" || issue.Comments[1].Body != "Fixed in #42." {
+		t.Errorf("second comment = %+v", issue.Comments[1])
+	}
+
+	// Untouched issues round-trip byte for byte
+	out, err := SerializeIssue(issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != issueWithComments {
+		t.Errorf("SerializeIssue() changed an unmodified issue:\n%s", out)
+	}
+}
+
+func TestCommentsSectionWithOtherContentStaysInBody(t *testing.T) {
+	content := "---\nid: \"001\"\n---\n\n# Title\n\nIntro\n\n## Comments\n\nPlease keep comments short.\n"
+	issue, err := ParseMarkdown(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issue.Comments) != 0 || !strings.Contains(issue.Body, "Please keep comments short.") {
+		t.Errorf("Body = %q, Comments = %+v", issue.Body, issue.Comments)
+	}
+}
+
+func TestCommentQuotingCommentsHeading(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	created := createWithID(t, repo, "Template issue", "")
+
+	quoted := "The template should end with:\n\n## Comments\n\nand nothing else."
+	for _, c := range []struct{ author, text string }{{"alice", "First"}, {"bob", quoted}, {"carol", "Last"}} {
+		if _, err := repo.AddComment(created.ID, c.author, c.text); err != nil {
+			t.Fatalf("AddComment() error = %v", err)
+		}
+	}
+
+	issue, _, err := repo.LoadIssue(created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issue.Comments) != 3 {
+		t.Fatalf("Comments = %+v, want 3", issue.Comments)
+	}
+	if issue.Comments[0].Body != "First" || issue.Comments[1].Body != quoted || issue.Comments[2].Body != "Last" {
+		t.Errorf("Comments = %+v", issue.Comments)
+	}
+	if issue.Body != created.Body {
+		t.Errorf("Body = %q, want it unchanged", issue.Body)
+	}
+}
+
+func TestAddComment(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	created := createWithID(t, repo, "Discuss me", "")
+
+	if _, err := repo.AddComment(created.ID, "alice", "  First thoughts  "); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+	if _, err := repo.AddComment(created.ID, "bob", "Agreed.\n\nLet's ship it."); err != nil {
+		t.Fatalf("AddComment() error = %v", err)
+	}
+
+	issue, _, err := repo.LoadIssue(created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(issue.Comments) != 2 {
+		t.Fatalf("Comments = %+v, want 2", issue.Comments)
+	}
+	if issue.Comments[0].Author != "alice" || issue.Comments[0].Body != "First thoughts" {
+		t.Errorf("first comment = %+v", issue.Comments[0])
+	}
+	if issue.Comments[1].Body != "Agreed.\n\nLet's ship it." {
+		t.Errorf("second comment = %+v", issue.Comments[1])
+	}
+	if issue.Body != created.Body {
+		t.Errorf("Body changed after commenting: %q", issue.Body)
+	}
+
+	for _, tt := range []struct{ author, text string }{{"", "text"}, {"alice", "  "}, {"a\nb", "text"}} {
+		if _, err := repo.AddComment(created.ID, tt.author, tt.text); err == nil {
+			t.Errorf("AddComment(%q, %q) should fail", tt.author, tt.text)
+		}
+	}
+	if _, err := repo.AddComment("999", "alice", "text"); err == nil {
+		t.Error("AddComment() on a missing issue should fail")
+	}
+}
@@ -0,0 +1,241 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// stressRootEnv tells the helper process which .issues directory to create issues in
+const stressRootEnv = "GI_STRESS_ROOT"
+
+// createIssues allocates IDs and saves issues the same way `gi create` does
+func createIssues(repo *Repository, worker, count int) error {
+	for i := 0; i < count; i++ {
+		id, err := repo.GetNextID()
+		if err != nil {
+			return err
+		}
+		issue := repo.NewIssue(id, fmt.Sprintf("Worker %d issue %d", worker, i), "", nil)
+		if err := repo.SaveIssue(issue, OpenDir); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// assertUniqueIssues checks that every issue in root has its own ID and parses cleanly
+func assertUniqueIssues(t *testing.T, root string, want int) {
+	t.Helper()
+
+	repo := OpenRepository(root)
+	issues, err := repo.ListIssues(OpenDir)
+	if err != nil {
+		t.Fatalf("ListIssues() error = %v", err)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(root, OpenDir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var files int
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("temporary file left behind: %s", entry.Name())
+		}
+		if strings.HasSuffix(entry.Name(), ".md") {
+			files++
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, issue := range issues {
+		if seen[issue.ID] {
+			t.Errorf("duplicate ID %s", issue.ID)
+		}
+		seen[issue.ID] = true
+	}
+
+	if files != want || len(issues) != want || len(seen) != want {
+		t.Errorf("got %d files, %d parsed issues, %d unique IDs; want %d", files, len(issues), len(seen), want)
+	}
+}
+
+func TestConcurrentCreatorsGetUniqueIDs(t *testing.T) {
+	root := filepath.Join(t.TempDir(), IssuesDir)
+	if err := OpenRepository(root).Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	const workers, perWorker = 16, 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, workers)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(worker int) {
+			defer wg.Done()
+			// Each worker opens its own repository, like a separate gi invocation
+			if err := createIssues(OpenRepository(root), worker, perWorker); err != nil {
+				errs <- err
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("concurrent create failed: %v", err)
+	}
+
+	assertUniqueIssues(t, root, workers*perWorker)
+}
+
+func TestConcurrentProcessesGetUniqueIDs(t *testing.T) {
+	if testing.Short() {
+		t.Skip("spawns processes")
+	}
+
+	root := filepath.Join(t.TempDir(), IssuesDir)
+	if err := OpenRepository(root).Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	const processes, perProcess = 8, 10
+
+	cmds := make([]*exec.Cmd, processes)
+	for p := range cmds {
+		cmd := exec.Command(os.Args[0], "-test.run=^TestHelperProcessCreateIssues$", fmt.Sprintf("-test.count=%d", 1))
+		cmd.Env = append(os.Environ(), stressRootEnv+"="+root, fmt.Sprintf("GI_STRESS_WORKER=%d", p))
+		if err := cmd.Start(); err != nil {
+			t.Fatalf("failed to start helper process: %v", err)
+		}
+		cmds[p] = cmd
+	}
+	for _, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("helper process failed: %v", err)
+		}
+	}
+
+	assertUniqueIssues(t, root, processes*perProcess)
+}
+
+// TestHelperProcessCreateIssues is run as a subprocess by TestConcurrentProcessesGetUniqueIDs
+func TestHelperProcessCreateIssues(t *testing.T) {
+	root := os.Getenv(stressRootEnv)
+	if root == "" {
+		t.Skip("helper process")
+	}
+
+	var worker int
+	_, _ = fmt.Sscan(os.Getenv("GI_STRESS_WORKER"), &worker)
+	if err := createIssues(OpenRepository(root), worker, 10); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFSStoreWriteFileIsAtomic(t *testing.T) {
+	store := NewFSStore(t.TempDir())
+
+	if err := store.WriteFile("file.md", []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.WriteFile("file.md", []byte("second")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := store.ReadFile("file.md")
+	if err != nil || string(data) != "second" {
+		t.Errorf("ReadFile() = %q, %v; want second", data, err)
+	}
+
+	info, err := os.Stat(store.Path("file.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0644 {
+		t.Errorf("file mode = %v, want 0644", info.Mode().Perm())
+	}
+
+	names, err := store.ReadDir(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 1 {
+		t.Errorf("ReadDir() = %v, temporary files should not be left behind", names)
+	}
+}
+
+func TestStoreLockIsExclusive(t *testing.T) {
+	stores := map[string]Store{
+		"fs":     NewFSStore(t.TempDir()),
+		"memory": NewMemStore(),
+	}
+
+	for name, store := range stores {
+		t.Run(name, func(t *testing.T) {
+			const workers, rounds = 8, 20
+
+			// Count the holders of the lock; it's never more than one when the lock works
+			var holders atomic.Int32
+			var overlapped atomic.Bool
+			var wg sync.WaitGroup
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < rounds; i++ {
+						unlock, err := store.Lock()
+						if err != nil {
+							t.Errorf("Lock() error = %v", err)
+							return
+						}
+						if holders.Add(1) > 1 {
+							overlapped.Store(true)
+						}
+						time.Sleep(100 * time.Microsecond)
+						holders.Add(-1)
+						unlock()
+					}
+				}()
+			}
+			wg.Wait()
+
+			if overlapped.Load() {
+				t.Error("the lock was held by several goroutines at once")
+			}
+		})
+	}
+}
+
+func TestLockAddsGitignoreEntries(t *testing.T) {
+	root := filepath.Join(t.TempDir(), IssuesDir)
+	repo := OpenRepository(root)
+	if err := repo.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	// A repository initialized before the lock and cache were ignored
+	if err := os.WriteFile(filepath.Join(root, ".gitignore"), []byte("notes.txt"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.GetNextID(); err != nil {
+		t.Fatalf("GetNextID() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(root, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "notes.txt\n" + LockFile + "\n" + CacheDir + "/\n"; string(data) != want {
+		t.Errorf(".gitignore = %q, want %q", data, want)
+	}
+}
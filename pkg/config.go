@@ -0,0 +1,316 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigFile is the repository configuration stored in the .issues directory
+const ConfigFile = "config.yaml"
+
+// DateLayout is the format used for date custom fields
+const DateLayout = "2006-01-02"
+
+// FieldType is the type of a custom field declared in config.yaml
+type FieldType string
+
+const (
+	FieldString FieldType = "string"
+	FieldEnum   FieldType = "enum"
+	FieldInt    FieldType = "int"
+	FieldDate   FieldType = "date"
+	FieldUser   FieldType = "user"
+	FieldList   FieldType = "list"
+)
+
+// Config is the repository configuration read from .issues/config.yaml
+type Config struct {
+	Fields   []FieldDef `yaml:"fields,omitempty"`
+	Workflow Workflow   `yaml:"workflow,omitempty"`
+	IDs      IDConfig   `yaml:"ids,omitempty"`
+	// Priorities lists the priority levels, highest first (see PriorityLevels)
+	Priorities []string `yaml:"priorities,omitempty"`
+	// Views are the shared saved views (see View)
+	Views []View `yaml:"views,omitempty"`
+}
+
+// FieldDef declares a typed custom frontmatter field
+type FieldDef struct {
+	Name        string      `yaml:"name"`
+	Type        FieldType   `yaml:"type"`
+	Values      []string    `yaml:"values,omitempty"` // allowed values for enum fields
+	Default     interface{} `yaml:"default,omitempty"`
+	Required    bool        `yaml:"required,omitempty"`
+	Description string      `yaml:"description,omitempty"`
+}
+
+// LoadConfig reads and validates config.yaml. A missing file yields an empty config.
+func (r *Repository) LoadConfig() (*Config, error) {
+	data, err := r.store.ReadFile(ConfigFile)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", ConfigFile, err)
+	}
+
+	return ParseConfig(data)
+}
+
+// SaveConfig writes config.yaml
+func (r *Repository) SaveConfig(cfg *Config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to marshal config: %w", err)
+	}
+
+	if err := r.store.WriteFile(ConfigFile, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ConfigFile, err)
+	}
+
+	return nil
+}
+
+// ParseConfig parses and validates the contents of a config.yaml file
+func ParseConfig(data []byte) (*Config, error) {
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", ConfigFile, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", ConfigFile, err)
+	}
+
+	return &cfg, nil
+}
+
+// Validate checks that the custom field, workflow and ID declarations are well formed
+func (c *Config) Validate() error {
+	if err := c.Workflow.Validate(); err != nil {
+		return err
+	}
+	if err := c.IDs.Validate(); err != nil {
+		return err
+	}
+	if err := validateViews(c.Views); err != nil {
+		return err
+	}
+	for i, level := range c.Priorities {
+		if strings.TrimSpace(level) == "" {
+			return fmt.Errorf("priority #%d is empty", i+1)
+		}
+		if containsString(c.Priorities[:i], level) {
+			return fmt.Errorf("priority %q is listed more than once", level)
+		}
+	}
+
+	seen := make(map[string]bool)
+	for i := range c.Fields {
+		field := &c.Fields[i]
+
+		if field.Name == "" {
+			return fmt.Errorf("field #%d has no name", i+1)
+		}
+		if isKnownField(field.Name) {
+			return fmt.Errorf("field %q clashes with a built-in field", field.Name)
+		}
+		if seen[field.Name] {
+			return fmt.Errorf("field %q is declared more than once", field.Name)
+		}
+		seen[field.Name] = true
+
+		if field.Type == "" {
+			field.Type = FieldString
+		}
+		switch field.Type {
+		case FieldString, FieldInt, FieldDate, FieldUser, FieldList:
+		case FieldEnum:
+			if len(field.Values) == 0 {
+				return fmt.Errorf("enum field %q has no values", field.Name)
+			}
+		default:
+			return fmt.Errorf("field %q has unknown type %q", field.Name, field.Type)
+		}
+
+		if field.Default != nil {
+			if err := field.Check(field.Default); err != nil {
+				return fmt.Errorf("default for %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Field returns the declaration of a custom field, or nil if it isn't declared
+func (c *Config) Field(name string) *FieldDef {
+	for i := range c.Fields {
+		if c.Fields[i].Name == name {
+			return &c.Fields[i]
+		}
+	}
+	return nil
+}
+
+// ApplyDefaults sets declared defaults for custom fields the issue doesn't have
+func (c *Config) ApplyDefaults(issue *Issue) {
+	for _, field := range c.Fields {
+		if field.Default == nil {
+			continue
+		}
+		if _, ok := issue.Field(field.Name); !ok {
+			issue.SetField(field.Name, field.Default)
+		}
+	}
+}
+
+// ValidateIssue checks an issue's custom fields against the schema, and its
+// priority and due date
+func (c *Config) ValidateIssue(issue *Issue) error {
+	for _, field := range c.Fields {
+		value, ok := issue.Field(field.Name)
+		if !ok || value == nil {
+			if field.Required {
+				return fmt.Errorf("field %q is required", field.Name)
+			}
+			continue
+		}
+		if err := field.Check(value); err != nil {
+			return err
+		}
+	}
+	return c.validatePlanning(issue)
+}
+
+// Parse converts a command-line value into the field's type
+func (f *FieldDef) Parse(raw string) (interface{}, error) {
+	raw = strings.TrimSpace(raw)
+
+	var value interface{}
+	switch f.Type {
+	case FieldInt:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return nil, fmt.Errorf("field %q: %q is not an integer", f.Name, raw)
+		}
+		value = n
+	case FieldList:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		value = items
+	default:
+		value = raw
+	}
+
+	if err := f.Check(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// Check validates a value (as decoded from YAML or returned by Parse) against the field's type
+func (f *FieldDef) Check(value interface{}) error {
+	switch f.Type {
+	case FieldInt:
+		if _, ok := value.(int); !ok {
+			return fmt.Errorf("field %q: %v is not an integer", f.Name, value)
+		}
+	case FieldDate:
+		s := FormatFieldValue(value)
+		if _, err := time.Parse(DateLayout, s); err != nil {
+			return fmt.Errorf("field %q: %q is not a date (YYYY-MM-DD)", f.Name, s)
+		}
+	case FieldEnum:
+		s, ok := value.(string)
+		if !ok || !containsString(f.Values, s) {
+			return fmt.Errorf("field %q: %v is not one of %s", f.Name, value, strings.Join(f.Values, ", "))
+		}
+	case FieldUser:
+		s, ok := value.(string)
+		if !ok || s == "" || strings.ContainsAny(s, " \t") {
+			return fmt.Errorf("field %q: %q is not a valid user name", f.Name, FormatFieldValue(value))
+		}
+	case FieldList:
+		switch value.(type) {
+		case []string, []interface{}:
+		default:
+			return fmt.Errorf("field %q: %v is not a list", f.Name, value)
+		}
+	default:
+		// Unquoted YAML scalars like 42 or true are fine as strings; collections aren't
+		switch value.(type) {
+		case []string, []interface{}, map[string]interface{}:
+			return fmt.Errorf("field %q: %v is not a string", f.Name, value)
+		}
+	}
+	return nil
+}
+
+// FormatFieldValue renders a frontmatter value for display and comparison
+func FormatFieldValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		// YAML decodes unquoted dates as timestamps
+		if v.Hour() == 0 && v.Minute() == 0 && v.Second() == 0 && v.Nanosecond() == 0 {
+			return v.Format(DateLayout)
+		}
+		return v.Format(time.RFC3339)
+	case []string:
+		return strings.Join(v, ", ")
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = FormatFieldValue(item)
+		}
+		return strings.Join(items, ", ")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// MatchFieldValue reports whether a frontmatter value matches a filter string.
+// List values match when any element matches.
+func MatchFieldValue(value interface{}, want string) bool {
+	switch v := value.(type) {
+	case []string:
+		return containsString(v, want)
+	case []interface{}:
+		for _, item := range v {
+			if FormatFieldValue(item) == want {
+				return true
+			}
+		}
+		return false
+	default:
+		return FormatFieldValue(value) == want
+	}
+}
+
+// LoadConfig reads config.yaml from the resolved .issues directory
+func LoadConfig() (*Config, error) {
+	return DefaultRepository().LoadConfig()
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,171 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+const testConfig = `fields:
+  - name: priority
+    type: enum
+    values: [low, medium, high]
+    default: medium
+  - name: estimate
+    type: int
+  - name: due
+    type: date
+  - name: reviewer
+    type: user
+  - name: components
+    type: list
+  - name: customer
+    type: string
+    required: true
+`
+
+func TestParseConfig(t *testing.T) {
+	cfg, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("ParseConfig() error = %v", err)
+	}
+
+	if len(cfg.Fields) != 6 {
+		t.Fatalf("len(Fields) = %d, want 6", len(cfg.Fields))
+	}
+	if f := cfg.Field("priority"); f == nil || f.Type != FieldEnum || f.Default != "medium" {
+		t.Errorf("Field(priority) = %+v", f)
+	}
+	if cfg.Field("missing") != nil {
+		t.Error("Field() should return nil for undeclared fields")
+	}
+}
+
+func TestParseConfigErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"unknown type", "fields:\n  - name: x\n    type: color\n", "unknown type"},
+		{"enum without values", "fields:\n  - name: x\n    type: enum\n", "has no values"},
+		{"builtin clash", "fields:\n  - name: assignee\n", "built-in"},
+		{"duplicate", "fields:\n  - name: x\n  - name: x\n", "more than once"},
+		{"bad default", "fields:\n  - name: x\n    type: int\n    default: lots\n", "not an integer"},
+		{"missing name", "fields:\n  - type: int\n", "no name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFieldDefParse(t *testing.T) {
+	cfg, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		field   string
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"priority", "high", "high", false},
+		{"priority", "urgent", "", true},
+		{"estimate", "3", "3", false},
+		{"estimate", "three", "", true},
+		{"due", "2026-11-01", "2026-11-01", false},
+		{"due", "next week", "", true},
+		{"reviewer", "mina", "mina", false},
+		{"reviewer", "two people", "", true},
+		{"components", "api, web", "api, web", false},
+		{"customer", "acme", "acme", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.field+"="+tt.raw, func(t *testing.T) {
+			value, err := cfg.Field(tt.field).Parse(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && FormatFieldValue(value) != tt.want {
+				t.Errorf("Parse() = %v, want %s", FormatFieldValue(value), tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateIssueRequiredFields(t *testing.T) {
+	cfg, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue := &Issue{ID: "001", Title: "Needs a customer"}
+	cfg.ApplyDefaults(issue)
+	if value, _ := issue.Field("priority"); value != "medium" {
+		t.Errorf("ApplyDefaults() priority = %v, want medium", value)
+	}
+
+	if err := cfg.ValidateIssue(issue); err == nil || !strings.Contains(err.Error(), "customer") {
+		t.Errorf("ValidateIssue() error = %v, want missing customer", err)
+	}
+
+	issue.SetField("customer", "acme")
+	if err := cfg.ValidateIssue(issue); err != nil {
+		t.Errorf("ValidateIssue() error = %v", err)
+	}
+
+	issue.SetField("priority", "urgent")
+	if err := cfg.ValidateIssue(issue); err == nil {
+		t.Error("ValidateIssue() should reject values outside the enum")
+	}
+}
+
+func TestValidateIssueFromParsedFile(t *testing.T) {
+	cfg, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issue, err := ParseMarkdown("---\nid: \"001\"\ncustomer: acme\nestimate: 5\ndue: 2026-11-01\ncomponents: [api, web]\n---\n\n# Parsed\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cfg.ValidateIssue(issue); err != nil {
+		t.Errorf("ValidateIssue() error = %v", err)
+	}
+	if due, _ := issue.Field("due"); FormatFieldValue(due) != "2026-11-01" {
+		t.Errorf("due = %q, want 2026-11-01", FormatFieldValue(due))
+	}
+	if components, _ := issue.Field("components"); !MatchFieldValue(components, "web") {
+		t.Error("MatchFieldValue() should match list elements")
+	}
+}
+
+func TestNewIssueAppliesConfigDefaults(t *testing.T) {
+	repo := NewRepository(NewMemStore())
+	if err := repo.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := ParseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveConfig(cfg); err != nil {
+		t.Fatalf("SaveConfig() error = %v", err)
+	}
+
+	issue := repo.NewIssue(1, "Defaults", "", nil)
+	if value, _ := issue.Field("priority"); value != "medium" {
+		t.Errorf("NewIssue() priority = %v, want medium", value)
+	}
+}
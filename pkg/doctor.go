@@ -0,0 +1,266 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ProblemKind identifies a class of problem found by Doctor
+type ProblemKind string
+
+const (
+	ProblemInvalidConfig ProblemKind = "invalid-config"
+	ProblemUnparsable    ProblemKind = "unparsable"
+	ProblemMissingTitle  ProblemKind = "missing-title"
+	ProblemBadFilename   ProblemKind = "bad-filename"
+	ProblemIDMismatch    ProblemKind = "id-mismatch"
+	ProblemDuplicateID   ProblemKind = "duplicate-id"
+	ProblemCounterBehind ProblemKind = "counter-behind"
+	// ProblemCorruptCounter is a counter file that is missing or isn't a number
+	ProblemCorruptCounter ProblemKind = "corrupt-counter"
+	ProblemStrayFile      ProblemKind = "stray-file"
+	ProblemStaleSlug      ProblemKind = "stale-slug"
+	ProblemInvalidField   ProblemKind = "invalid-field"
+)
+
+// Problem is an inconsistency in the issue store
+type Problem struct {
+	Kind    ProblemKind
+	Name    string // store-relative file the problem was found in
+	Message string
+	// Fixable problems have a safe automatic repair (see DoctorOptions.Fix)
+	Fixable bool
+	Fixed   bool
+}
+
+// DoctorOptions controls Doctor
+type DoctorOptions struct {
+	// Fix applies the safe repairs: rewriting id fields to match filenames,
+	// renaming files whose slug no longer matches the title, raising the
+	// counter above the highest ID (or recreating a missing one) and removing
+	// leftover temporary files
+	Fix bool
+}
+
+// Doctor checks the whole issue store for files ListIssues would skip or
+// misreport: unparsable files, missing titles, filename/id mismatches,
+// duplicate IDs, a missing, corrupt or behind counter, stray files and stale
+// slugs. Under the hash ID strategy, all-digit hash IDs aren't taken for
+// counter values.
+func (r *Repository) Doctor(opts DoctorOptions) ([]Problem, error) {
+	var problems []Problem
+	err := r.withLock(func() error {
+		var err error
+		problems, err = r.doctor(opts)
+		return err
+	})
+	return problems, err
+}
+
+// doctor implements Doctor; the caller must hold the store lock
+func (r *Repository) doctor(opts DoctorOptions) ([]Problem, error) {
+	if !r.Exists() {
+		return nil, fmt.Errorf("%s not found", r.Path())
+	}
+
+	var found []*Problem
+	report := func(p Problem) *Problem {
+		found = append(found, &p)
+		return &p
+	}
+	problems := func() []Problem {
+		list := make([]Problem, len(found))
+		for i, p := range found {
+			list[i] = *p
+		}
+		return list
+	}
+
+	cfg, err := r.LoadConfig()
+	if err != nil {
+		report(Problem{Kind: ProblemInvalidConfig, Name: ConfigFile, Message: err.Error()})
+		cfg = nil
+	}
+
+	byID := make(map[string][]string)
+	maxID := 0
+
+	for _, dir := range []string{OpenDir, ClosedDir} {
+		names, err := r.store.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, base := range names {
+			name := path.Join(dir, base)
+
+			if base == ".keep" {
+				continue
+			}
+			if !strings.HasSuffix(base, ".md") {
+				p := report(Problem{Kind: ProblemStrayFile, Name: name, Message: "not an issue file (issues must end in .md)"})
+				// Interrupted atomic writes leave .NAME.tmp-* files behind
+				if strings.HasPrefix(base, ".") && strings.Contains(base, ".tmp-") {
+					p.Message = "leftover temporary file from an interrupted write"
+					p.Fixable = true
+					if opts.Fix {
+						if err := r.store.Remove(name); err != nil {
+							return problems(), fmt.Errorf("failed to remove %s: %w", name, err)
+						}
+						p.Fixed = true
+					}
+				}
+				continue
+			}
+
+			id, ok := IssueIDFromFilename(base)
+			if !ok {
+				report(Problem{Kind: ProblemBadFilename, Name: name, Message: "filename doesn't follow the ID-slug.md pattern"})
+				continue
+			}
+			byID[id] = append(byID[id], name)
+			if n, err := strconv.Atoi(id); err == nil && n > maxID && (cfg == nil || !cfg.IDs.isHashID(id)) {
+				maxID = n
+			}
+
+			data, err := r.store.ReadFile(name)
+			if err != nil {
+				report(Problem{Kind: ProblemUnparsable, Name: name, Message: err.Error()})
+				continue
+			}
+			issue, err := ParseMarkdown(string(data))
+			if errors.Is(err, ErrMissingTitle) {
+				report(Problem{Kind: ProblemMissingTitle, Name: name, Message: "no # title heading; the issue is hidden from gi list"})
+				continue
+			}
+			if err != nil {
+				report(Problem{Kind: ProblemUnparsable, Name: name, Message: err.Error() + "; the issue is hidden from gi list"})
+				continue
+			}
+
+			if cfg != nil {
+				if err := cfg.ValidateIssue(issue); err != nil {
+					report(Problem{Kind: ProblemInvalidField, Name: name, Message: err.Error()})
+				}
+			}
+
+			if err := r.checkIssueFile(name, id, issue, opts, report); err != nil {
+				return problems(), err
+			}
+		}
+	}
+
+	for _, id := range sortedIDs(byID) {
+		names := byID[id]
+		if len(names) > 1 {
+			report(Problem{
+				Kind:    ProblemDuplicateID,
+				Name:    names[0],
+				Message: fmt.Sprintf("ID %s is used by %d files (%s); run gi renumber", id, len(names), strings.Join(names, ", ")),
+			})
+		}
+	}
+
+	if counter, err := r.readCounter(); errors.Is(err, fs.ErrNotExist) {
+		p := report(Problem{Kind: ProblemCorruptCounter, Name: CounterFile, Message: "counter file is missing", Fixable: true})
+		if opts.Fix {
+			if err := r.writeCounter(maxID + 1); err != nil {
+				return problems(), err
+			}
+			p.Fixed = true
+		}
+	} else if err != nil {
+		// The counter may be ahead of the highest ID (issues get deleted), so
+		// only a human can tell what it should be
+		report(Problem{
+			Kind:    ProblemCorruptCounter,
+			Name:    CounterFile,
+			Message: fmt.Sprintf("%v; set it to the next ID to hand out (at least %d)", err, maxID+1),
+		})
+	} else if counter <= maxID {
+		p := report(Problem{
+			Kind:    ProblemCounterBehind,
+			Name:    CounterFile,
+			Message: fmt.Sprintf("counter is %d but the highest ID is %s", counter, FormatID(maxID)),
+			Fixable: true,
+		})
+		if opts.Fix {
+			if err := r.writeCounter(maxID + 1); err != nil {
+				return problems(), err
+			}
+			p.Fixed = true
+		}
+	}
+
+	return problems(), nil
+}
+
+// checkIssueFile reports (and with Fix, repairs) an id field that disagrees
+// with the filename and a slug that no longer matches the title
+func (r *Repository) checkIssueFile(name, id string, issue *Issue, opts DoctorOptions, report func(Problem) *Problem) error {
+	if issue.ID != id {
+		p := report(Problem{
+			Kind:    ProblemIDMismatch,
+			Name:    name,
+			Message: fmt.Sprintf("id field is %q but the filename says %s", issue.ID, id),
+			Fixable: true,
+		})
+		if opts.Fix {
+			issue.ID = id
+			content, err := SerializeIssue(issue)
+			if err != nil {
+				return fmt.Errorf("failed to serialize issue: %w", err)
+			}
+			if err := r.store.WriteFile(name, []byte(content)); err != nil {
+				return fmt.Errorf("failed to write issue file: %w", err)
+			}
+			p.Fixed = true
+		}
+	}
+
+	slug := GenerateSlug(issue.Title)
+	wantName := path.Join(path.Dir(name), fmt.Sprintf("%s-%s.md", id, slug))
+	if wantName != name {
+		p := report(Problem{
+			Kind:    ProblemStaleSlug,
+			Name:    name,
+			Message: fmt.Sprintf("filename doesn't match the title (expected %s)", path.Base(wantName)),
+			Fixable: !r.store.Exists(wantName),
+		})
+		if opts.Fix && p.Fixable {
+			if err := r.store.Rename(name, wantName); err != nil {
+				return fmt.Errorf("failed to rename %s: %w", name, err)
+			}
+			p.Fixed = true
+		}
+	}
+
+	return nil
+}
+
+// writeCounter stores the next ID to hand out
+func (r *Repository) writeCounter(next int) error {
+	if err := r.store.WriteFile(CounterFile, []byte(fmt.Sprintf("%d\n", next))); err != nil {
+		return fmt.Errorf("failed to write counter: %w", err)
+	}
+	return nil
+}
+
+func sortedIDs(byID map[string][]string) []string {
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// Doctor checks the resolved .issues directory for problems
+func Doctor(opts DoctorOptions) ([]Problem, error) {
+	return DefaultRepository().Doctor(opts)
+}
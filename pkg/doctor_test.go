@@ -0,0 +1,180 @@
+package pkg
+
+import (
+	"testing"
+)
+
+func problemKinds(problems []Problem) map[ProblemKind]*Problem {
+	kinds := make(map[ProblemKind]*Problem)
+	for i := range problems {
+		kinds[problems[i].Kind] = &problems[i]
+	}
+	return kinds
+}
+
+func TestDoctorHealthyRepository(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	createWithID(t, repo, "Healthy issue", "")
+
+	problems, err := repo.Doctor(DoctorOptions{})
+	if err != nil {
+		t.Fatalf("Doctor() error = %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("Doctor() = %+v, want no problems", problems)
+	}
+}
+
+func newBrokenRepo(t *testing.T) *Repository {
+	t.Helper()
+
+	repo := newIDTestRepo(t, "")
+	store := repo.Store()
+	writeIssueFile(t, repo, "open/001-login.md", "001", "2026-09-01T10:00:00Z", "Login", "")
+	writeIssueFile(t, repo, "closed/001-export.md", "001", "2026-09-02T10:00:00Z", "Export", "")
+	writeIssueFile(t, repo, "open/004-old-title.md", "005", "2026-09-02T10:00:00Z", "New title", "")
+	for name, content := range map[string]string{
+		"open/002-broken.md":       "---\nid: [unclosed\n---\n\n# Broken\n",
+		"open/003-untitled.md":     "---\nid: \"003\"\n---\n\nNo heading here\n",
+		"open/notes.txt":           "scratch",
+		"open/.006-x.md.tmp-12345": "partial",
+		"closed/README.md":         "---\n---\n# Readme\n",
+		CounterFile:                "2\n",
+	} {
+		if err := store.WriteFile(name, []byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+func TestDoctorReportsProblems(t *testing.T) {
+	repo := newBrokenRepo(t)
+
+	problems, err := repo.Doctor(DoctorOptions{})
+	if err != nil {
+		t.Fatalf("Doctor() error = %v", err)
+	}
+
+	kinds := problemKinds(problems)
+	want := map[ProblemKind]string{
+		ProblemDuplicateID:   "open/001-login.md",
+		ProblemUnparsable:    "open/002-broken.md",
+		ProblemMissingTitle:  "open/003-untitled.md",
+		ProblemIDMismatch:    "open/004-old-title.md",
+		ProblemStaleSlug:     "open/004-old-title.md",
+		ProblemCounterBehind: CounterFile,
+		ProblemBadFilename:   "closed/README.md",
+	}
+	for kind, name := range want {
+		p := kinds[kind]
+		if p == nil {
+			t.Errorf("Doctor() didn't report %s", kind)
+			continue
+		}
+		if p.Name != name {
+			t.Errorf("%s reported for %s, want %s", kind, p.Name, name)
+		}
+		if p.Fixed {
+			t.Errorf("%s should not be fixed without Fix", kind)
+		}
+	}
+
+	var stray int
+	for _, p := range problems {
+		if p.Kind == ProblemStrayFile {
+			stray++
+		}
+	}
+	if stray != 2 {
+		t.Errorf("Doctor() reported %d stray files, want 2", stray)
+	}
+}
+
+func TestDoctorFix(t *testing.T) {
+	repo := newBrokenRepo(t)
+
+	if _, err := repo.Doctor(DoctorOptions{Fix: true}); err != nil {
+		t.Fatalf("Doctor(fix) error = %v", err)
+	}
+
+	issue, _, err := repo.LoadIssue("004")
+	if err != nil || issue.ID != "004" || issue.Title != "New title" {
+		t.Fatalf("LoadIssue(004) = %+v, %v", issue, err)
+	}
+	if !repo.Store().Exists("open/004-new-title.md") {
+		t.Error("stale slug should be renamed")
+	}
+	if repo.Store().Exists("open/.006-x.md.tmp-12345") {
+		t.Error("leftover temporary file should be removed")
+	}
+	if counter, err := repo.readCounter(); err != nil || counter != 5 {
+		t.Errorf("counter = %d, %v; want 5", counter, err)
+	}
+
+	// Only the problems that need a human are left
+	problems, err := repo.Doctor(DoctorOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range problems {
+		if p.Fixable {
+			t.Errorf("fixable problem left after --fix: %+v", p)
+		}
+	}
+	if kinds := problemKinds(problems); kinds[ProblemDuplicateID] == nil || kinds[ProblemUnparsable] == nil {
+		t.Errorf("unfixable problems should still be reported, got %+v", problems)
+	}
+}
+
+func TestDoctorCounterProblems(t *testing.T) {
+	// A counter that isn't a number is reported, but not overwritten
+	repo := newIDTestRepo(t, "")
+	createWithID(t, repo, "First", "")
+	conflicted := "<<<<<<< HEAD\n12\n=======\n14\n>>>>>>> feature\n"
+	if err := repo.Store().WriteFile(CounterFile, []byte(conflicted)); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := repo.Doctor(DoctorOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("Doctor() error = %v", err)
+	}
+	kinds := problemKinds(problems)
+	if p := kinds[ProblemCorruptCounter]; p == nil || p.Fixable || p.Fixed {
+		t.Errorf("corrupt counter reported as %+v", p)
+	}
+	if kinds[ProblemCounterBehind] != nil {
+		t.Error("a corrupt counter isn't a counter behind the highest ID")
+	}
+	if data, _ := repo.Store().ReadFile(CounterFile); string(data) != conflicted {
+		t.Errorf("--fix overwrote a corrupt counter with %q", data)
+	}
+
+	// A missing counter can safely be recreated
+	if err := repo.Store().Remove(CounterFile); err != nil {
+		t.Fatal(err)
+	}
+	problems, err = repo.Doctor(DoctorOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("Doctor() error = %v", err)
+	}
+	if p := problemKinds(problems)[ProblemCorruptCounter]; p == nil || !p.Fixed {
+		t.Errorf("missing counter reported as %+v", p)
+	}
+	if counter, err := repo.readCounter(); err != nil || counter != 2 {
+		t.Errorf("counter = %d, %v; want 2", counter, err)
+	}
+}
+
+func TestDoctorIgnoresNumericHashIDs(t *testing.T) {
+	repo := newIDTestRepo(t, "ids:\n  strategy: hash\n")
+	writeIssueFile(t, repo, "open/1234567-digits-only.md", "1234567", "2026-09-01T10:00:00Z", "Digits only", "")
+
+	problems, err := repo.Doctor(DoctorOptions{})
+	if err != nil {
+		t.Fatalf("Doctor() error = %v", err)
+	}
+	if p := problemKinds(problems)[ProblemCounterBehind]; p != nil {
+		t.Errorf("an all-digit hash ID was taken for a counter value: %+v", p)
+	}
+}
@@ -0,0 +1,277 @@
+package pkg
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// issueSource remembers the exact text an Issue was parsed from so that
+// SerializeIssue can write it back without losing unknown frontmatter keys,
+// key order, comments or body formatting.
+type issueSource struct {
+	prefix      string     // text before the opening ---
+	frontmatter string     // raw text between the --- delimiters
+	mapping     *yaml.Node // parsed frontmatter mapping (nil if not a mapping)
+	tail        string     // raw text after the closing ---
+	known       Issue      // snapshot of the known fields as parsed
+	extra       map[string]interface{}
+}
+
+// frontmatterField describes an Issue field stored in the YAML frontmatter
+type frontmatterField struct {
+	key       string
+	index     int
+	omitEmpty bool
+}
+
+// issueFields lists the Issue fields that live in the frontmatter, in struct order
+var issueFields = func() []frontmatterField {
+	var fields []frontmatterField
+	t := reflect.TypeOf(Issue{})
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("yaml")
+		if tag == "" || tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		fields = append(fields, frontmatterField{
+			key:       name,
+			index:     i,
+			omitEmpty: strings.Contains(opts, "omitempty"),
+		})
+	}
+	return fields
+}()
+
+// isKnownField reports whether key maps to a field of the Issue struct
+func isKnownField(key string) bool {
+	for _, f := range issueFields {
+		if f.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// snapshotIssue copies an issue, cloning slice fields so later in-place edits are detected
+func snapshotIssue(issue *Issue) Issue {
+	snapshot := *issue
+	v := reflect.ValueOf(&snapshot).Elem()
+	for _, f := range issueFields {
+		field := v.Field(f.index)
+		if field.Kind() == reflect.Slice && !field.IsNil() {
+			clone := reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+			reflect.Copy(clone, field)
+			field.Set(clone)
+		}
+	}
+	if issue.Comments != nil {
+		snapshot.Comments = append([]Comment(nil), issue.Comments...)
+	}
+	return snapshot
+}
+
+// frontmatterMapping returns the top-level mapping node of a frontmatter document
+func frontmatterMapping(frontmatter string) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(frontmatter), &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+// decodeExtraFields collects the frontmatter keys that aren't Issue fields
+func decodeExtraFields(mapping *yaml.Node) (map[string]interface{}, error) {
+	if mapping == nil {
+		return nil, nil
+	}
+
+	var extra map[string]interface{}
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		key := mapping.Content[i].Value
+		if isKnownField(key) {
+			continue
+		}
+
+		var value interface{}
+		if err := mapping.Content[i+1].Decode(&value); err != nil {
+			return nil, fmt.Errorf("failed to decode field %q: %w", key, err)
+		}
+		if extra == nil {
+			extra = make(map[string]interface{})
+		}
+		extra[key] = value
+	}
+	return extra, nil
+}
+
+// frontmatterChange is a single key to rewrite (or delete when remove is set)
+type frontmatterChange struct {
+	key    string
+	value  interface{}
+	remove bool
+}
+
+// changedFields compares an issue against the snapshot taken when it was parsed
+func (src *issueSource) changedFields(issue *Issue) []frontmatterChange {
+	var changes []frontmatterChange
+
+	current := reflect.ValueOf(issue).Elem()
+	original := reflect.ValueOf(&src.known).Elem()
+	for _, f := range issueFields {
+		value := current.Field(f.index)
+		if reflect.DeepEqual(value.Interface(), original.Field(f.index).Interface()) {
+			continue
+		}
+		changes = append(changes, frontmatterChange{
+			key:    f.key,
+			value:  value.Interface(),
+			remove: f.omitEmpty && isEmptyValue(value),
+		})
+	}
+
+	for _, key := range sortedKeys(issue.Extra) {
+		if old, ok := src.extra[key]; ok && reflect.DeepEqual(old, issue.Extra[key]) {
+			continue
+		}
+		changes = append(changes, frontmatterChange{key: key, value: issue.Extra[key]})
+	}
+	for _, key := range sortedKeys(src.extra) {
+		if _, ok := issue.Extra[key]; !ok {
+			changes = append(changes, frontmatterChange{key: key, remove: true})
+		}
+	}
+
+	return changes
+}
+
+// render writes the frontmatter back, touching only the lines of changed keys
+func (src *issueSource) render(issue *Issue) (string, error) {
+	changes := src.changedFields(issue)
+	if len(changes) == 0 {
+		return src.frontmatter, nil
+	}
+
+	lines := strings.Split(src.frontmatter, "\n")
+
+	// Work out which lines belong to each top-level key
+	type keySpan struct{ start, end int }
+	spans := make(map[string]keySpan)
+	insertAt := len(lines)
+	for i := 0; i+1 < len(src.mapping.Content); i += 2 {
+		start := src.mapping.Content[i].Line - 1
+		end := len(lines)
+		if i+2 < len(src.mapping.Content) {
+			end = src.mapping.Content[i+2].Line - 1
+		}
+		// Blank lines and column-0 comments before the next key belong to that key
+		for end > start+1 && isDetachedLine(lines[end-1]) {
+			end--
+		}
+		spans[src.mapping.Content[i].Value] = keySpan{start, end}
+		insertAt = end
+	}
+	if len(spans) == 0 {
+		// Empty frontmatter: insert before the trailing newline
+		insertAt = len(lines) - 1
+		if insertAt < 0 {
+			insertAt = 0
+		}
+	}
+
+	replacements := make(map[int][]string) // start line -> new lines
+	removed := make(map[int]bool)
+	var appended []string
+	for _, change := range changes {
+		var encoded []string
+		if !change.remove {
+			text, err := marshalField(change.key, change.value)
+			if err != nil {
+				return "", err
+			}
+			encoded = strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+		}
+
+		span, exists := spans[change.key]
+		switch {
+		case exists:
+			for line := span.start; line < span.end; line++ {
+				removed[line] = true
+			}
+			replacements[span.start] = encoded
+		case !change.remove:
+			appended = append(appended, encoded...)
+		}
+	}
+
+	var out []string
+	for i, line := range lines {
+		if i == insertAt {
+			out = append(out, appended...)
+		}
+		if repl, ok := replacements[i]; ok {
+			out = append(out, repl...)
+		}
+		if !removed[i] {
+			out = append(out, line)
+		}
+	}
+	if insertAt >= len(lines) {
+		out = append(out, appended...)
+	}
+
+	return strings.Join(out, "\n"), nil
+}
+
+// marshalField renders a single "key: value" frontmatter entry
+func marshalField(key string, value interface{}) (string, error) {
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return "", fmt.Errorf("failed to marshal field %q: %w", key, err)
+	}
+
+	mapping := &yaml.Node{
+		Kind: yaml.MappingNode,
+		Content: []*yaml.Node{
+			{Kind: yaml.ScalarNode, Tag: "!!str", Value: key},
+			&valueNode,
+		},
+	}
+
+	data, err := yaml.Marshal(mapping)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal field %q: %w", key, err)
+	}
+	return string(data), nil
+}
+
+// isDetachedLine reports whether a line is blank or a top-level comment
+func isDetachedLine(line string) bool {
+	return strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#")
+}
+
+// isEmptyValue mirrors yaml's omitempty rules for the field kinds Issue uses
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
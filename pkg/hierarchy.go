@@ -0,0 +1,174 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Child is an issue whose parent field names another issue
+type Child struct {
+	Issue *Issue
+	Dir   string
+}
+
+// Progress counts the closed children of an issue
+type Progress struct {
+	Closed int
+	Total  int
+}
+
+// String renders the progress as "3/7 children closed"
+func (p Progress) String() string {
+	return fmt.Sprintf("%d/%d children closed", p.Closed, p.Total)
+}
+
+// Children returns the issues whose parent is issue id, ordered by ID
+func (r *Repository) Children(id string) ([]Child, error) {
+	name, _, err := r.resolveIssue(id)
+	if err != nil {
+		return nil, err
+	}
+	id, _ = IssueIDFromFilename(path.Base(name))
+
+	files, err := r.issueFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	var children []Child
+	for _, f := range files {
+		if f.issue.Parent == id && f.id != id {
+			children = append(children, Child{Issue: f.issue, Dir: f.dir})
+		}
+	}
+	sort.SliceStable(children, func(i, j int) bool {
+		return children[i].Issue.ID < children[j].Issue.ID
+	})
+
+	return children, nil
+}
+
+// ChildrenProgress returns the progress of every issue that has children, keyed by ID
+func (r *Repository) ChildrenProgress() (map[string]Progress, error) {
+	files, err := r.issueFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	progress := make(map[string]Progress)
+	for _, f := range files {
+		if f.issue.Parent == "" {
+			continue
+		}
+		p := progress[f.issue.Parent]
+		p.Total++
+		if f.dir == ClosedDir {
+			p.Closed++
+		}
+		progress[f.issue.Parent] = p
+	}
+	return progress, nil
+}
+
+// ProgressOf computes the progress of a list of children
+func ProgressOf(children []Child) Progress {
+	p := Progress{Total: len(children)}
+	for _, child := range children {
+		if child.Dir == ClosedDir {
+			p.Closed++
+		}
+	}
+	return p
+}
+
+// SetParent makes issue id a child of parent, or removes its parent when
+// parent is "". The parent must exist and can't be the issue or one of its
+// descendants.
+func (r *Repository) SetParent(id, parent string) error {
+	return r.withLock(func() error {
+		return r.setParent(id, parent)
+	})
+}
+
+// setParent implements SetParent; the caller must hold the store lock
+func (r *Repository) setParent(id, parent string) error {
+	issue, dir, err := r.LoadIssue(id)
+	if err != nil {
+		return err
+	}
+
+	parent, err = r.checkParent(issue, parent)
+	if err != nil {
+		return err
+	}
+
+	if issue.Parent == parent {
+		return nil
+	}
+	issue.Parent = parent
+	issue.Updated = time.Now()
+
+	return r.saveIssue(issue, dir)
+}
+
+// checkParent checks parent can become the parent of issue and returns its
+// full ID ("" stays "")
+func (r *Repository) checkParent(issue *Issue, parent string) (string, error) {
+	if parent == "" {
+		return "", nil
+	}
+
+	parentIssue, _, err := r.LoadIssue(parent)
+	if err != nil {
+		return "", fmt.Errorf("parent: %w", err)
+	}
+	parent = parentIssue.ID
+
+	files, err := r.issueFiles()
+	if err != nil {
+		return "", err
+	}
+	if chain := ancestorChain(files, parent, issue.ID); chain != nil {
+		return "", fmt.Errorf("can't set parent: %s would be its own ancestor (%s)",
+			issue.ID, strings.Join(append([]string{issue.ID}, chain...), " → "))
+	}
+	return parent, nil
+}
+
+// ancestorChain follows parent fields up from id and returns the chain of IDs
+// up to and including target, or nil if target isn't an ancestor of id (or id itself)
+func ancestorChain(files []*issueFile, id, target string) []string {
+	parents := make(map[string]string, len(files))
+	for _, f := range files {
+		parents[f.id] = f.issue.Parent
+	}
+
+	var chain []string
+	seen := make(map[string]bool)
+	for current := id; current != "" && !seen[current]; current = parents[current] {
+		seen[current] = true
+		chain = append(chain, current)
+		if current == target {
+			return chain
+		}
+	}
+	return nil
+}
+
+// Children returns the child issues of an issue in the resolved .issues directory
+func Children(id string) ([]Child, error) {
+	return DefaultRepository().Children(id)
+}
+
+// ChildrenProgress returns the progress of every parent issue in the resolved .issues directory
+func ChildrenProgress() (map[string]Progress, error) {
+	return DefaultRepository().ChildrenProgress()
+}
+
+// SetParent sets the parent of an issue in the resolved .issues directory
+func SetParent(id, parent string) error {
+	return DefaultRepository().SetParent(id, parent)
+}
@@ -0,0 +1,80 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChildrenAndProgress(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, title := range []string{"Epic", "Cart", "Payment", "Stripe"} {
+		createWithID(t, repo, title, "")
+	}
+	for _, pair := range [][2]string{{"002", "001"}, {"003", "001"}, {"004", "003"}} {
+		if err := repo.SetParent(pair[0], pair[1]); err != nil {
+			t.Fatalf("SetParent(%s, %s) error = %v", pair[0], pair[1], err)
+		}
+	}
+	if err := repo.MoveIssue("002", OpenDir, ClosedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	children, err := repo.Children("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 2 || children[0].Issue.ID != "002" || children[0].Dir != ClosedDir || children[1].Issue.ID != "003" {
+		t.Fatalf("Children(001) = %+v", children)
+	}
+	if got := ProgressOf(children).String(); got != "1/2 children closed" {
+		t.Errorf("ProgressOf() = %q", got)
+	}
+
+	progress, err := repo.ChildrenProgress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if progress["001"] != (Progress{Closed: 1, Total: 2}) || progress["003"] != (Progress{Total: 1}) || len(progress) != 2 {
+		t.Errorf("ChildrenProgress() = %+v", progress)
+	}
+}
+
+func TestSetParentValidation(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, title := range []string{"A", "B", "C"} {
+		createWithID(t, repo, title, "")
+	}
+	if err := repo.SetParent("002", "001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SetParent("003", "002"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.SetParent("001", "404"); err == nil {
+		t.Error("SetParent() to a missing issue should fail")
+	}
+	if err := repo.SetParent("001", "001"); err == nil {
+		t.Error("SetParent() to itself should fail")
+	}
+	err := repo.SetParent("001", "003")
+	if err == nil || !strings.Contains(err.Error(), "001 → 003 → 002 → 001") {
+		t.Errorf("SetParent() cycle error = %v", err)
+	}
+
+	if err := repo.SetParent("003", ""); err != nil {
+		t.Fatalf("SetParent(\"\") error = %v", err)
+	}
+	issue, _, _ := repo.LoadIssue("003")
+	if issue.Parent != "" {
+		t.Errorf("Parent = %q after clearing", issue.Parent)
+	}
+	name, _, _ := repo.findIssue("003")
+	data, err := repo.Store().ReadFile(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "parent:") {
+		t.Errorf("cleared parent left in frontmatter:\n%s", data)
+	}
+}
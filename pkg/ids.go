@@ -0,0 +1,246 @@
+package pkg
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// IDStrategy selects how new issue IDs are allocated
+type IDStrategy string
+
+const (
+	// IDCounter allocates sequential IDs (001, 002, ...) from the .counter file
+	IDCounter IDStrategy = "counter"
+	// IDHash allocates short hex IDs derived from the title, time and random bytes
+	IDHash IDStrategy = "hash"
+	// IDULID allocates lowercase ULIDs, which sort by creation time
+	IDULID IDStrategy = "ulid"
+	// IDProvisional allocates counter IDs on mainline branches and branch-scoped
+	// provisional IDs (login_fix.1) elsewhere, finalized by `gi renumber` after merge
+	IDProvisional IDStrategy = "provisional"
+)
+
+// Defaults for the ids section of config.yaml
+const (
+	DefaultHashLength = 7
+	minHashLength     = 4
+)
+
+// DefaultMainlineBranches are the branches where provisional IDs aren't used
+var DefaultMainlineBranches = []string{"main", "master"}
+
+// IDConfig configures issue ID allocation
+type IDConfig struct {
+	Strategy IDStrategy `yaml:"strategy,omitempty"`
+	// Length is the number of hex digits in hash IDs
+	Length int `yaml:"length,omitempty"`
+	// Mainline lists the branches that get final IDs under the provisional strategy
+	Mainline []string `yaml:"mainline,omitempty"`
+}
+
+// Validate checks the ID strategy and its options
+func (c *IDConfig) Validate() error {
+	switch c.Strategy {
+	case "", IDCounter, IDHash, IDULID, IDProvisional:
+	default:
+		return fmt.Errorf("unknown ID strategy %q (must be one of: counter, hash, ulid, provisional)", c.Strategy)
+	}
+	if c.Length != 0 && (c.Length < minHashLength || c.Length > sha256.Size*2) {
+		return fmt.Errorf("ID length must be between %d and %d", minHashLength, sha256.Size*2)
+	}
+	return nil
+}
+
+// isHashID reports whether id looks like an ID allocated by the hash
+// strategy. Hash IDs can be all digits, but they aren't counter values.
+func (c *IDConfig) isHashID(id string) bool {
+	length := c.Length
+	if length == 0 {
+		length = DefaultHashLength
+	}
+	return c.Strategy == IDHash && len(id) == length
+}
+
+// isMainline reports whether branch gets final IDs under the provisional strategy.
+// An unknown branch (empty, or HEAD when detached) counts as mainline.
+func (c *IDConfig) isMainline(branch string) bool {
+	if branch == "" || branch == "HEAD" {
+		return true
+	}
+	mainline := c.Mainline
+	if len(mainline) == 0 {
+		mainline = DefaultMainlineBranches
+	}
+	return containsString(mainline, branch)
+}
+
+// IsProvisionalID reports whether id is a branch-scoped provisional ID
+func IsProvisionalID(id string) bool {
+	return strings.Contains(id, ".")
+}
+
+// IssueIDFromFilename extracts the ID from an issue filename (NNN-slug.md)
+func IssueIDFromFilename(name string) (string, bool) {
+	name = path.Base(name)
+	if !strings.HasSuffix(name, ".md") {
+		return "", false
+	}
+	id, _, ok := strings.Cut(name, "-")
+	if !ok || id == "" {
+		return "", false
+	}
+	return id, true
+}
+
+// AllocateID returns a new issue ID using the strategy configured in
+// config.yaml. branch is the current git branch, used by the provisional
+// strategy; pass "" when it isn't known.
+func (r *Repository) AllocateID(title, branch string) (string, error) {
+	var id string
+	err := r.withLock(func() error {
+		var err error
+		id, err = r.allocateID(title, branch)
+		return err
+	})
+	return id, err
+}
+
+// allocateID implements AllocateID; the caller must hold the store lock
+func (r *Repository) allocateID(title, branch string) (string, error) {
+	cfg, err := r.LoadConfig()
+	if err != nil {
+		return "", err
+	}
+	ids := cfg.IDs
+
+	switch ids.Strategy {
+	case IDHash:
+		length := ids.Length
+		if length == 0 {
+			length = DefaultHashLength
+		}
+		return r.unusedID(func() (string, error) {
+			return hashID(title, length)
+		})
+	case IDULID:
+		return r.unusedID(func() (string, error) {
+			return newULID(time.Now())
+		})
+	case IDProvisional:
+		if !ids.isMainline(branch) {
+			return r.provisionalID(branch)
+		}
+	}
+
+	n, err := r.nextID()
+	if err != nil {
+		return "", err
+	}
+	return FormatID(n), nil
+}
+
+// unusedID calls generate until it returns an ID no existing issue uses
+func (r *Repository) unusedID(generate func() (string, error)) (string, error) {
+	for attempt := 0; attempt < 10; attempt++ {
+		id, err := generate()
+		if err != nil {
+			return "", err
+		}
+		if _, _, err := r.findIssue(id); err != nil {
+			return id, nil
+		}
+	}
+	return "", fmt.Errorf("failed to allocate an unused issue ID")
+}
+
+var branchScopeRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// provisionalID returns the next provisional ID for branch (feature/login-fix -> feature_login_fix.1)
+func (r *Repository) provisionalID(branch string) (string, error) {
+	scope := strings.Trim(branchScopeRe.ReplaceAllString(strings.ToLower(branch), "_"), "_")
+	if scope == "" {
+		scope = "branch"
+	}
+
+	ids, err := r.issueIDs()
+	if err != nil {
+		return "", err
+	}
+
+	next := 1
+	prefix := scope + "."
+	for _, id := range ids {
+		if n, err := strconv.Atoi(strings.TrimPrefix(id, prefix)); err == nil && strings.HasPrefix(id, prefix) && n >= next {
+			next = n + 1
+		}
+	}
+	return fmt.Sprintf("%s%d", prefix, next), nil
+}
+
+// issueIDs lists the IDs of every issue file in open/ and closed/, sorted
+func (r *Repository) issueIDs() ([]string, error) {
+	var ids []string
+	for _, dir := range []string{OpenDir, ClosedDir} {
+		names, err := r.store.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			if id, ok := IssueIDFromFilename(name); ok {
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// hashID derives a short hex ID from the title, the current time and random bytes
+func hashID(title string, length int) (string, error) {
+	var nonce [8]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return "", fmt.Errorf("failed to generate issue ID: %w", err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte(title))
+	_ = binary.Write(h, binary.BigEndian, time.Now().UnixNano())
+	h.Write(nonce[:])
+	return hex.EncodeToString(h.Sum(nil))[:length], nil
+}
+
+// crockford is the ULID alphabet, lowercased so IDs match slugged filenames
+const crockford = "0123456789abcdefghjkmnpqrstvwxyz"
+
+// newULID returns a 26-character ULID: 48 bits of milliseconds, then 80 random bits
+func newULID(t time.Time) (string, error) {
+	var data [16]byte
+	ms := uint64(t.UnixMilli())
+	for i := 5; i >= 0; i-- {
+		data[i] = byte(ms)
+		ms >>= 8
+	}
+	if _, err := rand.Read(data[6:]); err != nil {
+		return "", fmt.Errorf("failed to generate issue ID: %w", err)
+	}
+
+	// 128 bits encode as 26 base32 digits, the first holding only 3 bits
+	hi := binary.BigEndian.Uint64(data[:8])
+	lo := binary.BigEndian.Uint64(data[8:])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out), nil
+}
@@ -0,0 +1,226 @@
+package pkg
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newIDTestRepo(t *testing.T, config string) *Repository {
+	t.Helper()
+
+	repo := NewRepository(NewMemStore())
+	if err := repo.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if config != "" {
+		if err := repo.Store().WriteFile(ConfigFile, []byte(config)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repo
+}
+
+// createWithID allocates an ID the way `gi create` does and saves the issue
+func createWithID(t *testing.T, repo *Repository, title, branch string) *Issue {
+	t.Helper()
+
+	id, err := repo.AllocateID(title, branch)
+	if err != nil {
+		t.Fatalf("AllocateID() error = %v", err)
+	}
+	issue := repo.NewIssueWithID(id, title, "", nil)
+	if err := repo.SaveIssue(issue, OpenDir); err != nil {
+		t.Fatalf("SaveIssue() error = %v", err)
+	}
+	return issue
+}
+
+func TestAllocateIDStrategies(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		branch string
+		want   *regexp.Regexp
+	}{
+		{"default counter", "", "feature/x", regexp.MustCompile(`^001$`)},
+		{"hash", "ids:\n  strategy: hash\n", "", regexp.MustCompile(`^[0-9a-f]{7}$`)},
+		{"hash length", "ids:\n  strategy: hash\n  length: 10\n", "", regexp.MustCompile(`^[0-9a-f]{10}$`)},
+		{"ulid", "ids:\n  strategy: ulid\n", "", regexp.MustCompile(`^[0-9a-hjkmnp-tv-z]{26}$`)},
+		{"provisional on branch", "ids:\n  strategy: provisional\n", "feature/Login-fix", regexp.MustCompile(`^feature_login_fix\.1$`)},
+		{"provisional on main", "ids:\n  strategy: provisional\n", "main", regexp.MustCompile(`^001$`)},
+		{"provisional custom mainline", "ids:\n  strategy: provisional\n  mainline: [trunk]\n", "main", regexp.MustCompile(`^main\.1$`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newIDTestRepo(t, tt.config)
+			issue := createWithID(t, repo, "Some title", tt.branch)
+			if !tt.want.MatchString(issue.ID) {
+				t.Errorf("AllocateID() = %q, want match for %s", issue.ID, tt.want)
+			}
+			if _, _, err := repo.FindIssueFile(issue.ID); err != nil {
+				t.Errorf("FindIssueFile(%q) error = %v", issue.ID, err)
+			}
+		})
+	}
+}
+
+func TestAllocateIDProvisionalIncrements(t *testing.T) {
+	repo := newIDTestRepo(t, "ids:\n  strategy: provisional\n")
+
+	first := createWithID(t, repo, "First", "topic")
+	second := createWithID(t, repo, "Second", "topic")
+	if first.ID != "topic.1" || second.ID != "topic.2" {
+		t.Errorf("provisional IDs = %s, %s; want topic.1, topic.2", first.ID, second.ID)
+	}
+}
+
+func TestIDConfigValidate(t *testing.T) {
+	for _, config := range []string{"ids:\n  strategy: uuid\n", "ids:\n  strategy: hash\n  length: 2\n"} {
+		if _, err := ParseConfig([]byte(config)); err == nil {
+			t.Errorf("ParseConfig(%q) should fail", config)
+		}
+	}
+}
+
+func TestNewULIDSortsByTime(t *testing.T) {
+	earlier, err := newULID(time.UnixMilli(1_700_000_000_000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	later, err := newULID(time.UnixMilli(1_700_000_000_001))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(earlier) != 26 || earlier >= later {
+		t.Errorf("newULID() = %s, %s; want 26 chars sorting by time", earlier, later)
+	}
+}
+
+func TestFindIssueFileResolvesUniquePrefix(t *testing.T) {
+	repo := newIDTestRepo(t, "ids:\n  strategy: hash\n")
+	for _, id := range []string{"a3f9c21", "a3e0011", "b7c4d2e"} {
+		if err := repo.SaveIssue(repo.NewIssueWithID(id, "Issue "+id, "", nil), OpenDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query   string
+		wantID  string
+		wantErr string
+	}{
+		{"a3f9c21", "a3f9c21", ""},
+		{"a3f", "a3f9c21", ""},
+		{"b", "b7c4d2e", ""},
+		{"a3", "", "ambiguous"},
+		{"c", "", "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			issue, _, err := repo.LoadIssue(tt.query)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadIssue(%q) error = %v, want %q", tt.query, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadIssue(%q) error = %v", tt.query, err)
+			}
+			if issue.ID != tt.wantID {
+				t.Errorf("LoadIssue(%q) = %s, want %s", tt.query, issue.ID, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestFindIssueFileCounterIDs(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, id := range []string{"001", "100"} {
+		if err := repo.SaveIssue(repo.NewIssueWithID(id, "Issue "+id, "", nil), OpenDir); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query  string
+		wantID string
+	}{
+		{"001", "001"},
+		{"1", "001"},
+		{"01", "001"},
+		{"100", "100"},
+		{"10", ""}, // not a prefix of 100
+		{"0", ""},
+	}
+	for _, tt := range tests {
+		issue, _, err := repo.LoadIssue(tt.query)
+		if tt.wantID == "" {
+			if !errors.Is(err, ErrNotFound) {
+				t.Errorf("LoadIssue(%q) error = %v, want not found", tt.query, err)
+			}
+			continue
+		}
+		if err != nil || issue.ID != tt.wantID {
+			t.Errorf("LoadIssue(%q) = %v, %v; want %s", tt.query, issue, err, tt.wantID)
+		}
+	}
+}
+
+func TestSaveIssueDoesNotMatchPrefix(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	if err := repo.SaveIssue(repo.NewIssueWithID("a3f9c21", "Long", "", nil), ClosedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new issue whose ID is a prefix of an existing one is a different issue
+	if err := repo.SaveIssue(repo.NewIssueWithID("a3f", "Short", "", nil), OpenDir); err != nil {
+		t.Fatalf("SaveIssue() error = %v", err)
+	}
+	if _, dir, err := repo.LoadIssue("a3f"); err != nil || dir != OpenDir {
+		t.Errorf("LoadIssue(a3f) = %s, %v; want exact match in open", dir, err)
+	}
+}
+
+func TestRenumberFinalizesProvisionalIDs(t *testing.T) {
+	repo := newIDTestRepo(t, "ids:\n  strategy: provisional\n")
+
+	createWithID(t, repo, "On main", "main")
+	first := createWithID(t, repo, "Login form", "login")
+	time.Sleep(time.Millisecond)
+	createWithID(t, repo, "Login errors", "login")
+	if err := repo.MoveIssue(first.ID, OpenDir, ClosedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := repo.Renumber(RenumberOptions{})
+	if err != nil {
+		t.Fatalf("Renumber() error = %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Renumber() = %d changes, want 2", len(changes))
+	}
+	if changes[0].OldID != "login.1" || changes[0].NewID != "002" || changes[0].NewName != "closed/002-login-form.md" {
+		t.Errorf("first change = %+v", changes[0])
+	}
+	if changes[1].OldID != "login.2" || changes[1].NewID != "003" {
+		t.Errorf("second change = %+v", changes[1])
+	}
+
+	issue, dir, err := repo.LoadIssue("002")
+	if err != nil || dir != ClosedDir || issue.ID != "002" || issue.Title != "Login form" {
+		t.Errorf("LoadIssue(002) = %+v, %s, %v", issue, dir, err)
+	}
+	if _, _, err := repo.LoadIssue("login.1"); err == nil {
+		t.Error("provisional file should be gone after renumbering")
+	}
+
+	if changes, err := repo.Renumber(RenumberOptions{}); err != nil || len(changes) != 0 {
+		t.Errorf("second Renumber() = %v, %v; want no changes", changes, err)
+	}
+}
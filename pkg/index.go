@@ -0,0 +1,605 @@
+package pkg
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/gob"
+	"math"
+	"path"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// CacheDir holds derived data such as the search index. It's git-ignored
+	// and can be deleted at any time.
+	CacheDir = ".cache"
+	// SearchIndexFile is the search index inside CacheDir
+	SearchIndexFile = CacheDir + "/search-index.gob"
+
+	// searchIndexVersion is bumped whenever the index format or tokenizer
+	// changes, so stale indexes are rebuilt
+	searchIndexVersion = 1
+
+	// racyGranularity is the coarsest file timestamp resolution allowed
+	// for: a file modified in the same tick as the last index build may
+	// have changed after it was indexed without its mtime showing it, so
+	// it's re-read anyway
+	racyGranularity = 2 * time.Second
+
+	// fieldGap separates the positions of the title, body and comments so
+	// phrases don't match across them
+	fieldGap = 100
+
+	// BM25 parameters
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// searchIndex is an inverted index over the text of every issue file
+type searchIndex struct {
+	Version int
+	// BuiltAt is when the index was last refreshed (UnixNano)
+	BuiltAt int64
+	// NextID numbers the next file indexed
+	NextID int
+	// Docs are the indexed files by store name ("open/001-fix-bug.md")
+	Docs map[string]*indexedDoc
+	// Postings maps a term to where it occurs. For each file containing it
+	// they hold the file's ID, the number of occurrences and the positions
+	// (delta-encoded), all as uvarints. Kept encoded, the index loads
+	// quickly and only the terms a query uses are decoded.
+	Postings map[string][]byte
+
+	names map[int]string // file names by ID
+	terms []string       // sorted keys of Postings, for prefix lookups
+}
+
+// indexedDoc is what the index knows about one issue file
+type indexedDoc struct {
+	ID      int
+	Size    int64
+	ModTime int64 // UnixNano; 0 when the store can't stat files
+	Hash    [sha256.Size]byte
+	Length  int // number of tokens
+}
+
+// SearchHit is an issue found by Search
+type SearchHit struct {
+	Issue  *Issue
+	Dir    string  // OpenDir or ClosedDir
+	Status string  // workflow status
+	Score  float64 // BM25 relevance of the query's words; 0 without words
+}
+
+func newSearchIndex() *searchIndex {
+	return &searchIndex{
+		Version:  searchIndexVersion,
+		Docs:     make(map[string]*indexedDoc),
+		Postings: make(map[string][]byte),
+		names:    make(map[int]string),
+	}
+}
+
+// tokenize lowercases text and splits it into runs of letters and digits
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// Search finds the issues in open/ and closed/ matching a query, ranked by
+// how well they match its words (best first, then by file name). Words match
+// anywhere in a word, as with Query.Match, and "quoted words" match a phrase.
+//
+// The words are looked up in a search index kept in CacheDir, which is
+// brought up to date first: only files whose size, modification time or
+// content changed since the last search are re-read.
+func (r *Repository) Search(q *Query) ([]SearchHit, error) {
+	cfg, err := r.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	idx := r.loadSearchIndex()
+	if r.refreshSearchIndex(idx) {
+		r.saveSearchIndex(idx)
+	}
+
+	// Every doc matching a text term, with how often it occurs
+	occurrences := make(map[*QueryTerm]map[string]int)
+	lookup := func(t *QueryTerm) map[string]int {
+		occ, ok := occurrences[t]
+		if !ok {
+			occ = idx.occurrences(t.Value)
+			occurrences[t] = occ
+		}
+		return occ
+	}
+
+	// Words every match must contain narrow down the files to read
+	var candidates []string
+	required := requiredTextTerms(q.Root)
+	if len(required) > 0 {
+		for name := range lookup(required[0]) {
+			ok := true
+			for _, t := range required[1:] {
+				if lookup(t)[name] == 0 {
+					ok = false
+					break
+				}
+			}
+			if ok {
+				candidates = append(candidates, name)
+			}
+		}
+	} else {
+		for name := range idx.Docs {
+			candidates = append(candidates, name)
+		}
+	}
+	sort.Strings(candidates)
+
+	scored := scoredTextTerms(q.Root, false)
+	avgLength := idx.averageLength()
+
+	var hits []SearchHit
+	for _, name := range candidates {
+		data, err := r.store.ReadFile(name)
+		if err != nil {
+			continue // Removed since the index was refreshed
+		}
+		issue, err := ParseMarkdown(string(data))
+		if err != nil {
+			continue
+		}
+		issue.Path = r.store.Path(name)
+		issue.Version = ContentVersion(data)
+
+		dir := path.Dir(name)
+		env := &queryEnv{
+			issue:  issue,
+			dir:    dir,
+			status: cfg.Workflow.StatusOf(issue, dir),
+			now:    q.Now,
+			text:   func(t *QueryTerm) bool { return lookup(t)[name] > 0 },
+		}
+		if !q.Root.match(env) {
+			continue
+		}
+
+		hit := SearchHit{Issue: issue, Dir: dir, Status: env.status}
+		for _, t := range scored {
+			occ := lookup(t)
+			hit.Score += bm25(occ[name], len(occ), len(idx.Docs), idx.Docs[name].Length, avgLength)
+		}
+		hits = append(hits, hit)
+	}
+
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Score > hits[j].Score })
+	return hits, nil
+}
+
+// bm25 scores a term occurring tf times in a doc of the given length, when
+// df of n docs contain it
+func bm25(tf, df, n, length int, avgLength float64) float64 {
+	if tf == 0 || avgLength == 0 {
+		return 0
+	}
+	idf := math.Log(1 + (float64(n-df)+0.5)/(float64(df)+0.5))
+	norm := bm25K1 * (1 - bm25B + bm25B*float64(length)/avgLength)
+	return idf * float64(tf) * (bm25K1 + 1) / (float64(tf) + norm)
+}
+
+// requiredTextTerms returns the free text terms of the top-level AND chain,
+// which every match must contain
+func requiredTextTerms(e QueryExpr) []*QueryTerm {
+	switch e := e.(type) {
+	case *QueryAnd:
+		return append(requiredTextTerms(e.Left), requiredTextTerms(e.Right)...)
+	case *QueryTerm:
+		if e.Field == "" {
+			return []*QueryTerm{e}
+		}
+	}
+	return nil
+}
+
+// scoredTextTerms returns the free text terms that count towards the score:
+// those that aren't negated
+func scoredTextTerms(e QueryExpr, negated bool) []*QueryTerm {
+	switch e := e.(type) {
+	case *QueryAnd:
+		return append(scoredTextTerms(e.Left, negated), scoredTextTerms(e.Right, negated)...)
+	case *QueryOr:
+		return append(scoredTextTerms(e.Left, negated), scoredTextTerms(e.Right, negated)...)
+	case *QueryNot:
+		return scoredTextTerms(e.Expr, !negated)
+	case *QueryTerm:
+		if e.Field == "" && !negated {
+			return []*QueryTerm{e}
+		}
+	}
+	return nil
+}
+
+// occurrences returns how often a text term occurs in each doc containing
+// it. As with Issue.ContainsText, a word matches anywhere inside the indexed
+// words (auth matches authentication and oauth). Several words match the
+// phrase: the first may end a word and the last start one, the others must
+// match whole words. A trailing * is accepted and changes nothing.
+func (idx *searchIndex) occurrences(value string) map[string]int {
+	tokens := tokenize(value)
+	occ := make(map[string]int)
+	if len(tokens) == 0 {
+		return occ
+	}
+
+	// The positions of each word of the phrase, by doc ID
+	slots := make([]map[int][]int, len(tokens))
+	for i, token := range tokens {
+		match := matchWhole
+		switch {
+		case len(tokens) == 1:
+			match = matchInside
+		case i == 0:
+			match = matchSuffix
+		case i == len(tokens)-1:
+			match = matchPrefix
+		}
+
+		slots[i] = make(map[int][]int)
+		for _, term := range idx.expand(token, match) {
+			for id, positions := range decodePostings(idx.Postings[term]) {
+				slots[i][id] = append(slots[i][id], positions...)
+			}
+		}
+		if len(slots[i]) == 0 {
+			return occ
+		}
+	}
+
+	for id, starts := range slots[0] {
+		count := len(starts)
+		if len(slots) > 1 {
+			count = 0
+			for _, start := range starts {
+				if phraseAt(slots, id, start) {
+					count++
+				}
+			}
+		}
+		if name, ok := idx.names[id]; ok && count > 0 {
+			occ[name] = count
+		}
+	}
+	return occ
+}
+
+// phraseAt reports whether the words of slots follow each other in a doc,
+// starting at position start
+func phraseAt(slots []map[int][]int, id, start int) bool {
+	for i := 1; i < len(slots); i++ {
+		found := false
+		for _, pos := range slots[i][id] {
+			if pos == start+i {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// How a word of a query matches indexed terms
+type termMatch int
+
+const (
+	matchWhole  termMatch = iota // the term is the word
+	matchPrefix                  // the term starts with the word
+	matchSuffix                  // the term ends with the word
+	matchInside                  // the term contains the word
+)
+
+// expand returns the indexed terms a word matches
+func (idx *searchIndex) expand(word string, match termMatch) []string {
+	if match == matchWhole {
+		if _, ok := idx.Postings[word]; ok {
+			return []string{word}
+		}
+		return nil
+	}
+
+	if idx.terms == nil {
+		idx.terms = make([]string, 0, len(idx.Postings))
+		for term := range idx.Postings {
+			idx.terms = append(idx.terms, term)
+		}
+		sort.Strings(idx.terms)
+	}
+
+	var terms []string
+	if match == matchPrefix {
+		for i := sort.SearchStrings(idx.terms, word); i < len(idx.terms) && strings.HasPrefix(idx.terms[i], word); i++ {
+			terms = append(terms, idx.terms[i])
+		}
+		return terms
+	}
+	for _, term := range idx.terms {
+		if match == matchSuffix && strings.HasSuffix(term, word) || match == matchInside && strings.Contains(term, word) {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+func (idx *searchIndex) averageLength() float64 {
+	if len(idx.Docs) == 0 {
+		return 0
+	}
+	total := 0
+	for _, doc := range idx.Docs {
+		total += doc.Length
+	}
+	return float64(total) / float64(len(idx.Docs))
+}
+
+// loadSearchIndex reads the persisted index. A missing, unreadable or
+// outdated index yields an empty one, to be rebuilt.
+func (r *Repository) loadSearchIndex() *searchIndex {
+	data, err := r.store.ReadFile(SearchIndexFile)
+	if err != nil {
+		return newSearchIndex()
+	}
+	var idx searchIndex
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&idx); err != nil || idx.Version != searchIndexVersion {
+		return newSearchIndex()
+	}
+	if idx.Docs == nil {
+		idx.Docs = make(map[string]*indexedDoc)
+	}
+	if idx.Postings == nil {
+		idx.Postings = make(map[string][]byte)
+	}
+	idx.names = make(map[int]string, len(idx.Docs))
+	for name, doc := range idx.Docs {
+		idx.names[doc.ID] = name
+	}
+	return &idx
+}
+
+// saveSearchIndex writes the index to CacheDir. The index is only a cache,
+// so failures (such as a read-only checkout) are ignored.
+func (r *Repository) saveSearchIndex(idx *searchIndex) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(idx); err != nil {
+		return
+	}
+	if err := r.store.MkdirAll(CacheDir); err != nil {
+		return
+	}
+	ignore := path.Join(CacheDir, ".gitignore")
+	if !r.store.Exists(ignore) {
+		_ = r.store.WriteFile(ignore, []byte("*\n"))
+	}
+	_ = r.store.WriteFile(SearchIndexFile, buf.Bytes())
+}
+
+// appendPosting encodes the positions of a term in a doc onto its postings
+func appendPosting(buf []byte, id int, positions []int) []byte {
+	buf = binary.AppendUvarint(buf, uint64(id))
+	buf = binary.AppendUvarint(buf, uint64(len(positions)))
+	last := 0
+	for _, pos := range positions {
+		buf = binary.AppendUvarint(buf, uint64(pos-last))
+		last = pos
+	}
+	return buf
+}
+
+// decodePostings returns the positions of a term by doc ID. Decoding stops
+// at the first malformed posting.
+func decodePostings(buf []byte) map[int][]int {
+	postings := make(map[int][]int)
+	for len(buf) > 0 {
+		id, positions, rest, ok := nextPosting(buf)
+		if !ok {
+			break
+		}
+		postings[id] = positions
+		buf = rest
+	}
+	return postings
+}
+
+// nextPosting decodes the first posting of buf
+func nextPosting(buf []byte) (id int, positions []int, rest []byte, ok bool) {
+	values := make([]uint64, 0, 2)
+	read := func() bool {
+		v, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return false
+		}
+		values = append(values, v)
+		buf = buf[n:]
+		return true
+	}
+	if !read() || !read() || values[1] > uint64(len(buf)) {
+		return 0, nil, nil, false
+	}
+	id, count := int(values[0]), int(values[1])
+
+	positions = make([]int, count)
+	last := 0
+	for i := range positions {
+		delta, n := binary.Uvarint(buf)
+		if n <= 0 {
+			return 0, nil, nil, false
+		}
+		last += int(delta)
+		positions[i] = last
+		buf = buf[n:]
+	}
+	return id, positions, buf, true
+}
+
+// refreshSearchIndex brings the index up to date with the issue files and
+// reports whether anything changed. Files whose size and modification time
+// match the index (and aren't too recent to trust them) aren't read; others
+// are read and only re-indexed when their content hash changed.
+func (r *Repository) refreshSearchIndex(idx *searchIndex) bool {
+	stater, _ := r.store.(StatStore)
+	builtAt := time.Now().UnixNano()
+	trustBefore := time.Unix(0, idx.BuiltAt).Truncate(racyGranularity).UnixNano()
+	changed := false
+
+	seen := make(map[string]bool)
+	removed := make(map[int]bool)
+	for _, dir := range []string{OpenDir, ClosedDir} {
+		names, err := r.store.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, base := range names {
+			if !strings.HasSuffix(base, ".md") {
+				continue
+			}
+			name := path.Join(dir, base)
+			doc := idx.Docs[name]
+
+			var size, modTime int64
+			if stater != nil {
+				info, err := stater.Stat(name)
+				if err != nil {
+					continue
+				}
+				size, modTime = info.Size(), info.ModTime().UnixNano()
+				if doc != nil && doc.Size == size && doc.ModTime == modTime && modTime < trustBefore {
+					seen[name] = true
+					continue
+				}
+			}
+
+			data, err := r.store.ReadFile(name)
+			if err != nil {
+				continue
+			}
+			seen[name] = true
+			if stater == nil {
+				size = int64(len(data))
+			}
+			hash := sha256.Sum256(data)
+			if doc != nil && doc.Hash == hash {
+				// Record the new stat, or move BuiltAt past a racy mtime,
+				// so the file isn't read again next time
+				if stater != nil {
+					doc.Size, doc.ModTime = size, modTime
+					changed = true
+				}
+				continue
+			}
+
+			if doc != nil {
+				removed[doc.ID] = true
+			}
+			idx.add(name, data, &indexedDoc{Size: size, ModTime: modTime, Hash: hash})
+			changed = true
+		}
+	}
+
+	for name, doc := range idx.Docs {
+		if !seen[name] {
+			removed[doc.ID] = true
+			delete(idx.Docs, name)
+			delete(idx.names, doc.ID)
+			changed = true
+		}
+	}
+	idx.removePostings(removed)
+
+	if changed || idx.BuiltAt == 0 {
+		idx.BuiltAt = builtAt
+		changed = true
+	}
+	return changed
+}
+
+// add indexes the title, body and comments of an issue file under a new ID,
+// replacing the file's entry in Docs. Postings of a replaced entry must be
+// removed with removePostings.
+func (idx *searchIndex) add(name string, data []byte, doc *indexedDoc) {
+	if old, ok := idx.Docs[name]; ok {
+		delete(idx.names, old.ID)
+	}
+	doc.ID = idx.NextID
+	idx.NextID++
+	idx.Docs[name] = doc
+	idx.names[doc.ID] = name
+	idx.terms = nil
+
+	issue, err := ParseMarkdown(string(data))
+	if err != nil {
+		return // Indexed as empty, so it isn't re-read until it changes
+	}
+
+	texts := []string{issue.Title, issue.Body}
+	for _, comment := range issue.Comments {
+		texts = append(texts, comment.Body)
+	}
+
+	positions := make(map[string][]int)
+	var order []string
+	pos := 0
+	for _, text := range texts {
+		for _, token := range tokenize(text) {
+			if _, ok := positions[token]; !ok {
+				order = append(order, token)
+			}
+			positions[token] = append(positions[token], pos)
+			doc.Length++
+			pos++
+		}
+		pos += fieldGap
+	}
+	for _, token := range order {
+		idx.Postings[token] = appendPosting(idx.Postings[token], doc.ID, positions[token])
+	}
+}
+
+// removePostings drops the postings of the given doc IDs
+func (idx *searchIndex) removePostings(ids map[int]bool) {
+	if len(ids) == 0 {
+		return
+	}
+	for term, buf := range idx.Postings {
+		var kept []byte
+		for rest := buf; len(rest) > 0; {
+			id, positions, next, ok := nextPosting(rest)
+			if !ok {
+				break
+			}
+			if !ids[id] {
+				kept = appendPosting(kept, id, positions)
+			}
+			rest = next
+		}
+		if len(kept) == 0 {
+			delete(idx.Postings, term)
+		} else {
+			idx.Postings[term] = kept
+		}
+	}
+	idx.terms = nil
+}
+
+// Search finds issues of the resolved .issues directory matching a query,
+// best matches first
+func Search(q *Query) ([]SearchHit, error) {
+	return DefaultRepository().Search(q)
+}
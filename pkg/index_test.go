@@ -0,0 +1,293 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+// newIndexTestRepo returns an initialized repository on disk, so the index
+// sees real file sizes and modification times
+func newIndexTestRepo(t testing.TB) *Repository {
+	t.Helper()
+
+	repo := NewRepository(NewFSStore(t.TempDir()))
+	if err := repo.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func saveTestIssue(t testing.TB, repo *Repository, id, title, body, dir string) *Issue {
+	t.Helper()
+
+	issue := repo.NewIssueWithID(id, title, "", nil)
+	issue.Body = body
+	if err := repo.SaveIssue(issue, dir); err != nil {
+		t.Fatal(err)
+	}
+	return issue
+}
+
+func searchIDs(t *testing.T, repo *Repository, query string) []string {
+	t.Helper()
+
+	q, err := ParseQuery(query, &Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hits, err := repo.Search(q)
+	if err != nil {
+		t.Fatalf("Search(%q) error = %v", query, err)
+	}
+	ids := []string{}
+	for _, hit := range hits {
+		ids = append(ids, hit.Issue.ID)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	repo := newIndexTestRepo(t)
+	saveTestIssue(t, repo, "001", "Fix Redis connection timeout", "The connection reset after 30s.", OpenDir)
+	saveTestIssue(t, repo, "002", "Add user authentication", "Use JWT tokens. Reset the password flow.", OpenDir)
+	saveTestIssue(t, repo, "003", "Authorize API calls", "Check scopes", ClosedDir)
+	saveTestIssue(t, repo, "004", "Timeout timeout timeout", "Timeouts everywhere: timeout", OpenDir)
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"redis", "[001]"},
+		{"auth", "[003 002]"},                      // part of a word, shorter issue first
+		{"thoriz", "[003]"},                        // anywhere in a word
+		{"auth*", "[003 002]"},                     // a trailing * is accepted
+		{`"connection reset"`, "[001]"},            // phrase
+		{`"reset connection"`, "[]"},               // phrase order matters
+		{`"connection res*"`, "[001]"},             // phrase with a prefix
+		{"timeout", "[004 001]"},                   // ranked by term frequency
+		{"timeout -redis", "[004]"},                // negated words
+		{"reset status:open", "[001 002]"},         // qualifiers still apply
+		{"jwt OR scopes", "[003 002]"},             // OR reads every issue
+		{`"redis timeout"`, "[]"},                  // no match across words
+		{`"timeout timeout" status:open`, "[004]"}, // repeated words
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(searchIDs(t, repo, tt.query)); got != tt.want {
+			t.Errorf("Search(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestSearchMatchesLikeScanning(t *testing.T) {
+	repo := newIndexTestRepo(t)
+	saveTestIssue(t, repo, "001", "로그인을 할 수 없음", "세션이 만료된 후 로그인 페이지로 이동하지 않습니다.", OpenDir)
+	saveTestIssue(t, repo, "002", "Support OAuth login", "Sign in with an identity provider.", OpenDir)
+	saveTestIssue(t, repo, "003", "Update documentation", "", OpenDir)
+
+	// The index finds what a scan with Query.Match does
+	for _, query := range []string{"로그인", "로그인을", "만료", "auth", "login", "sign in", `"in with an"`, "doc"} {
+		q, err := ParseQuery(query, &Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var want []string
+		for _, id := range []string{"001", "002", "003"} {
+			issue, dir, err := repo.LoadIssue(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.Match(issue, dir, StateOpen) {
+				want = append(want, id)
+			}
+		}
+		got := searchIDs(t, repo, query)
+		sort.Strings(got)
+		if fmt.Sprint(got) != fmt.Sprint(want) || len(want) == 0 {
+			t.Errorf("Search(%q) = %v, scanning finds %v", query, got, want)
+		}
+	}
+}
+
+func TestSearchIndexFollowsChanges(t *testing.T) {
+	repo := newIndexTestRepo(t)
+	issue := saveTestIssue(t, repo, "001", "Fix Redis connection timeout", "", OpenDir)
+	saveTestIssue(t, repo, "002", "Add user authentication", "", OpenDir)
+
+	if got := fmt.Sprint(searchIDs(t, repo, "redis")); got != "[001]" {
+		t.Fatalf("Search(redis) = %s, want [001]", got)
+	}
+	if !repo.store.Exists(SearchIndexFile) {
+		t.Fatalf("%s was not written", SearchIndexFile)
+	}
+	data, err := repo.store.ReadFile(CacheDir + "/.gitignore")
+	if err != nil || string(data) != "*\n" {
+		t.Errorf("%s/.gitignore = %q, %v; want *", CacheDir, data, err)
+	}
+
+	// Edited issues are re-indexed
+	issue.Title = "Fix Memcached connection timeout"
+	if err := repo.SaveIssue(issue, OpenDir); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(searchIDs(t, repo, "redis")); got != "[]" {
+		t.Errorf("Search(redis) after edit = %s, want []", got)
+	}
+	if got := fmt.Sprint(searchIDs(t, repo, "memcached")); got != "[001]" {
+		t.Errorf("Search(memcached) after edit = %s, want [001]", got)
+	}
+
+	// Closed issues are found in closed/
+	if err := repo.MoveIssue("001", OpenDir, ClosedDir); err != nil {
+		t.Fatal(err)
+	}
+	q, _ := ParseQuery("memcached", &Config{})
+	hits, err := repo.Search(q)
+	if err != nil || len(hits) != 1 || hits[0].Dir != ClosedDir {
+		t.Errorf("Search(memcached) after close = %+v, %v; want 001 in closed", hits, err)
+	}
+
+	// Deleted issues are dropped
+	if err := repo.DeleteIssue("001"); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(searchIDs(t, repo, "memcached")); got != "[]" {
+		t.Errorf("Search(memcached) after delete = %s, want []", got)
+	}
+	idx := repo.loadSearchIndex()
+	if _, ok := idx.Postings["memcached"]; ok || len(idx.Docs) != 1 {
+		t.Errorf("index still holds the deleted issue: %d docs", len(idx.Docs))
+	}
+}
+
+func TestSearchIndexSkipsUnchangedFiles(t *testing.T) {
+	repo := newIndexTestRepo(t)
+	saveTestIssue(t, repo, "001", "Fix Redis connection timeout", "", OpenDir)
+	searchIDs(t, repo, "redis")
+
+	// Pretend the index was built well after the file was written
+	idx := repo.loadSearchIndex()
+	idx.BuiltAt = time.Now().Add(time.Hour).UnixNano()
+	if repo.refreshSearchIndex(idx) {
+		t.Error("refreshSearchIndex() reported changes for unchanged files")
+	}
+
+	// A file changed behind the index's back with the same size and mtime
+	// is trusted; one with a new mtime is re-read
+	name := filepath.Join(repo.Path(), OpenDir, "001-fix-redis-connection-timeout.md")
+	info, err := os.Stat(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(name, info.ModTime(), info.ModTime().Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if !repo.refreshSearchIndex(idx) {
+		t.Error("refreshSearchIndex() should notice a new modification time")
+	}
+}
+
+func TestSearchIgnoresCorruptIndex(t *testing.T) {
+	repo := newIndexTestRepo(t)
+	saveTestIssue(t, repo, "001", "Fix Redis connection timeout", "", OpenDir)
+
+	if err := repo.store.MkdirAll(CacheDir); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.store.WriteFile(SearchIndexFile, []byte("not an index")); err != nil {
+		t.Fatal(err)
+	}
+	if got := fmt.Sprint(searchIDs(t, repo, "redis")); got != "[001]" {
+		t.Errorf("Search(redis) = %s, want [001]", got)
+	}
+}
+
+func TestSearchMemStore(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	createWithID(t, repo, "Fix Redis connection timeout", "")
+
+	if got := fmt.Sprint(searchIDs(t, repo, "redis")); got != "[001]" {
+		t.Errorf("Search(redis) = %s, want [001]", got)
+	}
+}
+
+// BenchmarkSearch compares an indexed search with scanning every issue, over
+// a store of mostly closed issues
+func BenchmarkSearch(b *testing.B) {
+	const issues = 2000
+	words := []string{"cache", "login", "deploy", "queue", "render", "export", "billing", "search"}
+
+	repo := newIndexTestRepo(b)
+	for i := 1; i <= issues; i++ {
+		dir := ClosedDir
+		if i%10 == 0 {
+			dir = OpenDir
+		}
+		issue := repo.NewIssueWithID(FormatID(i), fmt.Sprintf("Fix %s bug %d", words[i%len(words)], i), "", nil)
+		for j := 0; j < 20; j++ {
+			issue.Body += fmt.Sprintf("Step %d: check the %s service logs and retry. ", j, words[(i+j)%len(words)])
+		}
+		if i == issues/2 {
+			issue.Body += "The needle in a haystack regression."
+		}
+		content, err := SerializeIssue(issue)
+		if err != nil {
+			b.Fatal(err)
+		}
+		name := filepath.Join(repo.Path(), dir, fmt.Sprintf("%s-issue.md", issue.ID))
+		if err := os.WriteFile(name, []byte(content), 0644); err != nil {
+			b.Fatal(err)
+		}
+		// Old issues, as most closed ones are
+		old := time.Now().Add(-24 * time.Hour)
+		if err := os.Chtimes(name, old, old); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	q, err := ParseQuery(`"needle in a haystack"`, &Config{})
+	if err != nil {
+		b.Fatal(err)
+	}
+	cfg, err := repo.LoadConfig()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("indexed", func(b *testing.B) {
+		// Build the index outside the timed loop
+		if _, err := repo.Search(q); err != nil {
+			b.Fatal(err)
+		}
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			hits, err := repo.Search(q)
+			if err != nil || len(hits) != 1 {
+				b.Fatalf("Search() = %d hits, %v; want 1", len(hits), err)
+			}
+		}
+	})
+
+	b.Run("linear", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			found := 0
+			for _, dir := range []string{OpenDir, ClosedDir} {
+				list, err := repo.ListIssues(dir)
+				if err != nil {
+					b.Fatal(err)
+				}
+				for _, issue := range list {
+					if q.Match(issue, dir, cfg.Workflow.StatusOf(issue, dir)) {
+						found++
+					}
+				}
+			}
+			if found != 1 {
+				b.Fatalf("scan found %d issues, want 1", found)
+			}
+		}
+	})
+}
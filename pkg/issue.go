@@ -1,6 +1,9 @@
 package pkg
 
-import "time"
+import (
+	"strings"
+	"time"
+)
 
 // Issue represents a git-issue with metadata and content
 type Issue struct {
@@ -9,8 +12,32 @@ type Issue struct {
 	Labels   []string  `yaml:"labels"`
 	Created  time.Time `yaml:"created"`
 	Updated  time.Time `yaml:"updated"`
+	Status   string    `yaml:"status,omitempty"` // Workflow state; open/closed are implied by the directory
+
+	// Parent is the ID of the issue (e.g. an epic) this one is part of
+	Parent string `yaml:"parent,omitempty"`
+
+	// Typed links to other issues, stored on the issue they were created from
+	Blocks     []string `yaml:"blocks,omitempty"`
+	DependsOn  []string `yaml:"depends-on,omitempty"`
+	Duplicates []string `yaml:"duplicates,omitempty"`
+	RelatesTo  []string `yaml:"relates-to,omitempty"`
+
 	Title    string    `yaml:"-"` // Not in frontmatter, from markdown heading
 	Body     string    `yaml:"-"` // Markdown content after frontmatter
+	Comments []Comment `yaml:"-"` // Discussion thread from the ## Comments section
+
+	// Extra holds frontmatter keys gi doesn't know about (e.g. priority, epic)
+	// so they survive being rewritten by edit, close and open
+	Extra map[string]interface{} `yaml:"-"`
+
+	// Path is the file the issue was loaded from or saved to (set by the Repository)
+	Path string `yaml:"-"`
+	// Version identifies the file content the issue was loaded from or saved
+	// as (see ContentVersion), for detecting concurrent changes
+	Version string `yaml:"-"`
+
+	source *issueSource // original text, set by ParseMarkdown
 }
 
 // HasLabel checks if the issue has a specific label
@@ -22,3 +49,40 @@ func (i *Issue) HasLabel(label string) bool {
 	}
 	return false
 }
+
+// Field returns the value of a custom frontmatter field
+func (i *Issue) Field(name string) (interface{}, bool) {
+	value, ok := i.Extra[name]
+	return value, ok
+}
+
+// SetField sets a custom frontmatter field
+func (i *Issue) SetField(name string, value interface{}) {
+	if i.Extra == nil {
+		i.Extra = make(map[string]interface{})
+	}
+	i.Extra[name] = value
+}
+
+// RemoveField removes a custom frontmatter field
+func (i *Issue) RemoveField(name string) {
+	delete(i.Extra, name)
+}
+
+// AddLabel adds a label unless the issue already has it
+func (i *Issue) AddLabel(label string) {
+	if label = strings.TrimSpace(label); label != "" && !i.HasLabel(label) {
+		i.Labels = append(i.Labels, label)
+	}
+}
+
+// RemoveLabel removes a label if the issue has it
+func (i *Issue) RemoveLabel(label string) {
+	labels := i.Labels[:0]
+	for _, l := range i.Labels {
+		if l != label {
+			labels = append(labels, l)
+		}
+	}
+	i.Labels = labels
+}
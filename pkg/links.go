@@ -0,0 +1,391 @@
+package pkg
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// LinkType is a kind of relationship between two issues
+type LinkType string
+
+// Link types accepted by Link and stored in the frontmatter under the same key
+const (
+	LinkBlocks     LinkType = "blocks"
+	LinkDependsOn  LinkType = "depends-on"
+	LinkDuplicates LinkType = "duplicates"
+	LinkRelatesTo  LinkType = "relates-to"
+)
+
+// LinkTypes lists the link types in display order
+var LinkTypes = []LinkType{LinkBlocks, LinkDependsOn, LinkDuplicates, LinkRelatesTo}
+
+// ParseLinkType validates a link type name
+func ParseLinkType(s string) (LinkType, error) {
+	for _, t := range LinkTypes {
+		if string(t) == s {
+			return t, nil
+		}
+	}
+	names := make([]string, len(LinkTypes))
+	for i, t := range LinkTypes {
+		names[i] = string(t)
+	}
+	return "", fmt.Errorf("invalid link type: %s (must be one of: %s)", s, strings.Join(names, ", "))
+}
+
+// links returns the frontmatter list holding links of type t
+func (i *Issue) links(t LinkType) *[]string {
+	switch t {
+	case LinkBlocks:
+		return &i.Blocks
+	case LinkDependsOn:
+		return &i.DependsOn
+	case LinkDuplicates:
+		return &i.Duplicates
+	case LinkRelatesTo:
+		return &i.RelatesTo
+	}
+	return nil
+}
+
+// Links returns the IDs an issue links to with type t
+func (i *Issue) Links(t LinkType) []string {
+	if list := i.links(t); list != nil {
+		return *list
+	}
+	return nil
+}
+
+// inverseLink is the type stored on the other issue that expresses the same
+// relationship, or "" for one-way types
+func inverseLink(t LinkType) LinkType {
+	switch t {
+	case LinkBlocks:
+		return LinkDependsOn
+	case LinkDependsOn:
+		return LinkBlocks
+	case LinkRelatesTo:
+		return LinkRelatesTo
+	}
+	return ""
+}
+
+// Relation kinds, as seen from one issue whichever end the link is stored on
+const (
+	RelationBlocks       = "blocks"
+	RelationBlockedBy    = "blocked by"
+	RelationDuplicates   = "duplicates"
+	RelationDuplicatedBy = "duplicated by"
+	RelationRelatesTo    = "relates to"
+)
+
+// Relation is a link between an issue and another one. Links are stored on
+// one end only, so "002 blocks 001" and "001 depends-on 002" both give 001
+// a "blocked by 002" relation and 002 a "blocks 001" one.
+type Relation struct {
+	Kind  string
+	ID    string
+	Title string // "" when the linked issue no longer exists
+	Dir   string // open or closed, "" when the linked issue no longer exists
+}
+
+// relationKind returns the kind of a link of type t seen from the issue that
+// stores it (outgoing) or from its target
+func relationKind(t LinkType, outgoing bool) string {
+	switch t {
+	case LinkBlocks:
+		if outgoing {
+			return RelationBlocks
+		}
+		return RelationBlockedBy
+	case LinkDependsOn:
+		if outgoing {
+			return RelationBlockedBy
+		}
+		return RelationBlocks
+	case LinkDuplicates:
+		if outgoing {
+			return RelationDuplicates
+		}
+		return RelationDuplicatedBy
+	}
+	return RelationRelatesTo
+}
+
+// Link records that issue id has a link of type t to target. Both issues must
+// exist; unique ID prefixes are expanded. Linking twice is a no-op, and a
+// blocks or depends-on link that would make an issue (indirectly) block
+// itself is refused.
+func (r *Repository) Link(id string, t LinkType, target string) error {
+	return r.withLock(func() error {
+		return r.link(id, t, target)
+	})
+}
+
+// link implements Link; the caller must hold the store lock
+func (r *Repository) link(id string, t LinkType, target string) error {
+	if _, err := ParseLinkType(string(t)); err != nil {
+		return err
+	}
+
+	issue, dir, err := r.LoadIssue(id)
+	if err != nil {
+		return err
+	}
+	other, _, err := r.LoadIssue(target)
+	if err != nil {
+		return fmt.Errorf("link target: %w", err)
+	}
+	if issue.ID == other.ID {
+		return fmt.Errorf("issue %s can't link to itself", issue.ID)
+	}
+
+	if hasLink(issue, t, other.ID) || hasLink(other, inverseLink(t), issue.ID) {
+		return nil
+	}
+
+	if t == LinkBlocks || t == LinkDependsOn {
+		blocker, blocked := issue.ID, other.ID
+		if t == LinkDependsOn {
+			blocker, blocked = blocked, blocker
+		}
+		files, err := r.issueFiles()
+		if err != nil {
+			return err
+		}
+		if chain := blockingPath(files, blocked, blocker); chain != nil {
+			return fmt.Errorf("can't link: %s would block itself (%s)",
+				blocker, strings.Join(append([]string{blocker}, chain...), " → "))
+		}
+	}
+
+	list := issue.links(t)
+	*list = append(*list, other.ID)
+	issue.Updated = time.Now()
+
+	return r.saveIssue(issue, dir)
+}
+
+// Unlink removes a link of type t between issue id and target, whichever end
+// it's stored on. The target doesn't have to exist any more.
+func (r *Repository) Unlink(id string, t LinkType, target string) error {
+	return r.withLock(func() error {
+		return r.unlink(id, t, target)
+	})
+}
+
+// unlink implements Unlink; the caller must hold the store lock
+func (r *Repository) unlink(id string, t LinkType, target string) error {
+	if _, err := ParseLinkType(string(t)); err != nil {
+		return err
+	}
+
+	issue, dir, err := r.LoadIssue(id)
+	if err != nil {
+		return err
+	}
+
+	targetID := target
+	other, otherDir, err := r.LoadIssue(target)
+	if err == nil {
+		targetID = other.ID
+	}
+
+	found := false
+	if removeLink(issue, t, targetID) {
+		issue.Updated = time.Now()
+		if err := r.saveIssue(issue, dir); err != nil {
+			return err
+		}
+		found = true
+	}
+	if other != nil && removeLink(other, inverseLink(t), issue.ID) {
+		other.Updated = time.Now()
+		if err := r.saveIssue(other, otherDir); err != nil {
+			return err
+		}
+		found = true
+	}
+
+	if !found {
+		return fmt.Errorf("issue %s has no %s link to %s", issue.ID, t, targetID)
+	}
+	return nil
+}
+
+// Relations returns every link involving issue id, including those stored on
+// the other issue, ordered by kind and then ID
+func (r *Repository) Relations(id string) ([]Relation, error) {
+	name, _, err := r.resolveIssue(id)
+	if err != nil {
+		return nil, err
+	}
+	id, _ = IssueIDFromFilename(path.Base(name))
+
+	files, err := r.issueFiles()
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[string]*issueFile, len(files))
+	for _, f := range files {
+		if _, dup := byID[f.id]; !dup {
+			byID[f.id] = f
+		}
+	}
+
+	var relations []Relation
+	seen := make(map[Relation]bool)
+	add := func(kind, other string) {
+		rel := Relation{Kind: kind, ID: other}
+		if f, ok := byID[other]; ok {
+			rel.Title, rel.Dir = f.issue.Title, f.dir
+		}
+		if !seen[rel] {
+			seen[rel] = true
+			relations = append(relations, rel)
+		}
+	}
+
+	for _, t := range LinkTypes {
+		if self, ok := byID[id]; ok {
+			for _, other := range self.issue.Links(t) {
+				add(relationKind(t, true), other)
+			}
+		}
+		for _, f := range files {
+			if f.id != id && containsString(f.issue.Links(t), id) {
+				add(relationKind(t, false), f.id)
+			}
+		}
+	}
+
+	order := map[string]int{RelationBlockedBy: 0, RelationBlocks: 1, RelationDuplicates: 2, RelationDuplicatedBy: 3, RelationRelatesTo: 4}
+	sort.SliceStable(relations, func(i, j int) bool {
+		if relations[i].Kind != relations[j].Kind {
+			return order[relations[i].Kind] < order[relations[j].Kind]
+		}
+		return relations[i].ID < relations[j].ID
+	})
+
+	return relations, nil
+}
+
+// OpenBlockers returns the open issues that block issue id
+func (r *Repository) OpenBlockers(id string) ([]Relation, error) {
+	relations, err := r.Relations(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var blockers []Relation
+	for _, rel := range relations {
+		if rel.Kind == RelationBlockedBy && rel.Dir == OpenDir {
+			blockers = append(blockers, rel)
+		}
+	}
+	return blockers, nil
+}
+
+func hasLink(issue *Issue, t LinkType, id string) bool {
+	return t != "" && containsString(issue.Links(t), id)
+}
+
+// removeLink deletes id from the issue's links of type t, reporting whether it was there
+func removeLink(issue *Issue, t LinkType, id string) bool {
+	list := issue.links(t)
+	if list == nil {
+		return false
+	}
+	kept := make([]string, 0, len(*list))
+	for _, other := range *list {
+		if other != id {
+			kept = append(kept, other)
+		}
+	}
+	if len(kept) == len(*list) {
+		return false
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	*list = kept
+	return true
+}
+
+// blockingPath returns the chain of issues from -> ... -> to along
+// blocks/depends-on links (excluding from), or nil if to isn't reachable
+func blockingPath(files []*issueFile, from, to string) []string {
+	edges := make(map[string][]string)
+	for _, f := range files {
+		for _, blocked := range f.issue.Blocks {
+			edges[f.id] = append(edges[f.id], blocked)
+		}
+		for _, blocker := range f.issue.DependsOn {
+			edges[blocker] = append(edges[blocker], f.id)
+		}
+	}
+
+	parent := map[string]string{from: ""}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == to {
+			var chain []string
+			for id := to; id != ""; id = parent[id] {
+				chain = append([]string{id}, chain...)
+			}
+			return chain
+		}
+		for _, next := range edges[current] {
+			if _, visited := parent[next]; !visited {
+				parent[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// rewriteLinks replaces renumbered IDs in an issue's links and parent,
+// reporting whether any changed
+func (i *Issue) rewriteLinks(rewrites map[string]string) bool {
+	changed := false
+	if newID, ok := rewrites[i.Parent]; ok {
+		i.Parent = newID
+		changed = true
+	}
+	for _, t := range LinkTypes {
+		list := *i.links(t)
+		for n, id := range list {
+			if newID, ok := rewrites[id]; ok {
+				list[n] = newID
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// Link adds a typed link between two issues in the resolved .issues directory
+func Link(id string, t LinkType, target string) error {
+	return DefaultRepository().Link(id, t, target)
+}
+
+// Unlink removes a typed link between two issues in the resolved .issues directory
+func Unlink(id string, t LinkType, target string) error {
+	return DefaultRepository().Unlink(id, t, target)
+}
+
+// Relations returns the links of an issue in the resolved .issues directory
+func Relations(id string) ([]Relation, error) {
+	return DefaultRepository().Relations(id)
+}
+
+// OpenBlockers returns the open issues blocking an issue in the resolved .issues directory
+func OpenBlockers(id string) ([]Relation, error) {
+	return DefaultRepository().OpenBlockers(id)
+}
@@ -0,0 +1,186 @@
+package pkg
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLinkAndRelations(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, title := range []string{"Login page", "Database schema", "Login screen", "Docs"} {
+		createWithID(t, repo, title, "")
+	}
+
+	if err := repo.Link("002", LinkBlocks, "001"); err != nil {
+		t.Fatalf("Link(blocks) error = %v", err)
+	}
+	if err := repo.Link("003", LinkDuplicates, "001"); err != nil {
+		t.Fatalf("Link(duplicates) error = %v", err)
+	}
+	if err := repo.Link("001", LinkRelatesTo, "004"); err != nil {
+		t.Fatalf("Link(relates-to) error = %v", err)
+	}
+	// The same relationship from the other end is a no-op
+	if err := repo.Link("001", LinkDependsOn, "002"); err != nil {
+		t.Fatalf("Link(depends-on) error = %v", err)
+	}
+
+	issue, _, _ := repo.LoadIssue("001")
+	if len(issue.DependsOn) != 0 || !reflect.DeepEqual(issue.RelatesTo, []string{"004"}) {
+		t.Errorf("001 links = depends-on %v, relates-to %v", issue.DependsOn, issue.RelatesTo)
+	}
+
+	relations, err := repo.Relations("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Relation{
+		{Kind: RelationBlockedBy, ID: "002", Title: "Database schema", Dir: OpenDir},
+		{Kind: RelationDuplicatedBy, ID: "003", Title: "Login screen", Dir: OpenDir},
+		{Kind: RelationRelatesTo, ID: "004", Title: "Docs", Dir: OpenDir},
+	}
+	if !reflect.DeepEqual(relations, want) {
+		t.Errorf("Relations(001) = %+v, want %+v", relations, want)
+	}
+
+	relations, _ = repo.Relations("002")
+	if len(relations) != 1 || relations[0].Kind != RelationBlocks || relations[0].ID != "001" {
+		t.Errorf("Relations(002) = %+v", relations)
+	}
+	relations, _ = repo.Relations("004")
+	if len(relations) != 1 || relations[0].Kind != RelationRelatesTo || relations[0].ID != "001" {
+		t.Errorf("Relations(004) = %+v", relations)
+	}
+}
+
+func TestLinkValidation(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	createWithID(t, repo, "One", "")
+
+	if err := repo.Link("001", LinkBlocks, "404"); err == nil {
+		t.Error("Link() to a missing issue should fail")
+	}
+	if err := repo.Link("001", LinkBlocks, "001"); err == nil {
+		t.Error("Link() to itself should fail")
+	}
+	if err := repo.Link("001", LinkType("fixes"), "001"); err == nil {
+		t.Error("Link() with an unknown type should fail")
+	}
+	if _, err := ParseLinkType("blocked-by"); err == nil {
+		t.Error("ParseLinkType(blocked-by) should fail")
+	}
+}
+
+func TestLinkRejectsBlockingCycles(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, title := range []string{"A", "B", "C"} {
+		createWithID(t, repo, title, "")
+	}
+
+	if err := repo.Link("001", LinkBlocks, "002"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Link("003", LinkDependsOn, "002"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := repo.Link("003", LinkBlocks, "001")
+	if err == nil || !strings.Contains(err.Error(), "003 → 001 → 002 → 003") {
+		t.Errorf("Link() cycle error = %v", err)
+	}
+	if err := repo.Link("001", LinkDependsOn, "003"); err == nil {
+		t.Error("Link(depends-on) closing a cycle should fail")
+	}
+	if err := repo.Link("002", LinkBlocks, "001"); err == nil {
+		t.Error("Link() inverting an existing link should fail")
+	}
+
+	// Other link types may form cycles
+	if err := repo.Link("003", LinkRelatesTo, "001"); err != nil {
+		t.Errorf("Link(relates-to) error = %v", err)
+	}
+}
+
+func TestUnlink(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, title := range []string{"A", "B", "C"} {
+		createWithID(t, repo, title, "")
+	}
+	if err := repo.Link("001", LinkDependsOn, "002"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Link("001", LinkRelatesTo, "003"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Removed from whichever end stores it
+	if err := repo.Unlink("002", LinkBlocks, "001"); err != nil {
+		t.Fatalf("Unlink() error = %v", err)
+	}
+	if err := repo.Unlink("003", LinkRelatesTo, "001"); err != nil {
+		t.Fatalf("Unlink() error = %v", err)
+	}
+	if relations, _ := repo.Relations("001"); len(relations) != 0 {
+		t.Errorf("Relations() after unlink = %+v", relations)
+	}
+	if err := repo.Unlink("001", LinkRelatesTo, "003"); err == nil {
+		t.Error("Unlink() of a missing link should fail")
+	}
+
+	// Links to deleted issues can still be removed
+	if err := repo.Link("001", LinkBlocks, "003"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.DeleteIssue("003"); err != nil {
+		t.Fatal(err)
+	}
+	relations, _ := repo.Relations("001")
+	if len(relations) != 1 || relations[0].Dir != "" {
+		t.Errorf("Relations() with a deleted target = %+v", relations)
+	}
+	if err := repo.Unlink("001", LinkBlocks, "003"); err != nil {
+		t.Errorf("Unlink() of a deleted target error = %v", err)
+	}
+}
+
+func TestOpenBlockers(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, title := range []string{"Release", "Fix tests", "Write notes"} {
+		createWithID(t, repo, title, "")
+	}
+	if err := repo.Link("002", LinkBlocks, "001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Link("001", LinkDependsOn, "003"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MoveIssue("002", OpenDir, ClosedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	blockers, err := repo.OpenBlockers("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(blockers) != 1 || blockers[0].ID != "003" {
+		t.Errorf("OpenBlockers() = %+v, want only 003", blockers)
+	}
+}
+
+func TestRenumberRewritesLinks(t *testing.T) {
+	repo := newIDTestRepo(t, "ids:\n  strategy: provisional\n")
+	createWithID(t, repo, "Mainline", "main")
+	draft := createWithID(t, repo, "Draft", "feature/x")
+	if err := repo.Link("001", LinkDependsOn, draft.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := repo.Renumber(RenumberOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	issue, _, _ := repo.LoadIssue("001")
+	if !reflect.DeepEqual(issue.DependsOn, []string{"002"}) {
+		t.Errorf("depends-on after renumber = %v, want [002]", issue.DependsOn)
+	}
+}
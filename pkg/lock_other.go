@@ -0,0 +1,36 @@
+//go:build !unix
+
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// lockFile takes an exclusive lock by creating path with O_EXCL. Platforms
+// without flock can't detect a crashed holder, so a lock file older than the
+// timeout is treated as stale and removed.
+func lockFile(path string, timeout time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_RDWR, 0644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(path) }, nil
+		}
+		if !errors.Is(err, fs.ErrExist) {
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if info, statErr := os.Stat(path); statErr == nil && time.Since(info.ModTime()) > timeout {
+			_ = os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for lock on %s", path)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+}
@@ -0,0 +1,296 @@
+package pkg
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// MilestonesDir holds one Markdown file per milestone
+	MilestonesDir = "milestones"
+	// MilestoneField is the issue frontmatter key naming its milestone
+	MilestoneField = "milestone"
+)
+
+// Milestone groups issues towards a target date, such as a release or sprint.
+// It's stored in .issues/milestones/<slug>.md with the description as the body.
+type Milestone struct {
+	Name        string `yaml:"name"`
+	Start       string `yaml:"start,omitempty"` // YYYY-MM-DD
+	Due         string `yaml:"due,omitempty"`   // YYYY-MM-DD
+	Status      string `yaml:"status"`          // open or closed
+	Description string `yaml:"-"`
+}
+
+// Closed reports whether the milestone has been closed
+func (m *Milestone) Closed() bool {
+	return m.Status == StateClosed
+}
+
+// Validate checks the name and dates of a milestone
+func (m *Milestone) Validate() error {
+	if strings.TrimSpace(m.Name) == "" {
+		return fmt.Errorf("milestone name cannot be empty")
+	}
+	if strings.ContainsAny(m.Name, "\n\r") {
+		return fmt.Errorf("milestone name cannot span several lines")
+	}
+	if GenerateSlug(m.Name) == "" {
+		return fmt.Errorf("milestone name %q needs at least one letter or digit", m.Name)
+	}
+	if m.Status != StateOpen && m.Status != StateClosed {
+		return fmt.Errorf("invalid milestone status: %s (must be open or closed)", m.Status)
+	}
+
+	var start, due time.Time
+	var err error
+	if m.Start != "" {
+		if start, err = time.Parse(DateLayout, m.Start); err != nil {
+			return fmt.Errorf("invalid start date: %q (expected YYYY-MM-DD)", m.Start)
+		}
+	}
+	if m.Due != "" {
+		if due, err = ParseDueDate(m.Due); err != nil {
+			return err
+		}
+	}
+	if m.Start != "" && m.Due != "" && due.Before(start) {
+		return fmt.Errorf("milestone due date %s is before its start date %s", m.Due, m.Start)
+	}
+	return nil
+}
+
+// milestoneFile returns the store-relative file of a milestone. Names that
+// differ only in case or punctuation (v1.0 and v10) share a file, so only one
+// of them can be created.
+func milestoneFile(name string) string {
+	return path.Join(MilestonesDir, GenerateSlug(name)+".md")
+}
+
+// ParseMilestone parses a milestone file
+func ParseMilestone(content string) (*Milestone, error) {
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		return nil, fmt.Errorf("invalid milestone format: missing YAML frontmatter")
+	}
+
+	var m Milestone
+	if err := yaml.Unmarshal([]byte(parts[1]), &m); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML frontmatter: %w", err)
+	}
+	if m.Status == "" {
+		m.Status = StateOpen
+	}
+	m.Description = strings.TrimSpace(parts[2])
+
+	return &m, nil
+}
+
+// SerializeMilestone renders a milestone file
+func SerializeMilestone(m *Milestone) (string, error) {
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+	data, err := yaml.Marshal(m)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal YAML: %w", err)
+	}
+	buf.Write(data)
+	buf.WriteString("---\n")
+	if m.Description != "" {
+		buf.WriteString("\n" + m.Description + "\n")
+	}
+	return buf.String(), nil
+}
+
+// Milestone returns the name of the issue's milestone, or "" if it has none
+func (i *Issue) Milestone() string {
+	value, _ := i.Field(MilestoneField)
+	return FormatFieldValue(value)
+}
+
+// CreateMilestone saves a new milestone. Its status defaults to open.
+func (r *Repository) CreateMilestone(m *Milestone) error {
+	return r.withLock(func() error {
+		if m.Status == "" {
+			m.Status = StateOpen
+		}
+		if err := m.Validate(); err != nil {
+			return err
+		}
+
+		name := milestoneFile(m.Name)
+		if r.store.Exists(name) {
+			if existing, err := r.loadMilestoneFile(name); err == nil && !strings.EqualFold(existing.Name, m.Name) {
+				return fmt.Errorf("milestone %q can't be told apart from milestone %q (both would be stored in %s)", m.Name, existing.Name, r.store.Path(name))
+			}
+			return fmt.Errorf("milestone %q already exists", m.Name)
+		}
+		return r.saveMilestone(m)
+	})
+}
+
+// saveMilestone writes a milestone file; the caller must hold the store lock
+func (r *Repository) saveMilestone(m *Milestone) error {
+	if err := r.store.MkdirAll(MilestonesDir); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", r.store.Path(MilestonesDir), err)
+	}
+
+	content, err := SerializeMilestone(m)
+	if err != nil {
+		return err
+	}
+	if err := r.store.WriteFile(milestoneFile(m.Name), []byte(content)); err != nil {
+		return fmt.Errorf("failed to write milestone file: %w", err)
+	}
+	return nil
+}
+
+// LoadMilestone reads a milestone by name, ignoring case
+func (r *Repository) LoadMilestone(name string) (*Milestone, error) {
+	if !r.store.Exists(milestoneFile(name)) {
+		return nil, fmt.Errorf("milestone %q not found", name)
+	}
+	m, err := r.loadMilestoneFile(milestoneFile(name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse milestone %q: %w", name, err)
+	}
+	// v10 isn't v1.0, though they share a file
+	if !strings.EqualFold(m.Name, name) {
+		return nil, fmt.Errorf("milestone %q not found", name)
+	}
+	return m, nil
+}
+
+// loadMilestoneFile reads and parses a store-relative milestone file
+func (r *Repository) loadMilestoneFile(name string) (*Milestone, error) {
+	data, err := r.store.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return ParseMilestone(string(data))
+}
+
+// ListMilestones returns every milestone, ordered by due date (milestones
+// without one last) and then name
+func (r *Repository) ListMilestones() ([]*Milestone, error) {
+	if !r.store.Exists(MilestonesDir) {
+		return nil, nil
+	}
+	names, err := r.store.ReadDir(MilestonesDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", r.store.Path(MilestonesDir), err)
+	}
+
+	var milestones []*Milestone
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".md") {
+			continue
+		}
+		data, err := r.store.ReadFile(path.Join(MilestonesDir, name))
+		if err != nil {
+			continue // Skip files we can't read
+		}
+		m, err := ParseMilestone(string(data))
+		if err != nil {
+			continue // Skip files we can't parse
+		}
+		milestones = append(milestones, m)
+	}
+
+	sort.SliceStable(milestones, func(i, j int) bool {
+		a, b := milestones[i], milestones[j]
+		if a.Due != b.Due {
+			return b.Due == "" || a.Due != "" && a.Due < b.Due
+		}
+		return a.Name < b.Name
+	})
+	return milestones, nil
+}
+
+// CloseMilestone marks a milestone as closed. Its issues are left as they are.
+func (r *Repository) CloseMilestone(name string) error {
+	return r.withLock(func() error {
+		m, err := r.LoadMilestone(name)
+		if err != nil {
+			return err
+		}
+		if m.Closed() {
+			return fmt.Errorf("milestone %q is already closed", m.Name)
+		}
+		m.Status = StateClosed
+		return r.saveMilestone(m)
+	})
+}
+
+// MilestoneSummary counts the issues of a milestone
+type MilestoneSummary struct {
+	Open   []*Issue
+	Closed []*Issue
+}
+
+// Total returns the number of issues in the milestone
+func (s MilestoneSummary) Total() int {
+	return len(s.Open) + len(s.Closed)
+}
+
+// PercentComplete returns the share of closed issues, 0 for an empty milestone
+func (s MilestoneSummary) PercentComplete() int {
+	if s.Total() == 0 {
+		return 0
+	}
+	return len(s.Closed) * 100 / s.Total()
+}
+
+// MilestoneSummary collects the open and closed issues of a milestone,
+// ignoring the case of their milestone names
+func (r *Repository) MilestoneSummary(name string) (MilestoneSummary, error) {
+	var summary MilestoneSummary
+	for _, dir := range []string{OpenDir, ClosedDir} {
+		issues, err := r.ListIssues(dir)
+		if err != nil {
+			return summary, err
+		}
+		for _, issue := range issues {
+			if !strings.EqualFold(issue.Milestone(), name) {
+				continue
+			}
+			if dir == ClosedDir {
+				summary.Closed = append(summary.Closed, issue)
+			} else {
+				summary.Open = append(summary.Open, issue)
+			}
+		}
+	}
+	return summary, nil
+}
+
+// CreateMilestone saves a new milestone in the resolved .issues directory
+func CreateMilestone(m *Milestone) error {
+	return DefaultRepository().CreateMilestone(m)
+}
+
+// LoadMilestone reads a milestone from the resolved .issues directory
+func LoadMilestone(name string) (*Milestone, error) {
+	return DefaultRepository().LoadMilestone(name)
+}
+
+// ListMilestones returns the milestones of the resolved .issues directory
+func ListMilestones() ([]*Milestone, error) {
+	return DefaultRepository().ListMilestones()
+}
+
+// CloseMilestone closes a milestone in the resolved .issues directory
+func CloseMilestone(name string) error {
+	return DefaultRepository().CloseMilestone(name)
+}
+
+// GetMilestoneSummary counts the issues of a milestone in the resolved .issues directory
+func GetMilestoneSummary(name string) (MilestoneSummary, error) {
+	return DefaultRepository().MilestoneSummary(name)
+}
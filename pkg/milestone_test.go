@@ -0,0 +1,156 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMilestoneRoundTrip(t *testing.T) {
+	m := &Milestone{Name: "Sprint 12", Start: "2026-10-01", Due: "2026-10-14", Status: StateOpen, Description: "Ship checkout."}
+	content, err := SerializeMilestone(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseMilestone(content)
+	if err != nil {
+		t.Fatalf("ParseMilestone() error = %v\n%s", err, content)
+	}
+	if *parsed != *m {
+		t.Errorf("round trip = %+v, want %+v", parsed, m)
+	}
+}
+
+func TestMilestoneValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		m       Milestone
+		wantErr bool
+	}{
+		{"valid", Milestone{Name: "v1.0", Start: "2026-10-01", Due: "2026-11-01", Status: StateOpen}, false},
+		{"no dates", Milestone{Name: "Backlog", Status: StateOpen}, false},
+		{"empty name", Milestone{Name: " ", Status: StateOpen}, true},
+		{"punctuation only", Milestone{Name: "!!", Status: StateOpen}, true},
+		{"bad due", Milestone{Name: "v1", Due: "soon", Status: StateOpen}, true},
+		{"due before start", Milestone{Name: "v1", Start: "2026-11-01", Due: "2026-10-01", Status: StateOpen}, true},
+		{"bad status", Milestone{Name: "v1", Status: "done"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.m.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMilestones(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+
+	for _, m := range []*Milestone{
+		{Name: "Backlog"},
+		{Name: "v2.0", Due: "2026-12-01"},
+		{Name: "v1.0", Due: "2026-11-01"},
+	} {
+		if err := repo.CreateMilestone(m); err != nil {
+			t.Fatalf("CreateMilestone(%s) error = %v", m.Name, err)
+		}
+	}
+	if err := repo.CreateMilestone(&Milestone{Name: "v1.0"}); err == nil {
+		t.Error("CreateMilestone() should reject an existing name")
+	}
+
+	milestones, err := repo.ListMilestones()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, m := range milestones {
+		names = append(names, m.Name)
+	}
+	if len(names) != 3 || names[0] != "v1.0" || names[1] != "v2.0" || names[2] != "Backlog" {
+		t.Errorf("ListMilestones() = %v, want [v1.0 v2.0 Backlog]", names)
+	}
+
+	// Two of three issues in v1.0, one of them closed
+	for i, milestone := range []string{"v1.0", "v1.0", "v2.0"} {
+		issue := createWithID(t, repo, "Issue", "")
+		issue.SetField(MilestoneField, milestone)
+		if err := repo.SaveIssue(issue, OpenDir); err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			if err := repo.MoveIssue(issue.ID, OpenDir, ClosedDir); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	summary, err := repo.MilestoneSummary("v1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Open) != 1 || len(summary.Closed) != 1 || summary.PercentComplete() != 50 {
+		t.Errorf("MilestoneSummary() = %d open, %d closed, %d%%", len(summary.Open), len(summary.Closed), summary.PercentComplete())
+	}
+	if empty, _ := repo.MilestoneSummary("Backlog"); empty.PercentComplete() != 0 {
+		t.Error("an empty milestone should be 0% complete")
+	}
+
+	if err := repo.CloseMilestone("v1.0"); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := repo.LoadMilestone("v1.0"); !m.Closed() {
+		t.Error("CloseMilestone() didn't close the milestone")
+	}
+	if err := repo.CloseMilestone("v1.0"); err == nil {
+		t.Error("CloseMilestone() should fail on a closed milestone")
+	}
+	if _, err := repo.LoadMilestone("v3.0"); err == nil {
+		t.Error("LoadMilestone() should fail for a missing milestone")
+	}
+}
+
+func TestMilestoneNames(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	if err := repo.CreateMilestone(&Milestone{Name: "v1.0"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// v10 would share v1.0's file
+	err := repo.CreateMilestone(&Milestone{Name: "v10"})
+	if err == nil || !strings.Contains(err.Error(), `can't be told apart from milestone "v1.0"`) {
+		t.Errorf("CreateMilestone(v10) error = %v, want a clash with v1.0", err)
+	}
+	if _, err := repo.LoadMilestone("v10"); err == nil {
+		t.Error("LoadMilestone(v10) found v1.0")
+	}
+
+	// Issues get the milestone's own name, whatever the case it was given in
+	issue, err := repo.CreateIssue("A", "", IssueOptions{Milestone: "V1.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Milestone() != "v1.0" {
+		t.Errorf("CreateIssue() milestone = %q, want v1.0", issue.Milestone())
+	}
+	if _, err := repo.CreateIssue("B", "", IssueOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	milestone := "V1.0"
+	issue, _, err = repo.UpdateIssue("002", IssueUpdate{Milestone: &milestone})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Milestone() != "v1.0" {
+		t.Errorf("UpdateIssue() milestone = %q, want v1.0", issue.Milestone())
+	}
+
+	summary, err := repo.MilestoneSummary("v1.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.Open) != 2 {
+		t.Errorf("MilestoneSummary() counted %d open issues, want 2", len(summary.Open))
+	}
+}
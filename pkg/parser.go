@@ -2,7 +2,9 @@ package pkg
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
 	"strings"
 	"time"
@@ -10,7 +12,12 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
-// ParseMarkdown parses a markdown file with YAML frontmatter into an Issue struct
+// ErrMissingTitle is returned by ParseMarkdown for issues without a # heading
+var ErrMissingTitle = errors.New("issue missing title (# heading)")
+
+// ParseMarkdown parses a markdown file with YAML frontmatter into an Issue struct.
+// Frontmatter keys that aren't Issue fields are kept in Issue.Extra, and the
+// original text is remembered so SerializeIssue can write untouched parts back verbatim.
 func ParseMarkdown(content string) (*Issue, error) {
 	// Split frontmatter and body
 	parts := strings.SplitN(content, "---", 3)
@@ -24,6 +31,15 @@ func ParseMarkdown(content string) (*Issue, error) {
 		return nil, fmt.Errorf("failed to parse YAML frontmatter: %w", err)
 	}
 
+	// Keep unknown keys along with their order and comments
+	mapping, err := frontmatterMapping(parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse YAML frontmatter: %w", err)
+	}
+	if issue.Extra, err = decodeExtraFields(mapping); err != nil {
+		return nil, fmt.Errorf("failed to parse YAML frontmatter: %w", err)
+	}
+
 	// Extract body (everything after second ---)
 	body := strings.TrimSpace(parts[2])
 
@@ -42,14 +58,42 @@ func ParseMarkdown(content string) (*Issue, error) {
 	}
 
 	if issue.Title == "" {
-		return nil, fmt.Errorf("issue missing title (# heading)")
+		return nil, ErrMissingTitle
+	}
+
+	// The comment thread is kept apart from the body
+	issue.Body, issue.Comments = splitComments(issue.Body)
+
+	issue.source = &issueSource{
+		prefix:      parts[0],
+		frontmatter: parts[1],
+		mapping:     mapping,
+		tail:        parts[2],
+		known:       snapshotIssue(&issue),
+		extra:       copyExtra(issue.Extra),
 	}
 
 	return &issue, nil
 }
 
-// SerializeIssue converts an Issue struct to markdown format with YAML frontmatter
+// SerializeIssue converts an Issue struct to markdown format with YAML frontmatter.
+// Issues returned by ParseMarkdown are written back byte-for-byte except for
+// the frontmatter keys, title and body that were changed since parsing.
 func SerializeIssue(issue *Issue) (string, error) {
+	if src := issue.source; src != nil && src.mapping != nil {
+		frontmatter, err := src.render(issue)
+		if err != nil {
+			return "", err
+		}
+
+		tail := src.tail
+		if issue.Title != src.known.Title || issue.Body != src.known.Body || !reflect.DeepEqual(issue.Comments, src.known.Comments) {
+			tail = "\n\n" + serializeContent(issue)
+		}
+
+		return src.prefix + "---" + frontmatter + "---" + tail, nil
+	}
+
 	var buf bytes.Buffer
 
 	// Write YAML frontmatter
@@ -59,8 +103,24 @@ func SerializeIssue(issue *Issue) (string, error) {
 		return "", fmt.Errorf("failed to marshal YAML: %w", err)
 	}
 	buf.Write(yamlData)
+	for _, key := range sortedKeys(issue.Extra) {
+		field, err := marshalField(key, issue.Extra[key])
+		if err != nil {
+			return "", err
+		}
+		buf.WriteString(field)
+	}
 	buf.WriteString("---\n\n")
 
+	buf.WriteString(serializeContent(issue))
+
+	return buf.String(), nil
+}
+
+// serializeContent renders the title heading, body and comment thread
+func serializeContent(issue *Issue) string {
+	var buf bytes.Buffer
+
 	// Write title
 	buf.WriteString("# ")
 	buf.WriteString(issue.Title)
@@ -72,7 +132,26 @@ func SerializeIssue(issue *Issue) (string, error) {
 		buf.WriteString("\n")
 	}
 
-	return buf.String(), nil
+	// Write comment thread
+	if comments := serializeComments(issue.Comments); comments != "" {
+		if issue.Body != "" {
+			buf.WriteString("\n")
+		}
+		buf.WriteString(comments)
+	}
+
+	return buf.String()
+}
+
+func copyExtra(extra map[string]interface{}) map[string]interface{} {
+	if extra == nil {
+		return nil
+	}
+	copied := make(map[string]interface{}, len(extra))
+	for key, value := range extra {
+		copied[key] = value
+	}
+	return copied
 }
 
 // GenerateSlug generates a URL-safe slug from a title
@@ -216,3 +216,169 @@ updated: 2025-11-14T14:20:00Z
 		t.Errorf("Assignee mismatch: %q != %q", reparsed.Assignee, issue.Assignee)
 	}
 }
+
+const handWrittenIssue = `---
+# Owned by the payments squad
+id: "007"
+assignee: mina
+priority: high   # set during triage
+labels:
+  - bug
+  - payments
+epic: checkout-v2
+created: 2025-11-14T10:30:00Z
+updated: 2025-11-14T14:20:00Z
+estimate:
+  points: 3
+  confidence: low
+---
+
+
+# Refund webhook drops events
+
+Body with   odd    spacing.
+
+* bullets with stars
+`
+
+func TestSerializeUnchangedIssueIsByteForByte(t *testing.T) {
+	issue, err := ParseMarkdown(handWrittenIssue)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+
+	serialized, err := SerializeIssue(issue)
+	if err != nil {
+		t.Fatalf("SerializeIssue() error = %v", err)
+	}
+
+	if serialized != handWrittenIssue {
+		t.Errorf("untouched issue changed on round-trip:\n got: %q\nwant: %q", serialized, handWrittenIssue)
+	}
+}
+
+func TestParseMarkdownKeepsUnknownFields(t *testing.T) {
+	issue, err := ParseMarkdown(handWrittenIssue)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+
+	if issue.Extra["priority"] != "high" {
+		t.Errorf("Extra[priority] = %v, want high", issue.Extra["priority"])
+	}
+	if issue.Extra["epic"] != "checkout-v2" {
+		t.Errorf("Extra[epic] = %v, want checkout-v2", issue.Extra["epic"])
+	}
+	if _, ok := issue.Extra["id"]; ok {
+		t.Error("known fields should not be duplicated in Extra")
+	}
+	if len(issue.Labels) != 2 {
+		t.Errorf("Labels = %v, want 2 labels", issue.Labels)
+	}
+}
+
+func TestSerializeOnlyRewritesChangedFields(t *testing.T) {
+	issue, err := ParseMarkdown(handWrittenIssue)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+
+	issue.Updated = time.Date(2025, 12, 1, 9, 0, 0, 0, time.UTC)
+
+	serialized, err := SerializeIssue(issue)
+	if err != nil {
+		t.Fatalf("SerializeIssue() error = %v", err)
+	}
+
+	want := strings.Replace(handWrittenIssue, "updated: 2025-11-14T14:20:00Z", "updated: 2025-12-01T09:00:00Z", 1)
+	if serialized != want {
+		t.Errorf("only the updated line should change:\n got: %q\nwant: %q", serialized, want)
+	}
+}
+
+func TestSerializeRewritesMultiLineFieldInPlace(t *testing.T) {
+	issue, err := ParseMarkdown(handWrittenIssue)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+
+	issue.Labels = append(issue.Labels, "urgent")
+	issue.Extra["epic"] = "checkout-v3"
+	delete(issue.Extra, "estimate")
+	issue.Extra["team"] = "payments"
+
+	serialized, err := SerializeIssue(issue)
+	if err != nil {
+		t.Fatalf("SerializeIssue() error = %v", err)
+	}
+
+	for _, untouched := range []string{
+		"# Owned by the payments squad\nid: \"007\"\nassignee: mina\npriority: high   # set during triage\n",
+		"created: 2025-11-14T10:30:00Z\nupdated: 2025-11-14T14:20:00Z\n",
+		"\n\n# Refund webhook drops events\n\nBody with   odd    spacing.\n\n* bullets with stars\n",
+	} {
+		if !strings.Contains(serialized, untouched) {
+			t.Errorf("untouched text %q missing from:\n%s", untouched, serialized)
+		}
+	}
+	if strings.Contains(serialized, "estimate") {
+		t.Errorf("deleted field should be removed:\n%s", serialized)
+	}
+
+	reparsed, err := ParseMarkdown(serialized)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() of rewritten issue error = %v", err)
+	}
+	if !reparsed.HasLabel("urgent") || len(reparsed.Labels) != 3 {
+		t.Errorf("Labels = %v, want bug, payments, urgent", reparsed.Labels)
+	}
+	if reparsed.Extra["epic"] != "checkout-v3" || reparsed.Extra["team"] != "payments" {
+		t.Errorf("Extra = %v, want updated epic and new team", reparsed.Extra)
+	}
+	if strings.Index(serialized, "epic:") > strings.Index(serialized, "created:") {
+		t.Errorf("rewritten field should keep its position:\n%s", serialized)
+	}
+}
+
+func TestSerializeChangedBodyKeepsFrontmatter(t *testing.T) {
+	issue, err := ParseMarkdown(handWrittenIssue)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+
+	issue.Body = "Rewritten body."
+
+	serialized, err := SerializeIssue(issue)
+	if err != nil {
+		t.Fatalf("SerializeIssue() error = %v", err)
+	}
+
+	frontmatterEnd := strings.Index(handWrittenIssue[3:], "---") + 6
+	if !strings.HasPrefix(serialized, handWrittenIssue[:frontmatterEnd]) {
+		t.Errorf("frontmatter should be untouched when only the body changes:\n%s", serialized)
+	}
+	if !strings.HasSuffix(serialized, "# Refund webhook drops events\n\nRewritten body.\n") {
+		t.Errorf("body not rewritten:\n%s", serialized)
+	}
+}
+
+func TestSerializeNewIssueWithExtraFields(t *testing.T) {
+	issue := &Issue{
+		ID:    "003",
+		Title: "Extra on a new issue",
+		Extra: map[string]interface{}{"priority": "low", "epic": "search"},
+	}
+
+	serialized, err := SerializeIssue(issue)
+	if err != nil {
+		t.Fatalf("SerializeIssue() error = %v", err)
+	}
+
+	reparsed, err := ParseMarkdown(serialized)
+	if err != nil {
+		t.Fatalf("ParseMarkdown() error = %v", err)
+	}
+	if reparsed.Extra["priority"] != "low" || reparsed.Extra["epic"] != "search" {
+		t.Errorf("Extra = %v, want priority and epic", reparsed.Extra)
+	}
+}
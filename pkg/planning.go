@@ -0,0 +1,165 @@
+package pkg
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Frontmatter keys of the planning fields
+const (
+	PriorityField = "priority"
+	DueField      = "due"
+)
+
+// DefaultPriorities are the priority levels, highest first, used when
+// config.yaml doesn't set priorities
+var DefaultPriorities = []string{"critical", "high", "medium", "low"}
+
+// PriorityLevels returns the priority levels, highest first: the priorities
+// list of config.yaml, or DefaultPriorities
+func (c *Config) PriorityLevels() []string {
+	if len(c.Priorities) > 0 {
+		return c.Priorities
+	}
+	return DefaultPriorities
+}
+
+// PriorityRank orders priorities for sorting: 0 for the highest level, and
+// the number of levels for a missing or unknown priority
+func (c *Config) PriorityRank(priority string) int {
+	levels := c.PriorityLevels()
+	for i, level := range levels {
+		if level == priority {
+			return i
+		}
+	}
+	return len(levels)
+}
+
+// CheckPriority validates a priority against the configured levels. When
+// priority is declared as a custom field (and config.yaml has no priorities
+// list), the field's declaration is used instead.
+func (c *Config) CheckPriority(priority string) error {
+	if field := c.Field(PriorityField); field != nil && len(c.Priorities) == 0 {
+		return field.Check(priority)
+	}
+	if !containsString(c.PriorityLevels(), priority) {
+		return fmt.Errorf("invalid priority: %s (must be one of: %s)", priority, strings.Join(c.PriorityLevels(), ", "))
+	}
+	return nil
+}
+
+// validatePlanning checks an issue's priority and due date
+func (c *Config) validatePlanning(issue *Issue) error {
+	if priority := issue.Priority(); priority != "" {
+		if err := c.CheckPriority(priority); err != nil {
+			return err
+		}
+	}
+	if value, ok := issue.Field(DueField); ok && value != nil {
+		if _, err := ParseDueDate(FormatFieldValue(value)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ParseDueDate parses a due date in YYYY-MM-DD form
+func ParseDueDate(s string) (time.Time, error) {
+	due, err := time.Parse(DateLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid due date: %q (expected YYYY-MM-DD)", s)
+	}
+	return due, nil
+}
+
+// Priority returns the issue's priority, or "" if it has none
+func (i *Issue) Priority() string {
+	value, _ := i.Field(PriorityField)
+	return FormatFieldValue(value)
+}
+
+// Due returns the issue's due date, if it has a valid one
+func (i *Issue) Due() (time.Time, bool) {
+	value, ok := i.Field(DueField)
+	if !ok {
+		return time.Time{}, false
+	}
+	due, err := ParseDueDate(FormatFieldValue(value))
+	if err != nil {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+// DaysOverdue returns how many days the issue is past its due date as of
+// now, or 0 when it isn't overdue or has no due date
+func (i *Issue) DaysOverdue(now time.Time) int {
+	due, ok := i.Due()
+	if !ok {
+		return 0
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !due.Before(today) {
+		return 0
+	}
+	return int(today.Sub(due).Hours() / 24)
+}
+
+// IssueOptions holds the optional attributes of a new issue
+type IssueOptions struct {
+	Assignee  string
+	Labels    []string
+	Priority  string // one of the configured priority levels
+	Due       string // YYYY-MM-DD
+	Milestone string // name of a milestone in .issues/milestones
+	Parent    string // ID of the parent issue
+	// Body replaces the template body when set
+	Body string
+	// Fields sets custom fields to typed values (see FieldDef.Parse)
+	Fields map[string]interface{}
+}
+
+// NewIssueWithOptions is NewIssueWithID with the optional attributes of
+// opts. The priority and due date are validated against config.yaml.
+func (r *Repository) NewIssueWithOptions(id, title string, opts IssueOptions) (*Issue, error) {
+	issue := r.newIssue(id, title, opts.Assignee, opts.Labels)
+
+	if opts.Priority != "" {
+		issue.SetField(PriorityField, opts.Priority)
+	}
+	if opts.Due != "" {
+		if _, err := ParseDueDate(opts.Due); err != nil {
+			return nil, err
+		}
+		issue.SetField(DueField, opts.Due)
+	}
+	if opts.Milestone != "" {
+		issue.SetField(MilestoneField, opts.Milestone)
+	}
+	issue.Parent = opts.Parent
+	if opts.Body != "" {
+		issue.Body = opts.Body
+	}
+	for name, value := range opts.Fields {
+		issue.SetField(name, value)
+	}
+
+	if opts.Priority != "" {
+		cfg, err := r.LoadConfig()
+		if err != nil {
+			return nil, err
+		}
+		if err := cfg.validatePlanning(issue); err != nil {
+			return nil, err
+		}
+	}
+
+	return issue, nil
+}
+
+// NewIssueWithOptions creates a new issue with a priority and due date in the resolved .issues directory
+func NewIssueWithOptions(id, title string, opts IssueOptions) (*Issue, error) {
+	return DefaultRepository().NewIssueWithOptions(id, title, opts)
+}
@@ -0,0 +1,102 @@
+package pkg
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPriorityLevels(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		want   []string
+	}{
+		{"default", "", DefaultPriorities},
+		{"configured", "priorities: [p0, p1, p2]\n", []string{"p0", "p1", "p2"}},
+		// Enum values may be listed lowest first, so they don't set the order
+		{"enum field", "fields:\n  - name: priority\n    type: enum\n    values: [low, medium, high]\n", DefaultPriorities},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := ParseConfig([]byte(tt.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			levels := cfg.PriorityLevels()
+			if len(levels) != len(tt.want) || levels[0] != tt.want[0] {
+				t.Errorf("PriorityLevels() = %v, want %v", levels, tt.want)
+			}
+			if cfg.PriorityRank(tt.want[1]) != 1 || cfg.PriorityRank("") != len(tt.want) {
+				t.Errorf("PriorityRank() ordering is wrong for %v", levels)
+			}
+		})
+	}
+
+	if _, err := ParseConfig([]byte("priorities: [high, high]\n")); err == nil {
+		t.Error("ParseConfig() should reject duplicate priorities")
+	}
+
+	// A declared priority field is checked by its declaration
+	cfg, _ := ParseConfig([]byte("fields:\n  - name: priority\n    type: string\n"))
+	if err := cfg.CheckPriority("P1"); err != nil {
+		t.Errorf("CheckPriority() with a string field error = %v", err)
+	}
+	cfg, _ = ParseConfig([]byte("fields:\n  - name: priority\n    type: enum\n    values: [low, high]\n"))
+	if cfg.CheckPriority("high") != nil || cfg.CheckPriority("critical") == nil {
+		t.Error("CheckPriority() with an enum field should use its values")
+	}
+}
+
+func TestNewIssueWithOptions(t *testing.T) {
+	repo := newIDTestRepo(t, "priorities: [p0, p1]\n")
+
+	issue, err := repo.NewIssueWithOptions("001", "Renew certificate", IssueOptions{Priority: "p0", Due: "2026-11-01"})
+	if err != nil {
+		t.Fatalf("NewIssueWithOptions() error = %v", err)
+	}
+	if issue.Priority() != "p0" {
+		t.Errorf("Priority() = %q", issue.Priority())
+	}
+	if due, ok := issue.Due(); !ok || !due.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Due() = %v, %v", due, ok)
+	}
+
+	if _, err := repo.NewIssueWithOptions("002", "x", IssueOptions{Priority: "high"}); err == nil {
+		t.Error("NewIssueWithOptions() should reject an unknown priority")
+	}
+	if _, err := repo.NewIssueWithOptions("002", "x", IssueOptions{Due: "next week"}); err == nil {
+		t.Error("NewIssueWithOptions() should reject an invalid due date")
+	}
+
+	// Due dates survive a round trip, as the quoted string or a YAML date
+	if err := repo.SaveIssue(issue, OpenDir); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _, _ := repo.LoadIssue("001")
+	if _, ok := loaded.Due(); !ok {
+		t.Error("Due() lost after saving")
+	}
+	parsed, err := ParseMarkdown("---\nid: \"009\"\ndue: 2026-01-02\n---\n\n# Title\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := parsed.Due(); !ok {
+		t.Error("Due() doesn't read unquoted YAML dates")
+	}
+}
+
+func TestDaysOverdue(t *testing.T) {
+	issue := &Issue{}
+	now := time.Date(2026, 10, 18, 15, 0, 0, 0, time.UTC)
+	if issue.DaysOverdue(now) != 0 {
+		t.Error("an issue without a due date isn't overdue")
+	}
+
+	for due, want := range map[string]int{"2026-10-15": 3, "2026-10-17": 1, "2026-10-18": 0, "2026-11-01": 0} {
+		issue.SetField(DueField, due)
+		if got := issue.DaysOverdue(now); got != want {
+			t.Errorf("DaysOverdue() with due %s = %d, want %d", due, got, want)
+		}
+	}
+}
@@ -0,0 +1,574 @@
+package pkg
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Query is a parsed filter expression such as
+//
+//	label:bug AND -label:wontfix AND assignee:(jonghun OR mina) AND updated:>7d
+//
+// Terms are field:value qualifiers or bare words, which match the title, body
+// and comments. Adjacent terms are ANDed; NOT (or a leading -) binds tighter
+// than AND, which binds tighter than OR.
+type Query struct {
+	Root QueryExpr
+	// Now is the time relative dates such as 7d count back from, giving the
+	// time they're compared with
+	Now time.Time
+}
+
+// QueryExpr is a node of a parsed query
+type QueryExpr interface {
+	// String renders the node with explicit operators and parentheses
+	String() string
+	match(env *queryEnv) bool
+}
+
+// QueryAnd matches issues that match both sides
+type QueryAnd struct {
+	Left, Right QueryExpr
+}
+
+// QueryOr matches issues that match either side
+type QueryOr struct {
+	Left, Right QueryExpr
+}
+
+// QueryNot matches issues that don't match Expr
+type QueryNot struct {
+	Expr QueryExpr
+}
+
+// QueryTerm is a single field:value qualifier, or free text when Field is ""
+type QueryTerm struct {
+	Field string
+	Op    string // "", "=", ">", ">=", "<" or "<="
+	Value string
+	Pos   int // byte offset of the term in the query
+
+	field *FieldDef     // declaration of a custom field
+	age   time.Duration // for relative dates such as 7d
+	num   int           // for int fields
+}
+
+func (e *QueryAnd) String() string { return "(" + e.Left.String() + " AND " + e.Right.String() + ")" }
+func (e *QueryOr) String() string  { return "(" + e.Left.String() + " OR " + e.Right.String() + ")" }
+func (e *QueryNot) String() string { return "NOT " + e.Expr.String() }
+
+func (t *QueryTerm) String() string {
+	value := t.Value
+	if value == "" || strings.ContainsAny(value, " ()\"") {
+		value = strconv.Quote(value)
+	}
+	if t.Field == "" {
+		return value
+	}
+	return t.Field + ":" + t.Op + value
+}
+
+// String renders the whole query with explicit operators and parentheses
+func (q *Query) String() string {
+	return q.Root.String()
+}
+
+// queryFields are the built-in qualifiers; custom fields declared in
+// config.yaml can be used too
+var queryFields = []string{"id", "title", "body", "label", "assignee", "status", "milestone", "priority", "parent", "due", "created", "updated"}
+
+// timeFields accept comparisons against YYYY-MM-DD dates or, for created and
+// updated, relative ages such as 7d
+var timeFields = []string{"created", "updated", "due"}
+
+// relativeAgeRe matches relative ages: hours, days or weeks
+var relativeAgeRe = regexp.MustCompile(`^(\d+)([hdw])$`)
+
+// QueryError reports a problem with a query and where it is
+type QueryError struct {
+	Query string
+	Pos   int // byte offset of the bad token
+	Len   int // byte length of the bad token
+	Msg   string
+}
+
+// Error renders the message with the query and a marker under the bad token
+func (e *QueryError) Error() string {
+	column := utf8.RuneCountInString(e.Query[:e.Pos])
+	width := utf8.RuneCountInString(e.Query[e.Pos : e.Pos+e.Len])
+	if width < 1 {
+		width = 1
+	}
+	return fmt.Sprintf("invalid query: %s at column %d\n  %s\n  %s%s",
+		e.Msg, column+1, e.Query, strings.Repeat(" ", column), strings.Repeat("^", width))
+}
+
+// Query tokens
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenMinus
+)
+
+type token struct {
+	kind tokenKind
+	text string // the word or unquoted string
+	pos  int
+	len  int
+}
+
+// lexQuery splits a query into tokens
+func lexQuery(input string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(input); {
+		c := input[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(':
+			tokens = append(tokens, token{kind: tokenLParen, text: "(", pos: i, len: 1})
+			i++
+		case c == ')':
+			tokens = append(tokens, token{kind: tokenRParen, text: ")", pos: i, len: 1})
+			i++
+		case c == '"':
+			end := strings.IndexByte(input[i+1:], '"')
+			if end < 0 {
+				return nil, &QueryError{Query: input, Pos: i, Len: len(input) - i, Msg: "unterminated quote"}
+			}
+			tokens = append(tokens, token{kind: tokenString, text: input[i+1 : i+1+end], pos: i, len: end + 2})
+			i += end + 2
+		case c == '-' && i+1 < len(input) && !strings.ContainsRune(" \t\n\r)", rune(input[i+1])):
+			tokens = append(tokens, token{kind: tokenMinus, text: "-", pos: i, len: 1})
+			i++
+		default:
+			start := i
+			for i < len(input) && !strings.ContainsRune(" \t\n\r()\"", rune(input[i])) {
+				i++
+			}
+			word := input[start:i]
+			kind := tokenWord
+			switch word {
+			case "AND":
+				kind = tokenAnd
+			case "OR":
+				kind = tokenOr
+			case "NOT":
+				kind = tokenNot
+			}
+			tokens = append(tokens, token{kind: kind, text: word, pos: start, len: i - start})
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// queryParser is a recursive descent parser over the tokens of a query
+type queryParser struct {
+	input  string
+	tokens []token
+	next   int
+	cfg    *Config
+}
+
+// ParseQuery parses a query. Fields and values are checked against cfg when
+// it's given; without it, unknown fields are taken to be custom fields.
+func ParseQuery(input string, cfg *Config) (*Query, error) {
+	tokens, err := lexQuery(input)
+	if err != nil {
+		return nil, err
+	}
+	p := &queryParser{input: input, tokens: tokens, cfg: cfg}
+
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorAt(p.peek(), "empty query")
+	}
+	root, err := p.parseOr("")
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		if tok.kind == tokenRParen {
+			return nil, p.errorAt(tok, "unmatched )")
+		}
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.text))
+	}
+
+	return &Query{Root: root, Now: time.Now()}, nil
+}
+
+// ParseSearch parses the query of a text search. Input that doesn't parse as
+// a query, such as an error message ("TypeError: cannot read") or a URL, is
+// searched for as text: issues must contain each of its words, less any
+// punctuation around them.
+func ParseSearch(input string, cfg *Config) (*Query, error) {
+	q, err := ParseQuery(input, cfg)
+	if err == nil {
+		return q, nil
+	}
+
+	var root QueryExpr
+	for _, word := range strings.Fields(input) {
+		word = strings.TrimFunc(word, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		if word == "" {
+			continue // Punctuation on its own, such as a dash
+		}
+		term := &QueryTerm{Value: word, Pos: strings.Index(input, word)}
+		if root == nil {
+			root = term
+		} else {
+			root = &QueryAnd{Left: root, Right: term}
+		}
+	}
+	if root == nil {
+		return nil, err
+	}
+	return &Query{Root: root, Now: time.Now()}, nil
+}
+
+func (p *queryParser) peek() token {
+	return p.tokens[p.next]
+}
+
+func (p *queryParser) advance() token {
+	tok := p.tokens[p.next]
+	if tok.kind != tokenEOF {
+		p.next++
+	}
+	return tok
+}
+
+func (p *queryParser) errorAt(tok token, msg string) error {
+	return &QueryError{Query: p.input, Pos: tok.pos, Len: tok.len, Msg: msg}
+}
+
+// parseOr parses OR-separated terms. Inside a field group such as
+// assignee:(a OR b), field is the field the bare values belong to.
+func (p *queryParser) parseOr(field string) (QueryExpr, error) {
+	left, err := p.parseAnd(field)
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.advance()
+		right, err := p.parseAnd(field)
+		if err != nil {
+			return nil, err
+		}
+		left = &QueryOr{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses terms joined by AND or just whitespace
+func (p *queryParser) parseAnd(field string) (QueryExpr, error) {
+	left, err := p.parseUnary(field)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.advance()
+		case tokenWord, tokenString, tokenLParen, tokenNot, tokenMinus:
+		default:
+			return left, nil
+		}
+		right, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		left = &QueryAnd{Left: left, Right: right}
+	}
+}
+
+// parseUnary parses a term with any number of NOT or - prefixes
+func (p *queryParser) parseUnary(field string) (QueryExpr, error) {
+	if kind := p.peek().kind; kind == tokenNot || kind == tokenMinus {
+		p.advance()
+		expr, err := p.parseUnary(field)
+		if err != nil {
+			return nil, err
+		}
+		return &QueryNot{Expr: expr}, nil
+	}
+	return p.parsePrimary(field)
+}
+
+// parsePrimary parses a parenthesized group or a single term
+func (p *queryParser) parsePrimary(field string) (QueryExpr, error) {
+	tok := p.advance()
+	switch tok.kind {
+	case tokenLParen:
+		return p.parseGroup(tok, field)
+	case tokenString:
+		return p.newTerm(field, tok.text, tok)
+	case tokenWord:
+		if field != "" {
+			if name, _, ok := strings.Cut(tok.text, ":"); ok {
+				return nil, p.errorAt(tok, fmt.Sprintf("can't use %s: inside %s:(...)", name, field))
+			}
+			return p.newTerm(field, tok.text, tok)
+		}
+
+		name, value, ok := strings.Cut(tok.text, ":")
+		if !ok {
+			return p.newTerm("", tok.text, tok)
+		}
+		if name == "" {
+			return nil, p.errorAt(tok, "missing field name before :")
+		}
+		if err := p.checkField(name, tok); err != nil {
+			return nil, err
+		}
+		if value != "" {
+			return p.newTerm(name, value, tok)
+		}
+
+		// label:"needs review" or assignee:(a OR b)
+		switch next := p.advance(); next.kind {
+		case tokenString:
+			return p.newTerm(name, next.text, next)
+		case tokenLParen:
+			return p.parseGroup(next, name)
+		default:
+			return nil, p.errorAt(tok, fmt.Sprintf("missing value after %s:", name))
+		}
+	case tokenEOF:
+		return nil, p.errorAt(tok, "unexpected end of query")
+	default:
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.text))
+	}
+}
+
+// parseGroup parses the rest of a parenthesized group opened by open
+func (p *queryParser) parseGroup(open token, field string) (QueryExpr, error) {
+	if p.peek().kind == tokenRParen {
+		return nil, p.errorAt(p.peek(), "empty parentheses")
+	}
+	expr, err := p.parseOr(field)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenRParen {
+		return nil, p.errorAt(open, "unmatched (")
+	}
+	p.advance()
+	return expr, nil
+}
+
+// checkField checks a qualifier names a built-in or declared field
+func (p *queryParser) checkField(name string, tok token) error {
+	if containsString(queryFields, name) || p.cfg == nil || p.cfg.Field(name) != nil {
+		return nil
+	}
+	return &QueryError{Query: p.input, Pos: tok.pos, Len: len(name), Msg: fmt.Sprintf("unknown field %q (must be one of: %s, or a custom field)", name, strings.Join(queryFields, ", "))}
+}
+
+// newTerm builds a term from a field and its raw value, which may start with
+// a comparison operator, and checks the value
+func (p *queryParser) newTerm(field, raw string, tok token) (*QueryTerm, error) {
+	term := &QueryTerm{Field: field, Value: raw, Pos: tok.pos}
+	if field == "" {
+		return term, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if strings.HasPrefix(raw, op) {
+			term.Op, term.Value = op, raw[len(op):]
+			break
+		}
+	}
+	if term.Value == "" {
+		return nil, p.errorAt(tok, fmt.Sprintf("missing value for %s:", field))
+	}
+	if p.cfg != nil && !containsString(queryFields, field) {
+		term.field = p.cfg.Field(field)
+	}
+
+	bad := func(msg string) error {
+		return p.errorAt(tok, msg)
+	}
+	switch {
+	case containsString(timeFields, field) || term.field != nil && term.field.Type == FieldDate:
+		if m := relativeAgeRe.FindStringSubmatch(term.Value); m != nil {
+			if field != "created" && field != "updated" {
+				return nil, bad(fmt.Sprintf("relative dates like %s only apply to created and updated", term.Value))
+			}
+			n, _ := strconv.Atoi(m[1])
+			unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[2]]
+			term.age = time.Duration(n) * unit
+		} else if _, err := time.Parse(DateLayout, term.Value); err != nil {
+			return nil, bad(fmt.Sprintf("invalid date %q for %s (expected YYYY-MM-DD or an age like 7d)", term.Value, field))
+		}
+	case term.field != nil && term.field.Type == FieldInt:
+		n, err := strconv.Atoi(term.Value)
+		if err != nil {
+			return nil, bad(fmt.Sprintf("%s needs an integer, not %q", field, term.Value))
+		}
+		term.num = n
+	case term.Op != "" && term.Op != "=":
+		return nil, bad(fmt.Sprintf("%s can't be compared with %s", field, term.Op))
+	case field == "status" && p.cfg != nil && !containsString(p.cfg.Workflow.StateNames(), term.Value):
+		return nil, bad(fmt.Sprintf("unknown status %q (must be one of: %s)", term.Value, strings.Join(p.cfg.Workflow.StateNames(), ", ")))
+	}
+	return term, nil
+}
+
+// UsesField reports whether any term of the query qualifies the given field
+func (q *Query) UsesField(name string) bool {
+	var walk func(e QueryExpr) bool
+	walk = func(e QueryExpr) bool {
+		switch e := e.(type) {
+		case *QueryAnd:
+			return walk(e.Left) || walk(e.Right)
+		case *QueryOr:
+			return walk(e.Left) || walk(e.Right)
+		case *QueryNot:
+			return walk(e.Expr)
+		case *QueryTerm:
+			return e.Field == name
+		}
+		return false
+	}
+	return walk(q.Root)
+}
+
+// queryEnv is what a query is evaluated against
+type queryEnv struct {
+	issue  *Issue
+	dir    string
+	status string
+	now    time.Time
+	// text matches free text terms; nil means issue.ContainsText
+	text func(t *QueryTerm) bool
+}
+
+// Match reports whether an issue stored in dir, with the given workflow
+// status, matches the query
+func (q *Query) Match(issue *Issue, dir, status string) bool {
+	return q.Root.match(&queryEnv{issue: issue, dir: dir, status: status, now: q.Now})
+}
+
+func (e *QueryAnd) match(env *queryEnv) bool { return e.Left.match(env) && e.Right.match(env) }
+func (e *QueryOr) match(env *queryEnv) bool  { return e.Left.match(env) || e.Right.match(env) }
+func (e *QueryNot) match(env *queryEnv) bool { return !e.Expr.match(env) }
+
+func (t *QueryTerm) match(env *queryEnv) bool {
+	issue := env.issue
+	switch t.Field {
+	case "":
+		if env.text != nil {
+			return env.text(t)
+		}
+		// A trailing * asks for a prefix, which a substring match covers
+		return issue.ContainsText(strings.TrimSuffix(t.Value, "*"))
+	case "id":
+		return issue.ID == strings.TrimPrefix(t.Value, "#")
+	case "title":
+		return containsFold(issue.Title, t.Value)
+	case "body":
+		return containsFold(issue.Body, t.Value)
+	case "label":
+		return issue.HasLabel(t.Value)
+	case "assignee":
+		return issue.Assignee == t.Value
+	case "status":
+		switch t.Value {
+		case StateOpen:
+			return env.dir == OpenDir
+		case StateClosed:
+			return env.dir == ClosedDir
+		}
+		return env.status == t.Value
+	case "milestone":
+		return strings.EqualFold(issue.Milestone(), t.Value)
+	case "priority":
+		return issue.Priority() == t.Value
+	case "parent":
+		return issue.Parent == strings.TrimPrefix(t.Value, "#")
+	case "created":
+		return t.matchTime(issue.Created, env.now)
+	case "updated":
+		return t.matchTime(issue.Updated, env.now)
+	}
+
+	value, ok := issue.Field(t.Field)
+	if !ok || value == nil {
+		return false
+	}
+	switch {
+	case t.Field == DueField || t.field != nil && t.field.Type == FieldDate:
+		return compareOp(strings.Compare(FormatFieldValue(value), t.Value), t.Op)
+	case t.field != nil && t.field.Type == FieldInt:
+		n, err := strconv.Atoi(FormatFieldValue(value))
+		return err == nil && compareOp(cmp.Compare(n, t.num), t.Op)
+	}
+	return MatchFieldValue(value, t.Value)
+}
+
+// matchTime compares a timestamp against a date, or against the time a
+// relative age counts back to, in the same direction: updated:>7d matches
+// issues updated after 7 days ago, like updated:>2026-09-01 matches those
+// updated after that date, and updated:7d is the same as updated:>=7d
+func (t *QueryTerm) matchTime(ts time.Time, now time.Time) bool {
+	if ts.IsZero() {
+		return false
+	}
+	if t.age > 0 {
+		op := t.Op
+		if op == "" || op == "=" {
+			op = ">="
+		}
+		return compareOp(ts.Compare(now.Add(-t.age)), op)
+	}
+	return compareOp(strings.Compare(ts.Local().Format(DateLayout), t.Value), t.Op)
+}
+
+// compareOp applies a comparison operator to the result of a comparison
+func compareOp(c int, op string) bool {
+	switch op {
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	}
+	return c == 0
+}
+
+// ContainsText reports whether the title, body or any comment contains text,
+// ignoring case
+func (i *Issue) ContainsText(text string) bool {
+	if containsFold(i.Title, text) || containsFold(i.Body, text) {
+		return true
+	}
+	for _, comment := range i.Comments {
+		if containsFold(comment.Body, text) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
@@ -0,0 +1,155 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"label:bug", "label:bug"},
+		{"label:bug -label:wontfix", "(label:bug AND NOT label:wontfix)"},
+		{"a OR b c", "(a OR (b AND c))"},
+		{"NOT (a OR b)", "NOT (a OR b)"},
+		{`title:"needs review" timeout`, `(title:"needs review" AND timeout)`},
+		{"assignee:(jonghun OR mina)", "(assignee:jonghun OR assignee:mina)"},
+		{"created:>2026-09-01 updated:<=7d", "(created:>2026-09-01 AND updated:<=7d)"},
+		{"wont-fix", "wont-fix"},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query, &Config{})
+		if err != nil {
+			t.Errorf("ParseQuery(%q) error = %v", tt.query, err)
+			continue
+		}
+		if got := q.String(); got != tt.want {
+			t.Errorf("ParseQuery(%q) = %s, want %s", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	tests := []struct {
+		query   string
+		pos     int
+		message string
+	}{
+		{"", 0, "empty query"},
+		{"label:bug AND lable:x", 14, "unknown field"},
+		{"label:bug AND", 13, "unexpected end"},
+		{"(label:bug", 0, "unmatched ("},
+		{"label:bug)", 9, "unmatched )"},
+		{`title:"oops`, 6, "unterminated quote"},
+		{"created:>yesterday", 0, "invalid date"},
+		{"label:>bug", 0, "can't be compared"},
+		{"status:doing", 0, "unknown status"},
+		{"assignee:(a OR label:b)", 15, "inside assignee"},
+		{"label:", 0, "missing value"},
+	}
+
+	for _, tt := range tests {
+		_, err := ParseQuery(tt.query, &Config{})
+		var qerr *QueryError
+		if !errors.As(err, &qerr) {
+			t.Errorf("ParseQuery(%q) error = %v, want a QueryError", tt.query, err)
+			continue
+		}
+		if qerr.Pos != tt.pos || !strings.Contains(qerr.Msg, tt.message) {
+			t.Errorf("ParseQuery(%q) error at %d %q, want %q at %d", tt.query, qerr.Pos, qerr.Msg, tt.message, tt.pos)
+		}
+	}
+
+	// The message points at the bad token
+	_, err := ParseQuery("label:bug AND lable:x", &Config{})
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 3 || lines[2] != "  "+strings.Repeat(" ", 14)+"^^^^^" {
+		t.Errorf("error = %q, want a marker under lable", err.Error())
+	}
+}
+
+func TestParseSearch(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"label:bug redis", "(label:bug AND redis)"},
+		{"TypeError: cannot", "(TypeError AND cannot)"},
+		{"https://example.com/login", "https://example.com/login"},
+		{"fix (urgent", "(fix AND urgent)"},
+	}
+	for _, tt := range tests {
+		q, err := ParseSearch(tt.input, &Config{})
+		if err != nil {
+			t.Errorf("ParseSearch(%q) error = %v", tt.input, err)
+			continue
+		}
+		if got := q.String(); got != tt.want {
+			t.Errorf("ParseSearch(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+	if _, err := ParseSearch(" ( ", &Config{}); err == nil {
+		t.Error("ParseSearch() without any words should fail")
+	}
+}
+
+func TestQueryMatch(t *testing.T) {
+	cfg, err := ParseConfig([]byte("fields:\n  - name: estimate\n    type: int\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	issue := &Issue{
+		ID:       "007",
+		Title:    "Login times out",
+		Body:     "Happens behind the proxy.",
+		Assignee: "mina",
+		Labels:   []string{"bug", "backend"},
+		Created:  time.Date(2026, 9, 20, 9, 0, 0, 0, time.UTC),
+		Updated:  now.Add(-48 * time.Hour),
+		Comments: []Comment{{Author: "jonghun", Body: "Seen on staging too"}},
+	}
+	issue.SetField("estimate", 3)
+	issue.SetField(DueField, "2026-10-30")
+
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"label:bug AND -label:wontfix AND assignee:(jonghun OR mina) AND created:>2026-09-01 AND updated:>7d", true},
+		{"label:bug -label:backend", false},
+		{"proxy", true},
+		{"staging", true},
+		{`"times out"`, true},
+		{"title:proxy", false},
+		{"updated:<7d", false},
+		{"updated:1d", false},
+		{"updated:>=2d", true},
+		{"created:<2w", true},
+		{"created:>2w", false},
+		{"created:>2026-10-04", false},
+		{"created:2026-09-20", true},
+		{"due:<2026-11-01 estimate:>=3", true},
+		{"estimate:<3", false},
+		{"status:open", true},
+		{"status:closed", false},
+		{"id:#007", true},
+		{"milestone:v1 OR priority:high", false},
+	}
+
+	for _, tt := range tests {
+		q, err := ParseQuery(tt.query, cfg)
+		if err != nil {
+			t.Fatalf("ParseQuery(%q) error = %v", tt.query, err)
+		}
+		q.Now = now
+		if got := q.Match(issue, OpenDir, StateOpen); got != tt.want {
+			t.Errorf("Match(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+}
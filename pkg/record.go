@@ -0,0 +1,120 @@
+package pkg
+
+import (
+	"sort"
+	"time"
+)
+
+// IssueRecord is the stable machine-readable form of an issue, used by
+// --format json/yaml/ndjson and --template. Fields are only ever added to it,
+// never renamed or removed.
+type IssueRecord struct {
+	ID       string   `json:"id" yaml:"id"`
+	Title    string   `json:"title" yaml:"title"`
+	Status   string   `json:"status" yaml:"status"` // workflow state (open, closed or a custom state)
+	Closed   bool     `json:"closed" yaml:"closed"` // true when the issue is in closed/
+	Assignee string   `json:"assignee" yaml:"assignee"`
+	Labels   []string `json:"labels" yaml:"labels"`
+	Created  string   `json:"created" yaml:"created"` // RFC 3339
+	Updated  string   `json:"updated" yaml:"updated"` // RFC 3339
+	Path     string   `json:"path" yaml:"path"`
+	Parent   string   `json:"parent" yaml:"parent"` // parent issue ID, "" if none
+	// Fields holds every other frontmatter key (custom fields included).
+	// Dates are rendered as YYYY-MM-DD, timestamps as RFC 3339.
+	Fields map[string]interface{} `json:"fields" yaml:"fields"`
+	// Links maps a link type (blocks, depends-on, ...) to the linked IDs
+	// stored on this issue; links stored on the other issue aren't included
+	Links map[string][]string `json:"links" yaml:"links"`
+	Body  string              `json:"body" yaml:"body"`
+	// Tasks lists the Markdown task list items of the body, in order
+	Tasks []Task `json:"tasks" yaml:"tasks"`
+	// Comments is the discussion thread, oldest first
+	Comments []Comment `json:"comments" yaml:"comments"`
+}
+
+// NewIssueRecord builds the record for an issue stored in dir with the given workflow status
+func NewIssueRecord(issue *Issue, dir, status string) IssueRecord {
+	labels := issue.Labels
+	if labels == nil {
+		labels = []string{}
+	}
+
+	fields := make(map[string]interface{}, len(issue.Extra))
+	for key, value := range issue.Extra {
+		fields[key] = recordValue(value)
+	}
+
+	links := make(map[string][]string)
+	for _, t := range LinkTypes {
+		if ids := issue.Links(t); len(ids) > 0 {
+			links[string(t)] = ids
+		}
+	}
+
+	tasks := issue.Tasks()
+	if tasks == nil {
+		tasks = []Task{}
+	}
+
+	comments := issue.Comments
+	if comments == nil {
+		comments = []Comment{}
+	}
+
+	return IssueRecord{
+		ID:       issue.ID,
+		Title:    issue.Title,
+		Status:   status,
+		Closed:   dir == ClosedDir,
+		Assignee: issue.Assignee,
+		Labels:   labels,
+		Created:  formatRecordTime(issue.Created),
+		Updated:  formatRecordTime(issue.Updated),
+		Path:     issue.Path,
+		Parent:   issue.Parent,
+		Fields:   fields,
+		Links:    links,
+		Body:     issue.Body,
+		Tasks:    tasks,
+		Comments: comments,
+	}
+}
+
+// FieldNames returns the record's custom field names, sorted
+func (r IssueRecord) FieldNames() []string {
+	names := make([]string, 0, len(r.Fields))
+	for name := range r.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatRecordTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// recordValue converts a decoded frontmatter value into plain JSON-friendly types
+func recordValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case time.Time:
+		return FormatFieldValue(v)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = recordValue(item)
+		}
+		return items
+	case map[string]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			m[key] = recordValue(item)
+		}
+		return m
+	default:
+		return v
+	}
+}
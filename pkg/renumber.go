@@ -0,0 +1,410 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrAmbiguousReferences is wrapped by the error Renumber returns when
+// references to a duplicated ID could mean either issue and there's no
+// RenumberOptions.Resolve to decide
+var ErrAmbiguousReferences = errors.New("ambiguous references to duplicate IDs")
+
+// Renumbering records an issue whose ID was changed by Renumber
+type Renumbering struct {
+	OldID   string
+	NewID   string
+	Title   string
+	Dir     string
+	OldName string // store-relative filename before renumbering
+	NewName string // store-relative filename after renumbering
+	// Collision is true when the issue shared its ID with another issue,
+	// rather than having a provisional ID
+	Collision bool
+	// References lists the issues whose references to #OldID (in the body,
+	// parent or links) were rewritten to #NewID
+	References []string
+	// Ambiguous lists, for a collision, the issues referencing #OldID that
+	// could mean either issue sharing it. They are rewritten only when
+	// RenumberOptions.Resolve says so, and then also listed in References.
+	Ambiguous []string
+}
+
+// RenumberOptions controls Renumber
+type RenumberOptions struct {
+	// DryRun computes the renumbering without changing any files
+	DryRun bool
+	// Resolve is asked whether the references to a duplicated ID in the
+	// issue referrer (its ID after renumbering, as listed in Ambiguous) mean
+	// the renumbered issue (true) or the one keeping the ID (false). When
+	// nil, Renumber fails with ErrAmbiguousReferences rather than guess.
+	Resolve func(change Renumbering, referrer string) bool
+}
+
+// issueFile is an issue together with the file it was read from
+type issueFile struct {
+	id    string // ID from the filename
+	name  string // store-relative filename
+	dir   string
+	issue *Issue
+}
+
+// Renumber repairs issue IDs: every issue with a provisional ID, and every
+// issue but the oldest among those sharing an ID (for example after merging
+// two branches that each created 007), gets the next free ID from the
+// counter. Files are renamed, the id frontmatter field is rewritten and #ID
+// references in issue bodies and links are updated. Issues are renumbered in creation order.
+//
+// References to a duplicated ID are rewritten when they are the renumbered
+// issue's own. References from an issue last updated before the renumbered
+// issue was created can only mean the oldest one and are left alone; any
+// other reference is ambiguous and goes to RenumberOptions.Resolve.
+func (r *Repository) Renumber(opts RenumberOptions) ([]Renumbering, error) {
+	var changes []Renumbering
+	err := r.withLock(func() error {
+		var err error
+		changes, err = r.renumber(opts)
+		return err
+	})
+	return changes, err
+}
+
+// renumber implements Renumber; the caller must hold the store lock
+func (r *Repository) renumber(opts RenumberOptions) ([]Renumbering, error) {
+	files, err := r.issueFiles()
+	if err != nil {
+		return nil, err
+	}
+
+	// Group files by ID to find collisions
+	byID := make(map[string][]*issueFile)
+	for _, f := range files {
+		byID[f.id] = append(byID[f.id], f)
+	}
+
+	var renamed []*issueFile
+	collision := make(map[*issueFile]bool)
+	for _, group := range byID {
+		sortByCreated(group)
+		if IsProvisionalID(group[0].id) {
+			renamed = append(renamed, group...)
+			continue
+		}
+		for _, f := range group[1:] {
+			collision[f] = true
+			renamed = append(renamed, f)
+		}
+	}
+	if len(renamed) == 0 {
+		return nil, nil
+	}
+	sortByCreated(renamed)
+
+	// Allocate fresh IDs from the counter, skipping any ID in use, including
+	// those of files that don't parse
+	counter, err := r.readCounter()
+	if err != nil {
+		return nil, err
+	}
+	ids, err := r.issueIDs()
+	if err != nil {
+		return nil, err
+	}
+	used := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		used[id] = true
+	}
+
+	changes := make([]Renumbering, len(renamed))
+	newIDs := make(map[*issueFile]string, len(renamed))
+	for i, f := range renamed {
+		for used[FormatID(counter)] {
+			counter++
+		}
+		newID := FormatID(counter)
+		used[newID] = true
+		counter++
+
+		newIDs[f] = newID
+		changes[i] = Renumbering{
+			OldID:     f.id,
+			NewID:     newID,
+			Title:     f.issue.Title,
+			Dir:       f.dir,
+			OldName:   f.name,
+			NewName:   path.Join(f.dir, newID+strings.TrimPrefix(path.Base(f.name), f.id)),
+			Collision: collision[f],
+		}
+	}
+
+	// displayID is the ID an issue file has after renumbering
+	displayID := func(f *issueFile) string {
+		if newIDs[f] != "" {
+			return newIDs[f]
+		}
+		return f.id
+	}
+
+	// References to provisional IDs are unambiguous and rewritten everywhere;
+	// references to duplicated IDs are rewritten per file
+	rewrites := make(map[string]string)
+	fileRewrites := make(map[*issueFile]map[string]string)
+	rewriteIn := func(f *issueFile, oldID, newID string) {
+		if fileRewrites[f] == nil {
+			fileRewrites[f] = make(map[string]string)
+		}
+		fileRewrites[f][oldID] = newID
+	}
+	for i, change := range changes {
+		if change.Collision {
+			// An issue's mentions of its own ID follow it
+			rewriteIn(renamed[i], change.OldID, change.NewID)
+		} else {
+			rewrites[change.OldID] = change.NewID
+		}
+	}
+
+	var ambiguous []string
+	for i := range changes {
+		change := &changes[i]
+		created := renamed[i].issue.Created
+		for _, f := range files {
+			if f.id == change.OldID || !f.issue.references(change.OldID) {
+				continue
+			}
+			if !change.Collision {
+				change.References = append(change.References, displayID(f))
+				continue
+			}
+			if _, done := fileRewrites[f][change.OldID]; done {
+				continue // attributed to another issue sharing the ID
+			}
+			if !f.issue.Updated.IsZero() && f.issue.Updated.Before(created) {
+				continue // written before the renumbered issue existed
+			}
+
+			change.Ambiguous = append(change.Ambiguous, displayID(f))
+			switch {
+			case opts.DryRun:
+			case opts.Resolve == nil:
+				ambiguous = append(ambiguous, fmt.Sprintf("#%s in #%s", change.OldID, displayID(f)))
+			case opts.Resolve(*change, displayID(f)):
+				rewriteIn(f, change.OldID, change.NewID)
+				change.References = append(change.References, displayID(f))
+			}
+		}
+	}
+	if len(ambiguous) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrAmbiguousReferences, strings.Join(ambiguous, ", "))
+	}
+
+	if opts.DryRun {
+		return changes, nil
+	}
+
+	for _, f := range files {
+		newID := newIDs[f]
+		fileMap := rewrites
+		if len(fileRewrites[f]) > 0 {
+			fileMap = make(map[string]string, len(rewrites)+len(fileRewrites[f]))
+			for oldID, id := range rewrites {
+				fileMap[oldID] = id
+			}
+			for oldID, id := range fileRewrites[f] {
+				fileMap[oldID] = id
+			}
+		}
+		relinked := f.issue.rewriteLinks(fileMap)
+		if newID == "" && !relinked && !mentionsAny(f.issue.Body, fileMap) {
+			continue
+		}
+
+		if newID != "" {
+			f.issue.ID = newID
+			f.issue.Updated = time.Now()
+		}
+		content, err := SerializeIssue(f.issue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to serialize issue: %w", err)
+		}
+		content = rewriteReferences(content, fileMap)
+
+		name := f.name
+		if newID != "" {
+			name = path.Join(f.dir, newID+strings.TrimPrefix(path.Base(f.name), f.id))
+		}
+		if err := r.store.WriteFile(name, []byte(content)); err != nil {
+			return nil, fmt.Errorf("failed to write issue file: %w", err)
+		}
+		if name != f.name {
+			if err := r.store.Remove(f.name); err != nil {
+				return nil, fmt.Errorf("failed to remove %s: %w", f.name, err)
+			}
+		}
+	}
+
+	if err := r.writeCounter(counter); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// issueFiles reads every parsable issue file in open/ and closed/
+func (r *Repository) issueFiles() ([]*issueFile, error) {
+	var files []*issueFile
+	for _, dir := range []string{OpenDir, ClosedDir} {
+		names, err := r.store.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, name := range names {
+			id, ok := IssueIDFromFilename(name)
+			if !ok {
+				continue
+			}
+			name = path.Join(dir, name)
+			data, err := r.store.ReadFile(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read issue file: %w", err)
+			}
+			issue, err := ParseMarkdown(string(data))
+			if err != nil {
+				continue // Skip files we can't parse
+			}
+			files = append(files, &issueFile{id: id, name: name, dir: dir, issue: issue})
+		}
+	}
+	return files, nil
+}
+
+// readCounter returns the value stored in the counter file
+func (r *Repository) readCounter() (int, error) {
+	data, err := r.store.ReadFile(CounterFile)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read counter: %w", err)
+	}
+
+	n, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil {
+		return 0, fmt.Errorf("invalid counter value: %w", err)
+	}
+	return n, nil
+}
+
+// sortByCreated orders issue files by creation time, then filename
+func sortByCreated(files []*issueFile) {
+	sort.SliceStable(files, func(i, j int) bool {
+		a, b := files[i].issue.Created, files[j].issue.Created
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return files[i].name < files[j].name
+	})
+}
+
+// referenceAt reports whether text[i:] starts a #id reference that isn't
+// part of a longer ID (#001 doesn't match #0012 or #001.5)
+func referenceAt(text string, i int, id string) bool {
+	ref := "#" + id
+	if !strings.HasPrefix(text[i:], ref) {
+		return false
+	}
+	rest := text[i+len(ref):]
+	if rest == "" {
+		return true
+	}
+	if isIDChar(rest[0]) {
+		return false
+	}
+	return rest[0] != '.' || len(rest) == 1 || !isIDChar(rest[1])
+}
+
+func isIDChar(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// containsReference reports whether text mentions #id
+func containsReference(text, id string) bool {
+	for i := strings.Index(text, "#"); i >= 0; {
+		if referenceAt(text, i, id) {
+			return true
+		}
+		next := strings.Index(text[i+1:], "#")
+		if next < 0 {
+			break
+		}
+		i += next + 1
+	}
+	return false
+}
+
+// references reports whether an issue mentions #id in its body or refers to
+// id as its parent or in a link
+func (i *Issue) references(id string) bool {
+	if i.Parent == id || containsReference(i.Body, id) {
+		return true
+	}
+	for _, t := range LinkTypes {
+		for _, target := range i.Links(t) {
+			if target == id {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// mentionsAny reports whether text mentions any of the IDs being rewritten
+func mentionsAny(text string, rewrites map[string]string) bool {
+	for id := range rewrites {
+		if containsReference(text, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// rewriteReferences replaces #old with #new in the markdown after the frontmatter
+func rewriteReferences(content string, rewrites map[string]string) string {
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 || len(rewrites) == 0 {
+		return content
+	}
+
+	body := parts[2]
+	var out strings.Builder
+	for i := 0; i < len(body); {
+		if body[i] == '#' {
+			if replaced := rewriteAt(body, i, rewrites); replaced != "" {
+				out.WriteString("#" + rewrites[replaced])
+				i += len(replaced) + 1
+				continue
+			}
+		}
+		out.WriteByte(body[i])
+		i++
+	}
+
+	return parts[0] + "---" + parts[1] + "---" + out.String()
+}
+
+// rewriteAt returns the old ID referenced at text[i:], if it's being rewritten
+func rewriteAt(text string, i int, rewrites map[string]string) string {
+	for old := range rewrites {
+		if referenceAt(text, i, old) {
+			return old
+		}
+	}
+	return ""
+}
+
+// Renumber repairs provisional and colliding issue IDs in the resolved .issues directory
+func Renumber(opts RenumberOptions) ([]Renumbering, error) {
+	return DefaultRepository().Renumber(opts)
+}
@@ -0,0 +1,165 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// writeIssueFile writes a raw issue file, as a merge would
+func writeIssueFile(t *testing.T, repo *Repository, name, id, created, title, body string) {
+	t.Helper()
+
+	content := "---\nid: \"" + id + "\"\nassignee: \"\"\nlabels: []\ncreated: " + created +
+		"\nupdated: " + created + "\n---\n\n# " + title + "\n\n" + body + "\n"
+	if err := repo.Store().WriteFile(name, []byte(content)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newCollisionRepo(t *testing.T) *Repository {
+	t.Helper()
+
+	repo := newIDTestRepo(t, "")
+	writeIssueFile(t, repo, "open/007-login.md", "007", "2026-09-01T10:00:00Z", "Login", "Needs #008.")
+	writeIssueFile(t, repo, "closed/007-export.md", "007", "2026-09-02T10:00:00Z", "Export", "Tracked as #007.")
+	writeIssueFile(t, repo, "open/008-logout.md", "008", "2026-09-01T11:00:00Z", "Logout", "After #topic.1 and #007, not #0071.")
+	writeIssueFile(t, repo, "open/005-audit.md", "005", "2026-09-02T12:00:00Z", "Audit", "Follow-up to #007.")
+	writeIssueFile(t, repo, "open/topic.1-search.md", "topic.1", "2026-09-03T10:00:00Z", "Search", "Body")
+	if err := repo.Store().WriteFile(CounterFile, []byte("9\n")); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestRenumberRepairsCollisions(t *testing.T) {
+	repo := newCollisionRepo(t)
+
+	var asked []string
+	changes, err := repo.Renumber(RenumberOptions{Resolve: func(change Renumbering, referrer string) bool {
+		asked = append(asked, change.OldID+"->"+change.NewID+" in "+referrer)
+		return true
+	}})
+	if err != nil {
+		t.Fatalf("Renumber() error = %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("Renumber() = %+v, want 2 changes", changes)
+	}
+	if strings.Join(asked, ",") != "007->009 in 005" {
+		t.Errorf("Resolve asked about %v, want only 005", asked)
+	}
+
+	// The newer duplicate is renumbered, the older one keeps its ID
+	export := changes[0]
+	if export.OldID != "007" || export.NewID != "009" || !export.Collision || export.NewName != "closed/009-export.md" {
+		t.Errorf("duplicate change = %+v", export)
+	}
+	if strings.Join(export.References, ",") != "005" || strings.Join(export.Ambiguous, ",") != "005" {
+		t.Errorf("duplicate references = %v, ambiguous = %v; want [005]", export.References, export.Ambiguous)
+	}
+	search := changes[1]
+	if search.OldID != "topic.1" || search.NewID != "010" || search.Collision {
+		t.Errorf("provisional change = %+v", search)
+	}
+
+	if issue, dir, err := repo.LoadIssue("007"); err != nil || issue.Title != "Login" || dir != OpenDir {
+		t.Errorf("LoadIssue(007) = %v, %s, %v; want Login", issue, dir, err)
+	}
+	if issue, dir, err := repo.LoadIssue("009"); err != nil || issue.Title != "Export" || issue.ID != "009" || dir != ClosedDir {
+		t.Errorf("LoadIssue(009) = %v, %s, %v; want Export", issue, dir, err)
+	} else if issue.Body != "Tracked as #009." {
+		t.Errorf("an issue's references to itself should follow it: %q", issue.Body)
+	}
+
+	// Provisional references are rewritten; references written before the
+	// duplicate existed and longer IDs are left alone
+	logout, _, err := repo.LoadIssue("008")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if logout.Body != "After #010 and #007, not #0071." {
+		t.Errorf("references = %q", logout.Body)
+	}
+	if audit, _, err := repo.LoadIssue("005"); err != nil || audit.Body != "Follow-up to #009." {
+		t.Errorf("resolved reference = %q, %v", audit.Body, err)
+	}
+
+	if next, err := repo.GetNextID(); err != nil || next != 11 {
+		t.Errorf("GetNextID() = %d, %v; want 11", next, err)
+	}
+}
+
+func TestRenumberDryRun(t *testing.T) {
+	repo := newCollisionRepo(t)
+
+	before, err := repo.Store().ReadDir(OpenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := repo.Renumber(RenumberOptions{DryRun: true})
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("Renumber(dry run) = %+v, %v", changes, err)
+	}
+
+	after, err := repo.Store().ReadDir(OpenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(before, ",") != strings.Join(after, ",") {
+		t.Errorf("dry run changed files: %v -> %v", before, after)
+	}
+	if counter, err := repo.readCounter(); err != nil || counter != 9 {
+		t.Errorf("dry run changed counter to %d (%v)", counter, err)
+	}
+}
+
+func TestRenumberAmbiguousReferences(t *testing.T) {
+	repo := newCollisionRepo(t)
+
+	// Without Resolve, Renumber refuses to guess
+	_, err := repo.Renumber(RenumberOptions{})
+	if !errors.Is(err, ErrAmbiguousReferences) || !strings.Contains(err.Error(), "#007 in #005") {
+		t.Fatalf("Renumber() error = %v, want ErrAmbiguousReferences", err)
+	}
+	if _, err := repo.Store().ReadFile("closed/007-export.md"); err != nil {
+		t.Errorf("a failed renumber changed files: %v", err)
+	}
+
+	// References resolved to the issue keeping the ID are left alone
+	changes, err := repo.Renumber(RenumberOptions{Resolve: func(Renumbering, string) bool { return false }})
+	if err != nil {
+		t.Fatalf("Renumber() error = %v", err)
+	}
+	if len(changes[0].References) != 0 || strings.Join(changes[0].Ambiguous, ",") != "005" {
+		t.Errorf("references = %v, ambiguous = %v", changes[0].References, changes[0].Ambiguous)
+	}
+	if audit, _, err := repo.LoadIssue("005"); err != nil || audit.Body != "Follow-up to #007." {
+		t.Errorf("reference = %q, %v; want it unchanged", audit.Body, err)
+	}
+}
+
+func TestRenumberSkipsIDsOfUnparsableFiles(t *testing.T) {
+	repo := newCollisionRepo(t)
+	if err := repo.Store().WriteFile("open/009-broken.md", []byte("---\nid: [\n---\n")); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := repo.Renumber(RenumberOptions{Resolve: func(Renumbering, string) bool { return false }})
+	if err != nil {
+		t.Fatalf("Renumber() error = %v", err)
+	}
+	if changes[0].NewID != "010" {
+		t.Errorf("duplicate renumbered to %s, want 010 (009 is taken by an unparsable file)", changes[0].NewID)
+	}
+}
+
+func TestRewriteReferences(t *testing.T) {
+	content := "---\nid: \"#001\"\n---\n\n# See #001\n\n#001, #001. #0011 #001.5 (#001)\n"
+	got := rewriteReferences(content, map[string]string{"001": "042"})
+	want := "---\nid: \"#001\"\n---\n\n# See #042\n\n#042, #042. #0011 #001.5 (#042)\n"
+	if got != want {
+		t.Errorf("rewriteReferences() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,567 @@
+package pkg
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultTemplate = `---
+id: ""
+assignee: ""
+labels: []
+created:
+updated:
+---
+
+# Issue Title
+
+## Description
+
+Describe the issue here...
+
+## Requirements
+
+- Requirement 1
+- Requirement 2
+
+## Success Criteria
+
+- [ ] Criterion 1
+- [ ] Criterion 2
+`
+
+// ErrNotFound is wrapped by the error returned for an issue ID that matches no issue
+var ErrNotFound = errors.New("not found")
+
+// Repository is an issue store rooted at an explicit location.
+// Unlike the package-level functions, which operate on the .issues directory
+// discovered from the working directory, a Repository can be used as a library,
+// against several stores in one process, and from parallel tests.
+type Repository struct {
+	store Store
+}
+
+// NewRepository returns a Repository backed by the given Store
+func NewRepository(store Store) *Repository {
+	return &Repository{store: store}
+}
+
+// OpenRepository returns a Repository backed by the .issues directory at root
+func OpenRepository(root string) *Repository {
+	return NewRepository(NewFSStore(root))
+}
+
+// Store returns the Store backing the repository
+func (r *Repository) Store() Store {
+	return r.store
+}
+
+// Path returns the user-facing location of the repository root
+func (r *Repository) Path() string {
+	return r.store.Path(".")
+}
+
+// Exists checks if the repository has been initialized
+func (r *Repository) Exists() bool {
+	return r.store.Exists(".")
+}
+
+// withLock runs fn while holding the store's exclusive lock
+func (r *Repository) withLock(fn func() error) error {
+	unlock, err := r.store.Lock()
+	if err != nil {
+		return fmt.Errorf("failed to lock issue store: %w", err)
+	}
+	defer unlock()
+
+	// Repositories initialized before the lock existed don't ignore it yet;
+	// a missing entry would get the lock file committed with the issues
+	_ = r.ensureGitignore()
+
+	return fn()
+}
+
+// gitignoreEntries are the files in the repository root kept out of git
+var gitignoreEntries = []string{LockFile, CacheDir + "/"}
+
+// ensureGitignore adds any missing gitignoreEntries to .gitignore, keeping
+// the lines already there
+func (r *Repository) ensureGitignore() error {
+	data, err := r.store.ReadFile(".gitignore")
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	present := make(map[string]bool)
+	for _, line := range strings.Split(string(data), "\n") {
+		present[strings.TrimSpace(line)] = true
+	}
+
+	content := string(data)
+	changed := false
+	for _, entry := range gitignoreEntries {
+		if present[entry] {
+			continue
+		}
+		if content != "" && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+		content += entry + "\n"
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return r.store.WriteFile(".gitignore", []byte(content))
+}
+
+// Initialize creates the directory structure, counter and template
+func (r *Repository) Initialize() error {
+	// Create main directory
+	if err := r.store.MkdirAll("."); err != nil {
+		return fmt.Errorf("failed to create %s directory: %w", r.Path(), err)
+	}
+
+	// Create open and closed subdirectories, with .keep files to ensure they are tracked in git
+	for _, dir := range []string{OpenDir, ClosedDir} {
+		if err := r.store.MkdirAll(dir); err != nil {
+			return fmt.Errorf("failed to create %s directory: %w", r.store.Path(dir), err)
+		}
+
+		keepName := path.Join(dir, ".keep")
+		if !r.store.Exists(keepName) {
+			if err := r.store.WriteFile(keepName, []byte("")); err != nil {
+				return fmt.Errorf("failed to create .keep file in %s directory: %w", dir, err)
+			}
+		}
+	}
+
+	// Initialize counter file
+	if !r.store.Exists(CounterFile) {
+		if err := r.store.WriteFile(CounterFile, []byte("1\n")); err != nil {
+			return fmt.Errorf("failed to create counter file: %w", err)
+		}
+	}
+
+	// Keep the lock file and caches out of git
+	if err := r.ensureGitignore(); err != nil {
+		return fmt.Errorf("failed to create .gitignore: %w", err)
+	}
+
+	// Create template file
+	if !r.store.Exists(TemplateFile) {
+		if err := r.store.WriteFile(TemplateFile, []byte(defaultTemplate)); err != nil {
+			return fmt.Errorf("failed to create template file: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// GetNextID reads and increments the counter, skipping any IDs that already exist.
+// The store is locked for the duration so concurrent creators get distinct IDs.
+func (r *Repository) GetNextID() (int, error) {
+	var id int
+	err := r.withLock(func() error {
+		var err error
+		id, err = r.nextID()
+		return err
+	})
+	return id, err
+}
+
+// nextID implements GetNextID; the caller must hold the store lock
+func (r *Repository) nextID() (int, error) {
+	// Read current counter value
+	currentID, err := r.readCounter()
+	if err != nil {
+		return 0, err
+	}
+
+	// Find the next available ID by checking if current ID exists
+	availableID := currentID
+	for {
+		formattedID := FormatID(availableID)
+		_, _, err := r.findIssue(formattedID)
+		if err != nil {
+			// ID not found, so it's available
+			break
+		}
+		// ID exists (in either open or closed), try next one
+		availableID++
+	}
+
+	// Write the next ID after the one we're returning
+	nextID := availableID + 1
+	if err := r.writeCounter(nextID); err != nil {
+		return 0, err
+	}
+
+	return availableID, nil
+}
+
+// SaveIssue writes an issue to the specified directory (open or closed)
+func (r *Repository) SaveIssue(issue *Issue, dir string) error {
+	return r.withLock(func() error {
+		return r.saveIssue(issue, dir)
+	})
+}
+
+// saveIssue implements SaveIssue; the caller must hold the store lock
+func (r *Repository) saveIssue(issue *Issue, dir string) error {
+	var name string
+
+	// If the issue already exists in the target directory, preserve its existing filename
+	if existingName, existingDir, err := r.findIssue(issue.ID); err == nil {
+		if existingDir != dir {
+			return fmt.Errorf("issue %s exists in %s directory, cannot save to %s", issue.ID, existingDir, dir)
+		}
+		name = existingName
+	} else {
+		// Generate a new filename only when the issue doesn't exist yet
+		slug := GenerateSlug(issue.Title)
+		name = path.Join(dir, fmt.Sprintf("%s-%s.md", issue.ID, slug))
+	}
+
+	// Serialize issue
+	content, err := SerializeIssue(issue)
+	if err != nil {
+		return fmt.Errorf("failed to serialize issue: %w", err)
+	}
+
+	// Write to file
+	if err := r.store.WriteFile(name, []byte(content)); err != nil {
+		return fmt.Errorf("failed to write issue file: %w", err)
+	}
+	issue.Path = r.store.Path(name)
+	issue.Version = ContentVersion([]byte(content))
+
+	return nil
+}
+
+// LoadIssue reads an issue by ID (searches both open/ and closed/)
+func (r *Repository) LoadIssue(id string) (*Issue, string, error) {
+	// Try to find the issue file
+	name, dir, err := r.resolveIssue(id)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Read file
+	data, err := r.store.ReadFile(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read issue file: %w", err)
+	}
+
+	// Parse issue
+	issue, err := ParseMarkdown(string(data))
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to parse issue: %w", err)
+	}
+	issue.Path = r.store.Path(name)
+	issue.Version = ContentVersion(data)
+
+	return issue, dir, nil
+}
+
+// MoveIssue moves an issue file between directories and updates the timestamp.
+// Moving to closed/ or open/ is a transition to the built-in closed or open
+// state, so it must be allowed by the workflow in config.yaml.
+func (r *Repository) MoveIssue(id string, fromDir, toDir string) error {
+	return r.withLock(func() error {
+		return r.moveIssue(id, fromDir, toDir)
+	})
+}
+
+// moveIssue implements MoveIssue; the caller must hold the store lock
+func (r *Repository) moveIssue(id string, fromDir, toDir string) error {
+	// Find the issue file
+	oldName, currentDir, err := r.resolveIssue(id)
+	if err != nil {
+		return err
+	}
+
+	// Verify it's in the expected source directory
+	if currentDir != fromDir {
+		return fmt.Errorf("issue %s is in %s, not %s", id, currentDir, fromDir)
+	}
+
+	data, err := r.store.ReadFile(oldName)
+	if err != nil {
+		return fmt.Errorf("failed to read issue file: %w", err)
+	}
+
+	issue, err := ParseMarkdown(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse issue: %w", err)
+	}
+
+	cfg, err := r.LoadConfig()
+	if err != nil {
+		return err
+	}
+	to := StateOpen
+	if toDir == ClosedDir {
+		to = StateClosed
+	}
+	if from := cfg.Workflow.StatusOf(issue, currentDir); from != to {
+		if err := cfg.Workflow.checkTransition(id, from, to); err != nil {
+			return err
+		}
+	}
+
+	issue.Updated = time.Now()
+
+	// A workflow state belonging to the other directory no longer applies
+	if issue.Status != "" {
+		if stateDir, err := cfg.Workflow.DirFor(issue.Status); err != nil || stateDir != toDir {
+			issue.Status = ""
+		}
+	}
+
+	return r.writeIssue(issue, oldName, toDir)
+}
+
+// writeIssue writes issue to dir under the file name it has at name, the file
+// it was read from, and removes name when dir is another directory. The new
+// file is written before the old one is removed, so the issue is never left
+// half moved. The caller must hold the store lock.
+func (r *Repository) writeIssue(issue *Issue, name, dir string) error {
+	content, err := SerializeIssue(issue)
+	if err != nil {
+		return fmt.Errorf("failed to serialize issue: %w", err)
+	}
+
+	// The filename stays the same
+	newName := path.Join(dir, path.Base(name))
+	if err := r.store.WriteFile(newName, []byte(content)); err != nil {
+		return fmt.Errorf("failed to write issue file: %w", err)
+	}
+	if newName != name {
+		if err := r.store.Remove(name); err != nil {
+			// Don't leave the issue in both directories
+			_ = r.store.Remove(newName)
+			return fmt.Errorf("failed to move issue file: %w", err)
+		}
+	}
+	issue.Path = r.store.Path(newName)
+	issue.Version = ContentVersion([]byte(content))
+
+	return nil
+}
+
+// ListIssues gets all issues from a directory
+func (r *Repository) ListIssues(dir string) ([]*Issue, error) {
+	// Read directory
+	names, err := r.store.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", r.store.Path(dir), err)
+	}
+
+	var issues []*Issue
+	for _, name := range names {
+		if !strings.HasSuffix(name, ".md") {
+			continue
+		}
+
+		// Read and parse issue
+		data, err := r.store.ReadFile(path.Join(dir, name))
+		if err != nil {
+			continue // Skip files we can't read
+		}
+
+		issue, err := ParseMarkdown(string(data))
+		if err != nil {
+			continue // Skip files we can't parse
+		}
+		issue.Path = r.store.Path(path.Join(dir, name))
+		issue.Version = ContentVersion(data)
+
+		issues = append(issues, issue)
+	}
+
+	return issues, nil
+}
+
+// FindIssueFile searches for an issue file by ID in both open/ and closed/.
+// A number shorter than a counter ID is zero-padded (7 for 007). Under the
+// hash and ulid strategies, like git with short SHAs, a unique prefix of an
+// ID also matches (a3f for a3f9c21), as it does for provisional IDs; counter
+// IDs only match in full, so 10 can't mean 100.
+// Returns the full path and the directory name (open or closed)
+func (r *Repository) FindIssueFile(id string) (string, string, error) {
+	name, dir, err := r.resolveIssue(id)
+	if err != nil {
+		return "", "", err
+	}
+	return r.store.Path(name), dir, nil
+}
+
+// resolveIssue is FindIssueFile in store-relative names
+func (r *Repository) resolveIssue(id string) (string, string, error) {
+	if name, dir, err := r.findIssue(id); err == nil {
+		return name, dir, nil
+	}
+	if n, err := strconv.Atoi(id); err == nil && n >= 0 && len(id) < len(FormatID(n)) {
+		if name, dir, err := r.findIssue(FormatID(n)); err == nil {
+			return name, dir, nil
+		}
+	}
+
+	cfg, err := r.LoadConfig()
+	if err != nil {
+		return "", "", err
+	}
+	prefixIDs := cfg.IDs.Strategy == IDHash || cfg.IDs.Strategy == IDULID
+
+	ids, err := r.issueIDs()
+	if err != nil {
+		return "", "", err
+	}
+
+	var matches []string
+	for _, candidate := range ids {
+		if !prefixIDs && !IsProvisionalID(candidate) {
+			continue
+		}
+		if id != "" && strings.HasPrefix(candidate, id) && !containsString(matches, candidate) {
+			matches = append(matches, candidate)
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return "", "", fmt.Errorf("issue %s %w", id, ErrNotFound)
+	case 1:
+		return r.findIssue(matches[0])
+	default:
+		return "", "", fmt.Errorf("issue ID %s is ambiguous (matches %s)", id, strings.Join(matches, ", "))
+	}
+}
+
+// findIssue looks up an issue by its exact ID, returning its store-relative name and directory
+func (r *Repository) findIssue(id string) (string, string, error) {
+	for _, dir := range []string{OpenDir, ClosedDir} {
+		if name, err := r.findInDirectory(dir, id); err == nil {
+			return name, dir, nil
+		}
+	}
+
+	return "", "", fmt.Errorf("issue %s %w", id, ErrNotFound)
+}
+
+// findInDirectory searches for a file matching the ID pattern in a specific directory
+func (r *Repository) findInDirectory(dir, id string) (string, error) {
+	names, err := r.store.ReadDir(dir)
+	if err != nil {
+		return "", err
+	}
+
+	pattern := fmt.Sprintf("%s-", id)
+	for _, name := range names {
+		if strings.HasPrefix(name, pattern) && strings.HasSuffix(name, ".md") {
+			return path.Join(dir, name), nil
+		}
+	}
+
+	return "", fmt.Errorf("not found")
+}
+
+// DeleteIssue removes an issue file (for cleanup/testing)
+func (r *Repository) DeleteIssue(id string) error {
+	return r.withLock(func() error {
+		return r.deleteIssue(id)
+	})
+}
+
+// deleteIssue implements DeleteIssue; the caller must hold the store lock
+func (r *Repository) deleteIssue(id string) error {
+	name, _, err := r.resolveIssue(id)
+	if err != nil {
+		return err
+	}
+
+	if err := r.store.Remove(name); err != nil {
+		return fmt.Errorf("failed to delete issue file: %w", err)
+	}
+
+	return nil
+}
+
+// LoadTemplateBody reads the template file and extracts the body content (after title heading)
+func (r *Repository) LoadTemplateBody() string {
+	// Read template file
+	data, err := r.store.ReadFile(TemplateFile)
+	if err != nil {
+		// If template doesn't exist, return empty body
+		return ""
+	}
+
+	// Parse the template to extract body
+	content := string(data)
+	parts := strings.SplitN(content, "---", 3)
+	if len(parts) < 3 {
+		// Invalid template format, return empty
+		return ""
+	}
+
+	// Get everything after frontmatter
+	body := strings.TrimSpace(parts[2])
+
+	// Skip the title line (first # heading) and get everything after
+	lines := strings.Split(body, "\n")
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "# ") {
+			// Return everything after the title line
+			if i+1 < len(lines) {
+				return strings.TrimSpace(strings.Join(lines[i+1:], "\n"))
+			}
+			return ""
+		}
+	}
+
+	// If no title found, return the whole body
+	return body
+}
+
+// NewIssue creates a new Issue with default values, the repository's template
+// body and the defaults of any custom fields declared in config.yaml
+func (r *Repository) NewIssue(id int, title, assignee string, labels []string) *Issue {
+	return r.NewIssueWithID(FormatID(id), title, assignee, labels)
+}
+
+// NewIssueWithID is NewIssue for an ID returned by AllocateID
+func (r *Repository) NewIssueWithID(id, title, assignee string, labels []string) *Issue {
+	return r.newIssue(id, title, assignee, labels)
+}
+
+// newIssue implements NewIssueWithID and NewIssueWithOptions
+func (r *Repository) newIssue(id, title, assignee string, labels []string) *Issue {
+	now := time.Now()
+
+	issue := &Issue{
+		ID:       id,
+		Assignee: assignee,
+		Labels:   labels,
+		Created:  now,
+		Updated:  now,
+		Title:    title,
+		Body:     r.LoadTemplateBody(),
+	}
+
+	// An invalid config is reported by the commands that load it explicitly
+	if cfg, err := r.LoadConfig(); err == nil {
+		cfg.ApplyDefaults(issue)
+		if cfg.Workflow.Initial != StateOpen {
+			issue.Status = cfg.Workflow.Initial
+		}
+	}
+
+	return issue
+}
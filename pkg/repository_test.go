@@ -0,0 +1,221 @@
+package pkg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestRepositories returns an initialized filesystem and in-memory repository
+// so the same behaviour can be checked against both Store implementations
+func newTestRepositories(t *testing.T) map[string]*Repository {
+	t.Helper()
+
+	repos := map[string]*Repository{
+		"fs":     OpenRepository(filepath.Join(t.TempDir(), IssuesDir)),
+		"memory": NewRepository(NewMemStore()),
+	}
+
+	for name, repo := range repos {
+		if repo.Exists() {
+			t.Fatalf("%s repository should not exist before Initialize()", name)
+		}
+		if err := repo.Initialize(); err != nil {
+			t.Fatalf("%s Initialize() error = %v", name, err)
+		}
+		if !repo.Exists() {
+			t.Fatalf("%s repository should exist after Initialize()", name)
+		}
+	}
+
+	return repos
+}
+
+func TestRepositoryLifecycle(t *testing.T) {
+	t.Parallel()
+
+	for name, repo := range newTestRepositories(t) {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			id, err := repo.GetNextID()
+			if err != nil {
+				t.Fatalf("GetNextID() error = %v", err)
+			}
+			if id != 1 {
+				t.Errorf("GetNextID() = %d, want 1", id)
+			}
+
+			issue := repo.NewIssue(id, "Repository lifecycle", "alice", []string{"bug"})
+			if issue.Body == "" {
+				t.Error("NewIssue() should use the repository template body")
+			}
+			if err := repo.SaveIssue(issue, OpenDir); err != nil {
+				t.Fatalf("SaveIssue() error = %v", err)
+			}
+
+			issues, err := repo.ListIssues(OpenDir)
+			if err != nil {
+				t.Fatalf("ListIssues() error = %v", err)
+			}
+			if len(issues) != 1 || issues[0].Title != "Repository lifecycle" {
+				t.Fatalf("ListIssues() = %+v, want the saved issue", issues)
+			}
+
+			if err := repo.MoveIssue("001", OpenDir, ClosedDir); err != nil {
+				t.Fatalf("MoveIssue() error = %v", err)
+			}
+
+			loaded, dir, err := repo.LoadIssue("001")
+			if err != nil {
+				t.Fatalf("LoadIssue() error = %v", err)
+			}
+			if dir != ClosedDir {
+				t.Errorf("LoadIssue() dir = %s, want %s", dir, ClosedDir)
+			}
+			if loaded.Assignee != "alice" || !loaded.HasLabel("bug") {
+				t.Errorf("LoadIssue() = %+v, metadata not preserved", loaded)
+			}
+
+			next, err := repo.GetNextID()
+			if err != nil {
+				t.Fatalf("GetNextID() error = %v", err)
+			}
+			if next != 2 {
+				t.Errorf("GetNextID() = %d, want 2", next)
+			}
+
+			if err := repo.DeleteIssue("001"); err != nil {
+				t.Fatalf("DeleteIssue() error = %v", err)
+			}
+			if _, _, err := repo.FindIssueFile("001"); err == nil {
+				t.Error("FindIssueFile() should fail after DeleteIssue()")
+			}
+		})
+	}
+}
+
+func TestRepositoriesAreIndependent(t *testing.T) {
+	t.Parallel()
+
+	first := NewRepository(NewMemStore())
+	second := OpenRepository(filepath.Join(t.TempDir(), IssuesDir))
+	for _, repo := range []*Repository{first, second} {
+		if err := repo.Initialize(); err != nil {
+			t.Fatalf("Initialize() error = %v", err)
+		}
+	}
+
+	if err := first.SaveIssue(&Issue{ID: "001", Title: "Only in first"}, OpenDir); err != nil {
+		t.Fatalf("SaveIssue() error = %v", err)
+	}
+
+	if _, _, err := second.LoadIssue("001"); err == nil {
+		t.Error("issue saved in one repository should not be visible in another")
+	}
+}
+
+func TestOpenRepositoryIgnoresWorkingDirectory(t *testing.T) {
+	t.Parallel()
+
+	root := filepath.Join(t.TempDir(), IssuesDir)
+	repo := OpenRepository(root)
+	if err := repo.Initialize(); err != nil {
+		t.Fatalf("Initialize() error = %v", err)
+	}
+	if err := repo.SaveIssue(&Issue{ID: "001", Title: "Explicit root"}, OpenDir); err != nil {
+		t.Fatalf("SaveIssue() error = %v", err)
+	}
+
+	path, dir, err := repo.FindIssueFile("001")
+	if err != nil {
+		t.Fatalf("FindIssueFile() error = %v", err)
+	}
+	if dir != OpenDir {
+		t.Errorf("FindIssueFile() dir = %s, want %s", dir, OpenDir)
+	}
+	if want := filepath.Join(root, OpenDir, "001-explicit-root.md"); path != want {
+		t.Errorf("FindIssueFile() path = %s, want %s", path, want)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("issue file should exist on disk: %v", err)
+	}
+}
+
+func TestMemStoreRequiresParentDirectory(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemStore()
+	if err := store.WriteFile("open/001-x.md", []byte("x")); err == nil {
+		t.Error("WriteFile() should fail when the parent directory does not exist")
+	}
+
+	if err := store.MkdirAll("open"); err != nil {
+		t.Fatalf("MkdirAll() error = %v", err)
+	}
+	if err := store.WriteFile("open/001-x.md", []byte("x")); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	names, err := store.ReadDir("open")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(names) != 1 || names[0] != "001-x.md" {
+		t.Errorf("ReadDir() = %v, want [001-x.md]", names)
+	}
+
+	if _, err := store.ReadFile("open/missing.md"); !os.IsNotExist(err) {
+		t.Errorf("ReadFile() of a missing file should report not-exist, got %v", err)
+	}
+}
+
+// closedReadOnlyStore is a MemStore that can't write to closed/
+type closedReadOnlyStore struct {
+	*MemStore
+}
+
+func (s closedReadOnlyStore) WriteFile(name string, data []byte) error {
+	if strings.HasPrefix(name, ClosedDir+"/") {
+		return errors.New("read-only")
+	}
+	return s.MemStore.WriteFile(name, data)
+}
+
+func TestFailedMoveLeavesIssue(t *testing.T) {
+	t.Parallel()
+
+	store := NewMemStore()
+	if err := NewRepository(store).Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	repo := NewRepository(closedReadOnlyStore{store})
+	if _, err := repo.CreateIssue("Issue", "", IssueOptions{Body: "Nothing to check"}); err != nil {
+		t.Fatal(err)
+	}
+	before, _, err := repo.LoadIssue("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.MoveIssue("001", OpenDir, ClosedDir); err == nil {
+		t.Error("MoveIssue() should fail when closed/ can't be written")
+	}
+	if err := repo.TransitionIssue("001", StateClosed, CloseOptions{}); err == nil {
+		t.Error("TransitionIssue() should fail when closed/ can't be written")
+	}
+	title, closed := "Renamed", StateClosed
+	if _, _, err := repo.UpdateIssue("001", IssueUpdate{Title: &title, Status: &closed}); err == nil {
+		t.Error("UpdateIssue() should fail when closed/ can't be written")
+	}
+
+	after, dir, err := repo.LoadIssue("001")
+	if err != nil {
+		t.Fatalf("LoadIssue() after the failed moves error = %v", err)
+	}
+	if dir != OpenDir || after.Version != before.Version {
+		t.Errorf("the failed moves left the issue in %s as %q, want it unchanged in open", dir, after.Title)
+	}
+}
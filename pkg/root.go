@@ -0,0 +1,122 @@
+package pkg
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// EnvIssuesDir is the environment variable that points gi at a specific .issues directory
+const EnvIssuesDir = "GI_DIR"
+
+// issuesDirOverride is set by SetIssuesDir (e.g. from the --repo flag) and wins over discovery
+var issuesDirOverride string
+
+// SetIssuesDir overrides the location of the .issues directory.
+// Passing an empty path restores the default discovery behaviour.
+func SetIssuesDir(path string) {
+	issuesDirOverride = path
+}
+
+// ResolveIssuesDir returns the .issues directory every storage function operates on.
+// Resolution order:
+//  1. an explicit override set with SetIssuesDir
+//  2. the GI_DIR environment variable
+//  3. the nearest .issues directory found walking up from the working directory
+//  4. .issues at the root of the enclosing git repository (where `gi init` will create it)
+//  5. .issues relative to the working directory
+func ResolveIssuesDir() string {
+	if issuesDirOverride != "" {
+		return issuesDirOverride
+	}
+
+	if env := os.Getenv(EnvIssuesDir); env != "" {
+		return env
+	}
+
+	cwd, err := os.Getwd()
+	if err != nil {
+		return IssuesDir
+	}
+
+	if found, err := FindIssuesDir(cwd); err == nil {
+		return relativeTo(cwd, found)
+	}
+
+	if gitRoot, err := FindGitRoot(cwd); err == nil {
+		return relativeTo(cwd, filepath.Join(gitRoot, IssuesDir))
+	}
+
+	return IssuesDir
+}
+
+// FindIssuesDir walks upward from start looking for a .issues directory.
+// The walk stops at the root of the enclosing git repository so that an
+// unrelated .issues directory further up the filesystem is never picked up.
+func FindIssuesDir(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", start, err)
+	}
+
+	for {
+		candidate := filepath.Join(dir, IssuesDir)
+		if isDirectory(candidate) {
+			return candidate, nil
+		}
+
+		// Don't escape the enclosing git repository
+		if pathExists(filepath.Join(dir, ".git")) {
+			break
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	return "", fmt.Errorf("%s directory not found in %s or any parent directory", IssuesDir, start)
+}
+
+// FindGitRoot walks upward from start looking for the top level of a git repository
+func FindGitRoot(start string) (string, error) {
+	dir, err := filepath.Abs(start)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve %s: %w", start, err)
+	}
+
+	for {
+		// .git is a directory in regular clones and a file in worktrees/submodules
+		if pathExists(filepath.Join(dir, ".git")) {
+			return dir, nil
+		}
+
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("not a git repository: %s", start)
+		}
+		dir = parent
+	}
+}
+
+// relativeTo returns target relative to base when possible, so paths shown
+// to users stay short (e.g. "../../.issues" instead of an absolute path)
+func relativeTo(base, target string) string {
+	rel, err := filepath.Rel(base, target)
+	if err != nil {
+		return target
+	}
+	return rel
+}
+
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func pathExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
@@ -0,0 +1,130 @@
+package pkg
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindIssuesDirWalksUp(t *testing.T) {
+	cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	if err := InitializeRepo(); err != nil {
+		t.Fatalf("InitializeRepo() error = %v", err)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nested := filepath.Join(root, "services", "api")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := FindIssuesDir(nested)
+	if err != nil {
+		t.Fatalf("FindIssuesDir() error = %v", err)
+	}
+	if found != filepath.Join(root, IssuesDir) {
+		t.Errorf("FindIssuesDir() = %q, want %q", found, filepath.Join(root, IssuesDir))
+	}
+}
+
+func TestFindIssuesDirStopsAtGitRoot(t *testing.T) {
+	cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	if err := InitializeRepo(); err != nil {
+		t.Fatalf("InitializeRepo() error = %v", err)
+	}
+
+	root, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A nested git repository without its own .issues must not see the outer one
+	inner := filepath.Join(root, "vendor", "lib")
+	if err := os.MkdirAll(filepath.Join(inner, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := FindIssuesDir(inner); err == nil {
+		t.Error("FindIssuesDir() should not escape the enclosing git repository")
+	}
+}
+
+func TestResolveIssuesDirFromSubdirectory(t *testing.T) {
+	cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	if err := InitializeRepo(); err != nil {
+		t.Fatalf("InitializeRepo() error = %v", err)
+	}
+
+	issue := &Issue{ID: "001", Title: "Reachable from anywhere"}
+	if err := SaveIssue(issue, OpenDir); err != nil {
+		t.Fatalf("SaveIssue() error = %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Join("services", "api"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(filepath.Join("services", "api")); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ResolveIssuesDir(); got != filepath.Join("..", "..", IssuesDir) {
+		t.Errorf("ResolveIssuesDir() = %q, want %q", got, filepath.Join("..", "..", IssuesDir))
+	}
+
+	if !RepoExists() {
+		t.Fatal("RepoExists() should find .issues in a parent directory")
+	}
+
+	loaded, _, err := LoadIssue("001")
+	if err != nil {
+		t.Fatalf("LoadIssue() from subdirectory error = %v", err)
+	}
+	if loaded.Title != issue.Title {
+		t.Errorf("Title = %q, want %q", loaded.Title, issue.Title)
+	}
+}
+
+func TestResolveIssuesDirDefaultsToGitRoot(t *testing.T) {
+	cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	if err := os.MkdirAll(".git", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll("docs", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir("docs"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ResolveIssuesDir(); got != filepath.Join("..", IssuesDir) {
+		t.Errorf("ResolveIssuesDir() = %q, want %q", got, filepath.Join("..", IssuesDir))
+	}
+}
+
+func TestResolveIssuesDirOverrides(t *testing.T) {
+	cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	t.Setenv(EnvIssuesDir, "/from/env/.issues")
+	if got := ResolveIssuesDir(); got != "/from/env/.issues" {
+		t.Errorf("ResolveIssuesDir() with %s = %q", EnvIssuesDir, got)
+	}
+
+	SetIssuesDir("/from/flag/.issues")
+	defer SetIssuesDir("")
+	if got := ResolveIssuesDir(); got != "/from/flag/.issues" {
+		t.Errorf("ResolveIssuesDir() with override = %q, want override to win over %s", got, EnvIssuesDir)
+	}
+}
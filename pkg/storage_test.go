@@ -527,3 +527,36 @@ func fileExists(path string) bool {
 	info, err := os.Stat(path)
 	return err == nil && !info.IsDir()
 }
+
+func TestMoveIssuePreservesUnknownFrontmatter(t *testing.T) {
+	cleanup := setupTestRepo(t)
+	defer cleanup()
+
+	if err := InitializeRepo(); err != nil {
+		t.Fatal(err)
+	}
+
+	content := "---\nid: \"001\"\nassignee: \"\"\npriority: high # keep me\nlabels: []\nepic: billing\ncreated: 2025-11-14T10:30:00Z\nupdated: 2025-11-14T10:30:00Z\n---\n\n# Keep my fields\n\nBody.\n"
+	path := filepath.Join(IssuesDir, OpenDir, "001-keep-my-fields.md")
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := MoveIssue("001", OpenDir, ClosedDir); err != nil {
+		t.Fatalf("MoveIssue() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(IssuesDir, ClosedDir, "001-keep-my-fields.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"priority: high # keep me\n", "epic: billing\n", "\n\n# Keep my fields\n\nBody.\n"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("moved issue lost %q:\n%s", want, data)
+		}
+	}
+	if strings.Contains(string(data), "updated: 2025-11-14T10:30:00Z") {
+		t.Errorf("updated timestamp should be refreshed:\n%s", data)
+	}
+}
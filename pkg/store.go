@@ -0,0 +1,292 @@
+package pkg
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// LockFile is the advisory lock taken while the issue store is modified
+	LockFile = ".lock"
+	// LockTimeout is how long to wait for another gi process to release the lock
+	LockTimeout = 10 * time.Second
+
+	lockRetryInterval = 10 * time.Millisecond
+)
+
+// Store abstracts the file operations a Repository performs on an issue store.
+// Names are slash-separated and relative to the store root (e.g. "open/001-fix-bug.md").
+type Store interface {
+	// ReadFile returns the contents of the named file.
+	// Missing files are reported with an error wrapping fs.ErrNotExist.
+	ReadFile(name string) ([]byte, error)
+	// WriteFile creates or replaces the named file
+	WriteFile(name string, data []byte) error
+	// ReadDir returns the names of the regular files in dir, sorted by name
+	ReadDir(dir string) ([]string, error)
+	// Rename moves a file, replacing newName if it exists
+	Rename(oldName, newName string) error
+	// Remove deletes the named file
+	Remove(name string) error
+	// MkdirAll creates dir along with any missing parents
+	MkdirAll(dir string) error
+	// Exists reports whether a file or directory with the given name exists
+	Exists(name string) bool
+	// Path returns the user-facing location of name (a filesystem path for FSStore)
+	Path(name string) string
+	// Lock takes an exclusive lock on the store, shared with other processes
+	// for FSStore. The returned function releases it.
+	Lock() (func(), error)
+}
+
+// StatStore is implemented by stores that can report file sizes and
+// modification times, letting caches skip reading files that haven't changed
+type StatStore interface {
+	Stat(name string) (fs.FileInfo, error)
+}
+
+// FSStore is a Store backed by a directory on disk
+type FSStore struct {
+	root string
+}
+
+// NewFSStore returns a Store rooted at the given directory (usually a .issues directory)
+func NewFSStore(root string) *FSStore {
+	return &FSStore{root: root}
+}
+
+// Root returns the directory the store is rooted at
+func (s *FSStore) Root() string {
+	return s.root
+}
+
+// Path returns the filesystem path of name
+func (s *FSStore) Path(name string) string {
+	if name == "" || name == "." {
+		return s.root
+	}
+	return filepath.Join(s.root, filepath.FromSlash(name))
+}
+
+// ReadFile reads the named file from disk
+func (s *FSStore) ReadFile(name string) ([]byte, error) {
+	return os.ReadFile(s.Path(name))
+}
+
+// WriteFile writes the named file atomically: data goes to a temporary file in
+// the same directory which is then renamed over the target, so readers and
+// crashes never observe a partially written file.
+func (s *FSStore) WriteFile(name string, data []byte) error {
+	target := s.Path(name)
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer func() { _ = os.Remove(tmpName) }() // no-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmpName, target)
+}
+
+// ReadDir lists the regular files in dir
+func (s *FSStore) ReadDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(s.Path(dir))
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		names = append(names, entry.Name())
+	}
+	return names, nil
+}
+
+// Rename moves a file on disk
+func (s *FSStore) Rename(oldName, newName string) error {
+	return os.Rename(s.Path(oldName), s.Path(newName))
+}
+
+// Remove deletes a file from disk
+func (s *FSStore) Remove(name string) error {
+	return os.Remove(s.Path(name))
+}
+
+// MkdirAll creates a directory on disk
+func (s *FSStore) MkdirAll(dir string) error {
+	return os.MkdirAll(s.Path(dir), 0755)
+}
+
+// Exists reports whether name exists on disk
+func (s *FSStore) Exists(name string) bool {
+	return pathExists(s.Path(name))
+}
+
+// Stat returns the file info of name on disk
+func (s *FSStore) Stat(name string) (fs.FileInfo, error) {
+	return os.Stat(s.Path(name))
+}
+
+// Lock takes an advisory lock on the .lock file in the store root
+func (s *FSStore) Lock() (func(), error) {
+	return lockFile(s.Path(LockFile), LockTimeout)
+}
+
+// MemStore is an in-memory Store, useful for tests and for embedding gi
+// without touching the filesystem. It is safe for concurrent use.
+type MemStore struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+	dirs  map[string]bool
+
+	lock sync.Mutex // held between Lock and unlock
+}
+
+// NewMemStore returns an empty in-memory Store
+func NewMemStore() *MemStore {
+	return &MemStore{
+		files: make(map[string][]byte),
+		dirs:  make(map[string]bool),
+	}
+}
+
+// Path returns name unchanged; in-memory files have no filesystem location
+func (s *MemStore) Path(name string) string {
+	return cleanName(name)
+}
+
+// ReadFile returns a copy of the named file's contents
+func (s *MemStore) ReadFile(name string) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	data, ok := s.files[cleanName(name)]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return append([]byte(nil), data...), nil
+}
+
+// WriteFile stores a copy of data under name. The parent directory must exist.
+func (s *MemStore) WriteFile(name string, data []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name = cleanName(name)
+	if !s.dirs[path.Dir(name)] {
+		return &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	s.files[name] = append([]byte(nil), data...)
+	return nil
+}
+
+// ReadDir lists the files directly inside dir
+func (s *MemStore) ReadDir(dir string) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dir = cleanName(dir)
+	if !s.dirs[dir] {
+		return nil, &fs.PathError{Op: "open", Path: dir, Err: fs.ErrNotExist}
+	}
+
+	var names []string
+	for name := range s.files {
+		if path.Dir(name) == dir {
+			names = append(names, path.Base(name))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Rename moves a file, replacing newName if it exists
+func (s *MemStore) Rename(oldName, newName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldName, newName = cleanName(oldName), cleanName(newName)
+	data, ok := s.files[oldName]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldName, Err: fs.ErrNotExist}
+	}
+	if !s.dirs[path.Dir(newName)] {
+		return &fs.PathError{Op: "rename", Path: newName, Err: fs.ErrNotExist}
+	}
+	delete(s.files, oldName)
+	s.files[newName] = data
+	return nil
+}
+
+// Remove deletes the named file
+func (s *MemStore) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name = cleanName(name)
+	if _, ok := s.files[name]; !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	delete(s.files, name)
+	return nil
+}
+
+// MkdirAll records dir and all of its parents, including the store root
+func (s *MemStore) MkdirAll(dir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for dir = cleanName(dir); dir != "."; dir = path.Dir(dir) {
+		s.dirs[dir] = true
+	}
+	s.dirs["."] = true
+	return nil
+}
+
+// Exists reports whether a file or directory with the given name exists
+func (s *MemStore) Exists(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	name = cleanName(name)
+	if _, ok := s.files[name]; ok {
+		return true
+	}
+	return s.dirs[name]
+}
+
+// Lock takes the store's exclusive lock
+func (s *MemStore) Lock() (func(), error) {
+	s.lock.Lock()
+	return s.lock.Unlock, nil
+}
+
+// cleanName normalizes a store-relative name ("./open/" -> "open")
+func cleanName(name string) string {
+	return path.Clean(strings.TrimPrefix(filepath.ToSlash(name), "/"))
+}
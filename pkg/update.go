@@ -0,0 +1,457 @@
+package pkg
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrIssueModified is wrapped by the error returned when an issue changed
+// after the version a change was based on
+var ErrIssueModified = errors.New("was modified since it was read")
+
+// ErrOpenChildren is wrapped by the error returned for closing a parent issue
+// whose children are still open
+var ErrOpenChildren = errors.New("open child issue(s)")
+
+// ErrUncheckedCriteria is wrapped by the error returned for strictly closing
+// an issue whose success criteria aren't all checked
+var ErrUncheckedCriteria = errors.New("unchecked success criteria")
+
+// ContentVersion returns the version of an issue file's content: a hash that
+// changes whenever the file does
+func ContentVersion(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:16])
+}
+
+// checkVersion checks an issue is still at version, when one is given
+func checkVersion(issue *Issue, version string) error {
+	if version != "" && issue.Version != version {
+		return fmt.Errorf("issue %s %w", issue.ID, ErrIssueModified)
+	}
+	return nil
+}
+
+// CreateIssue validates and saves a new open issue, allocating its ID the way
+// config.yaml says (branch feeds the provisional strategy). The milestone
+// must be open and the parent must exist; custom fields in opts.Fields are
+// checked against config.yaml. Nothing is allocated when validation fails.
+func (r *Repository) CreateIssue(title, branch string, opts IssueOptions) (*Issue, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, fmt.Errorf("issue title cannot be empty")
+	}
+
+	var issue *Issue
+	err := r.withLock(func() error {
+		cfg, err := r.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		if opts.Milestone != "" {
+			milestone, err := r.openMilestone(opts.Milestone)
+			if err != nil {
+				return err
+			}
+			opts.Milestone = milestone.Name
+		}
+		if opts.Parent != "" {
+			parent, _, err := r.LoadIssue(opts.Parent)
+			if err != nil {
+				return fmt.Errorf("failed to load parent issue: %w", err)
+			}
+			opts.Parent = parent.ID
+		}
+
+		// Validate before allocating so invalid input doesn't consume an ID
+		issue, err = r.NewIssueWithOptions("", title, opts)
+		if err != nil {
+			return err
+		}
+		if err := cfg.ValidateIssue(issue); err != nil {
+			return err
+		}
+
+		issue.ID, err = r.allocateID(title, branch)
+		if err != nil {
+			return fmt.Errorf("failed to get next issue ID: %w", err)
+		}
+		if err := r.saveIssue(issue, OpenDir); err != nil {
+			return fmt.Errorf("failed to save issue: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// openMilestone loads a milestone, checking it isn't closed
+func (r *Repository) openMilestone(name string) (*Milestone, error) {
+	milestone, err := r.LoadMilestone(name)
+	if err != nil {
+		return nil, err
+	}
+	if milestone.Closed() {
+		return nil, fmt.Errorf("milestone %q is closed", milestone.Name)
+	}
+	return milestone, nil
+}
+
+// IssueUpdate is a set of changes to an issue. Nil pointers leave an
+// attribute as it is; pointers to "" clear it.
+type IssueUpdate struct {
+	Title    *string
+	Body     *string
+	Assignee *string
+	// Labels replaces the labels; AddLabels and RemoveLabels are applied after it
+	Labels       *[]string
+	AddLabels    []string
+	RemoveLabels []string
+	Priority     *string
+	Due          *string // YYYY-MM-DD
+	Milestone    *string
+	Parent       *string
+	// Status moves the issue to another workflow state, as 'gi status' does
+	Status *string
+	// Close says what moving the issue to a closed state refuses
+	Close CloseOptions
+	// Fields sets custom fields to typed values (see FieldDef.Parse); a nil
+	// value removes the field
+	Fields map[string]interface{}
+	// IfVersion, when set, makes the update fail with ErrIssueModified unless
+	// the issue's Version still equals it
+	IfVersion string
+}
+
+// UpdateIssue applies changes to an issue and returns it with the directory
+// it ends up in. Everything is validated before anything is written.
+func (r *Repository) UpdateIssue(id string, u IssueUpdate) (*Issue, string, error) {
+	var issue *Issue
+	var dir string
+	err := r.withLock(func() error {
+		cfg, err := r.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		issue, dir, err = r.LoadIssue(id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(issue, u.IfVersion); err != nil {
+			return err
+		}
+
+		if u.Title != nil {
+			title := strings.TrimSpace(*u.Title)
+			if title == "" {
+				return fmt.Errorf("issue title cannot be empty")
+			}
+			issue.Title = title
+		}
+		if u.Body != nil {
+			issue.Body = *u.Body
+		}
+		if u.Assignee != nil {
+			issue.Assignee = strings.TrimSpace(*u.Assignee)
+		}
+		if u.Labels != nil {
+			issue.Labels = nil
+			for _, label := range *u.Labels {
+				issue.AddLabel(label)
+			}
+		}
+		for _, label := range u.AddLabels {
+			issue.AddLabel(label)
+		}
+		for _, label := range u.RemoveLabels {
+			issue.RemoveLabel(label)
+		}
+
+		setOrClear := func(name string, value *string) {
+			if value == nil {
+				return
+			}
+			if *value == "" {
+				issue.RemoveField(name)
+			} else {
+				issue.SetField(name, *value)
+			}
+		}
+		setOrClear(PriorityField, u.Priority)
+		if u.Due != nil && *u.Due != "" {
+			if _, err := ParseDueDate(*u.Due); err != nil {
+				return err
+			}
+		}
+		setOrClear(DueField, u.Due)
+		if u.Milestone != nil && *u.Milestone != "" && *u.Milestone != issue.Milestone() {
+			milestone, err := r.openMilestone(*u.Milestone)
+			if err != nil {
+				return err
+			}
+			u.Milestone = &milestone.Name
+		}
+		setOrClear(MilestoneField, u.Milestone)
+		for name, value := range u.Fields {
+			if value == nil {
+				issue.RemoveField(name)
+			} else {
+				issue.SetField(name, value)
+			}
+		}
+
+		if u.Parent != nil {
+			issue.Parent, err = r.checkParent(issue, strings.TrimSpace(*u.Parent))
+			if err != nil {
+				return err
+			}
+		}
+
+		if err := cfg.ValidateIssue(issue); err != nil {
+			return err
+		}
+
+		toDir := dir
+		if from := cfg.Workflow.StatusOf(issue, dir); u.Status != nil && *u.Status != from {
+			toDir, err = cfg.Workflow.DirFor(*u.Status)
+			if err != nil {
+				return err
+			}
+			if err := cfg.Workflow.checkTransition(issue.ID, from, *u.Status); err != nil {
+				return err
+			}
+			if toDir == ClosedDir && dir == OpenDir {
+				if err := r.checkClose(issue, u.Close); err != nil {
+					return err
+				}
+			}
+			setState(issue, *u.Status)
+		}
+
+		// The changes and the move are written together
+		name, _, err := r.resolveIssue(issue.ID)
+		if err != nil {
+			return err
+		}
+		issue.Updated = time.Now()
+		if err := r.writeIssue(issue, name, toDir); err != nil {
+			return err
+		}
+		dir = toDir
+		return nil
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return issue, dir, nil
+}
+
+// ReplaceIssue replaces an issue with new Markdown content, as 'gi edit' does,
+// and returns it with its directory. The content must keep the issue's ID and
+// satisfy config.yaml; when version is set the issue must still be at that
+// version.
+func (r *Repository) ReplaceIssue(id, content, version string) (*Issue, string, error) {
+	edited, err := ParseMarkdown(content)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid issue format: %w", err)
+	}
+
+	var dir string
+	err = r.withLock(func() error {
+		cfg, err := r.LoadConfig()
+		if err != nil {
+			return err
+		}
+
+		var current *Issue
+		current, dir, err = r.LoadIssue(id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(current, version); err != nil {
+			return err
+		}
+		if edited.ID != current.ID {
+			return fmt.Errorf("the ID of issue %s can't be changed to %q", current.ID, edited.ID)
+		}
+		if err := cfg.ValidateIssue(edited); err != nil {
+			return err
+		}
+
+		edited.Updated = time.Now()
+		return r.saveIssue(edited, dir)
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return edited, dir, nil
+}
+
+// CloseIssue moves an open issue to closed/ and returns it, refusing what
+// opts refuse (see CloseCheck.Check). When version is set the issue must
+// still be at that version (see Issue.Version).
+func (r *Repository) CloseIssue(id, version string, opts CloseOptions) (*Issue, error) {
+	var issue *Issue
+	err := r.withLock(func() error {
+		var dir string
+		var err error
+		issue, dir, err = r.LoadIssue(id)
+		if err != nil {
+			return err
+		}
+		if err := checkVersion(issue, version); err != nil {
+			return err
+		}
+		if dir == ClosedDir {
+			return fmt.Errorf("issue #%s is already closed", issue.ID)
+		}
+		if err := r.checkClose(issue, opts); err != nil {
+			return err
+		}
+
+		if err := r.moveIssue(issue.ID, OpenDir, ClosedDir); err != nil {
+			return fmt.Errorf("failed to move issue: %w", err)
+		}
+		issue, _, err = r.LoadIssue(issue.ID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
+
+// CloseCheck is what's left undone on an issue that's about to be closed
+type CloseCheck struct {
+	// Blockers are the IDs of the open issues blocking it
+	Blockers []string
+	// Unchecked are its unchecked success criteria, leaving out those still
+	// as the issue template has them
+	Unchecked []Task
+	// OpenChildren are the IDs of its open child issues
+	OpenChildren []string
+}
+
+// CloseOptions say what closing an issue refuses. By default a parent issue
+// with open children is refused and anything else is let through.
+type CloseOptions struct {
+	// Force closes a parent issue whose children are still open
+	Force bool
+	// Strict refuses an issue with open blockers or unchecked success criteria
+	Strict bool
+}
+
+// Check returns why issue id can't be closed under opts, or nil when it can
+func (c *CloseCheck) Check(id string, opts CloseOptions) error {
+	if opts.Strict && len(c.Blockers) > 0 {
+		return fmt.Errorf("issue #%s is blocked by open issue(s) %s", id, formatRefs(c.Blockers))
+	}
+	if opts.Strict && len(c.Unchecked) > 0 {
+		return fmt.Errorf("issue #%s has %d %w", id, len(c.Unchecked), ErrUncheckedCriteria)
+	}
+	if !opts.Force && len(c.OpenChildren) > 0 {
+		return fmt.Errorf("issue #%s has %d %w: %s", id, len(c.OpenChildren), ErrOpenChildren, formatRefs(c.OpenChildren))
+	}
+	return nil
+}
+
+// formatRefs renders issue IDs as "#001, #002"
+func formatRefs(ids []string) string {
+	refs := make([]string, len(ids))
+	for i, id := range ids {
+		refs[i] = "#" + id
+	}
+	return strings.Join(refs, ", ")
+}
+
+// CloseChecks looks at what's left undone on issue id before closing it, for
+// the caller to warn about, refuse or confirm
+func (r *Repository) CloseChecks(id string) (*CloseCheck, error) {
+	issue, _, err := r.LoadIssue(id)
+	if err != nil {
+		return nil, err
+	}
+	return r.closeChecks(issue)
+}
+
+// checkClose returns why issue can't be closed under opts, judging its
+// success criteria as they are in issue
+func (r *Repository) checkClose(issue *Issue, opts CloseOptions) error {
+	check, err := r.closeChecks(issue)
+	if err != nil {
+		return err
+	}
+	return check.Check(issue.ID, opts)
+}
+
+// closeChecks implements CloseChecks for a loaded issue
+func (r *Repository) closeChecks(issue *Issue) (*CloseCheck, error) {
+	check := &CloseCheck{}
+
+	// Criteria left as the template has them are placeholders, not work
+	// left undone
+	placeholders := map[string]bool{}
+	for _, task := range parseTasks(r.LoadTemplateBody()) {
+		if !task.Checked {
+			placeholders[task.Text] = true
+		}
+	}
+	for _, task := range issue.UncheckedCriteria() {
+		if !placeholders[task.Text] {
+			check.Unchecked = append(check.Unchecked, task)
+		}
+	}
+
+	blockers, err := r.OpenBlockers(issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check blockers: %w", err)
+	}
+	for _, blocker := range blockers {
+		check.Blockers = append(check.Blockers, blocker.ID)
+	}
+
+	children, err := r.Children(issue.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check child issues: %w", err)
+	}
+	for _, child := range children {
+		if child.Dir == OpenDir {
+			check.OpenChildren = append(check.OpenChildren, child.Issue.ID)
+		}
+	}
+
+	return check, nil
+}
+
+// CreateIssue validates and saves a new issue in the resolved .issues directory
+func CreateIssue(title, branch string, opts IssueOptions) (*Issue, error) {
+	return DefaultRepository().CreateIssue(title, branch, opts)
+}
+
+// UpdateIssue applies changes to an issue in the resolved .issues directory
+func UpdateIssue(id string, u IssueUpdate) (*Issue, string, error) {
+	return DefaultRepository().UpdateIssue(id, u)
+}
+
+// ReplaceIssue replaces an issue with new Markdown content in the resolved .issues directory
+func ReplaceIssue(id, content, version string) (*Issue, string, error) {
+	return DefaultRepository().ReplaceIssue(id, content, version)
+}
+
+// CloseIssue moves an open issue to closed/ in the resolved .issues directory
+func CloseIssue(id, version string, opts CloseOptions) (*Issue, error) {
+	return DefaultRepository().CloseIssue(id, version, opts)
+}
+
+// CloseChecks looks at what's left undone on issue id in the resolved .issues directory
+func CloseChecks(id string) (*CloseCheck, error) {
+	return DefaultRepository().CloseChecks(id)
+}
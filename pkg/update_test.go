@@ -0,0 +1,284 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestCreateIssue(t *testing.T) {
+	repo := newIDTestRepo(t, `fields:
+  - name: estimate
+    type: int
+    required: true
+`)
+
+	// Invalid input doesn't consume an ID
+	if _, err := repo.CreateIssue("No estimate", "", IssueOptions{}); err == nil || !strings.Contains(err.Error(), "estimate") {
+		t.Fatalf("CreateIssue() without a required field error = %v", err)
+	}
+	if _, err := repo.CreateIssue("Bad parent", "", IssueOptions{Parent: "999", Fields: map[string]interface{}{"estimate": 3}}); err == nil {
+		t.Fatal("CreateIssue() with a missing parent should fail")
+	}
+	if _, err := repo.CreateIssue("  ", "", IssueOptions{}); err == nil {
+		t.Fatal("CreateIssue() with an empty title should fail")
+	}
+
+	issue, err := repo.CreateIssue("Epic", "", IssueOptions{
+		Body:   "Custom body",
+		Labels: []string{"epic"},
+		Fields: map[string]interface{}{"estimate": 8},
+	})
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if issue.ID != "001" || issue.Body != "Custom body" {
+		t.Errorf("CreateIssue() = %s %q, want 001 with the given body", issue.ID, issue.Body)
+	}
+
+	child, err := repo.CreateIssue("Child", "", IssueOptions{Parent: "1", Fields: map[string]interface{}{"estimate": 1}})
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	if child.ID != "002" || child.Parent != "001" {
+		t.Errorf("CreateIssue() = %s with parent %q, want 002 with parent 001", child.ID, child.Parent)
+	}
+}
+
+func TestUpdateIssue(t *testing.T) {
+	repo := newIDTestRepo(t, `workflow:
+  states:
+    - name: review
+  transitions:
+    open: [review]
+    review: [closed]
+`)
+	createWithID(t, repo, "Fix login", "")
+	createWithID(t, repo, "Epic", "")
+
+	strPtr := func(s string) *string { return &s }
+
+	issue, dir, err := repo.UpdateIssue("001", IssueUpdate{
+		Title:     strPtr("Fix login timeout"),
+		Assignee:  strPtr("mina"),
+		AddLabels: []string{"bug", "backend"},
+		Priority:  strPtr("high"),
+		Due:       strPtr("2026-11-01"),
+		Parent:    strPtr("002"),
+		Status:    strPtr("review"),
+	})
+	if err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if issue.Title != "Fix login timeout" || issue.Assignee != "mina" || strings.Join(issue.Labels, ",") != "bug,backend" ||
+		issue.Priority() != "high" || issue.Parent != "002" || issue.Status != "review" || dir != OpenDir {
+		t.Errorf("UpdateIssue() = %+v in %s", issue, dir)
+	}
+
+	// Nil leaves attributes alone; "" clears them
+	issue, _, err = repo.UpdateIssue("001", IssueUpdate{Priority: strPtr(""), RemoveLabels: []string{"bug"}})
+	if err != nil {
+		t.Fatalf("UpdateIssue() error = %v", err)
+	}
+	if _, ok := issue.Field(PriorityField); ok || issue.Assignee != "mina" || strings.Join(issue.Labels, ",") != "backend" {
+		t.Errorf("UpdateIssue() = %+v", issue)
+	}
+
+	// Nothing is written when a change is invalid
+	tests := []IssueUpdate{
+		{Title: strPtr("Renamed"), Priority: strPtr("urgent")},
+		{Title: strPtr("Renamed"), Due: strPtr("tomorrow")},
+		{Title: strPtr("Renamed"), Status: strPtr("open")}, // review -> open isn't allowed
+		{Title: strPtr("Renamed"), Milestone: strPtr("v9")},
+	}
+	for _, u := range tests {
+		if _, _, err := repo.UpdateIssue("001", u); err == nil {
+			t.Errorf("UpdateIssue(%+v) should fail", u)
+		}
+	}
+	if issue, _, _ := repo.LoadIssue("001"); issue.Title != "Fix login timeout" {
+		t.Errorf("a failed update changed the title to %q", issue.Title)
+	}
+
+	// Parents can't form a cycle
+	if _, _, err := repo.UpdateIssue("002", IssueUpdate{Parent: strPtr("001")}); err == nil {
+		t.Error("UpdateIssue() should refuse a parent cycle")
+	}
+
+	// Moving to a closed state moves the file
+	if _, dir, err := repo.UpdateIssue("001", IssueUpdate{Status: strPtr("closed")}); err != nil || dir != ClosedDir {
+		t.Errorf("UpdateIssue(status closed) = %s, %v; want closed", dir, err)
+	}
+}
+
+func TestIssueVersion(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	created := createWithID(t, repo, "Fix login", "")
+
+	issue, _, err := repo.LoadIssue("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if issue.Version == "" || issue.Version != created.Version {
+		t.Fatalf("LoadIssue() version = %q, want the saved version %q", issue.Version, created.Version)
+	}
+	stale := issue.Version
+
+	strPtr := func(s string) *string { return &s }
+	updated, _, err := repo.UpdateIssue("001", IssueUpdate{Assignee: strPtr("mina"), IfVersion: stale})
+	if err != nil {
+		t.Fatalf("UpdateIssue() at the current version error = %v", err)
+	}
+	if updated.Version == stale {
+		t.Error("UpdateIssue() didn't change the version")
+	}
+
+	if _, _, err := repo.UpdateIssue("001", IssueUpdate{Assignee: strPtr("joe"), IfVersion: stale}); !errors.Is(err, ErrIssueModified) {
+		t.Errorf("UpdateIssue() at a stale version error = %v, want ErrIssueModified", err)
+	}
+	if _, err := repo.CloseIssue("001", stale, CloseOptions{}); !errors.Is(err, ErrIssueModified) {
+		t.Errorf("CloseIssue() at a stale version error = %v, want ErrIssueModified", err)
+	}
+	if _, err := repo.CloseIssue("001", updated.Version, CloseOptions{}); err != nil {
+		t.Errorf("CloseIssue() at the current version error = %v", err)
+	}
+	if _, _, err := repo.LoadIssue("999"); !errors.Is(err, ErrNotFound) || err.Error() != "issue 999 not found" {
+		t.Errorf("LoadIssue(999) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestReplaceIssue(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	createWithID(t, repo, "Fix login", "")
+
+	issue, _, err := repo.LoadIssue("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	content, err := SerializeIssue(issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	edited := strings.Replace(content, "# Fix login", "# Fix login timeout", 1)
+	replaced, dir, err := repo.ReplaceIssue("001", edited, issue.Version)
+	if err != nil {
+		t.Fatalf("ReplaceIssue() error = %v", err)
+	}
+	if replaced.Title != "Fix login timeout" || dir != OpenDir || !replaced.Updated.After(issue.Updated) {
+		t.Errorf("ReplaceIssue() = %q in %s, updated %v", replaced.Title, dir, replaced.Updated)
+	}
+
+	tests := []struct {
+		name, content, version string
+	}{
+		{"stale version", edited, issue.Version},
+		{"changed ID", strings.Replace(edited, `id: "001"`, `id: "002"`, 1), ""},
+		{"invalid priority", strings.Replace(edited, "labels: []", "labels: []\npriority: urgent", 1), ""},
+		{"no title", "---\nid: \"001\"\n---\n", ""},
+	}
+	for _, tt := range tests {
+		if _, _, err := repo.ReplaceIssue("001", tt.content, tt.version); err == nil {
+			t.Errorf("ReplaceIssue() with a %s should fail", tt.name)
+		}
+	}
+}
+
+func TestCloseChecks(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, opts := range []IssueOptions{
+		{Body: "## Success Criteria\n\n- [x] Shipped\n- [ ] Documented\n"},
+		{Body: "Nothing to check"},
+		{Parent: "001"},
+		{Parent: "001"},
+	} {
+		if _, err := repo.CreateIssue("Issue", "", opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := repo.Link("002", LinkBlocks, "001"); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.MoveIssue("004", OpenDir, ClosedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	check, err := repo.CloseChecks("001")
+	if err != nil {
+		t.Fatalf("CloseChecks() error = %v", err)
+	}
+	if strings.Join(check.Blockers, ",") != "002" || strings.Join(check.OpenChildren, ",") != "003" {
+		t.Errorf("CloseChecks() blockers %v, open children %v, want 002 and 003", check.Blockers, check.OpenChildren)
+	}
+	if len(check.Unchecked) != 1 || check.Unchecked[0].Text != "Documented" {
+		t.Errorf("CloseChecks() unchecked = %+v", check.Unchecked)
+	}
+
+	check, err = repo.CloseChecks("002")
+	if err != nil {
+		t.Fatalf("CloseChecks() error = %v", err)
+	}
+	if len(check.Blockers) != 0 || len(check.Unchecked) != 0 || len(check.OpenChildren) != 0 {
+		t.Errorf("CloseChecks() of an unhindered issue = %+v", check)
+	}
+}
+
+func TestCloseChecksIgnoreTemplatePlaceholders(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, opts := range []IssueOptions{
+		{},
+		{Body: "## Success Criteria\n\n- [x] Criterion 1\n- [ ] Criterion 2\n- [ ] Documented\n"},
+	} {
+		if _, err := repo.CreateIssue("Issue", "", opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A new issue only has the template's placeholders
+	check, err := repo.CloseChecks("001")
+	if err != nil {
+		t.Fatalf("CloseChecks() error = %v", err)
+	}
+	if len(check.Unchecked) != 0 {
+		t.Errorf("CloseChecks() of a new issue unchecked = %+v, want none", check.Unchecked)
+	}
+
+	check, err = repo.CloseChecks("002")
+	if err != nil {
+		t.Fatalf("CloseChecks() error = %v", err)
+	}
+	if len(check.Unchecked) != 1 || check.Unchecked[0].Text != "Documented" {
+		t.Errorf("CloseChecks() unchecked = %+v, want only Documented", check.Unchecked)
+	}
+}
+
+func TestClosingTransitionsAreChecked(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	for _, opts := range []IssueOptions{
+		{Body: "## Success Criteria\n\n- [ ] Documented\n"},
+		{Body: "Nothing to check", Parent: "001"},
+	} {
+		if _, err := repo.CreateIssue("Issue", "", opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A move to a closed state is checked as closing is
+	closed := StateClosed
+	if err := repo.TransitionIssue("001", StateClosed, CloseOptions{}); !errors.Is(err, ErrOpenChildren) {
+		t.Errorf("TransitionIssue(closed) error = %v, want ErrOpenChildren", err)
+	}
+	if _, _, err := repo.UpdateIssue("001", IssueUpdate{Status: &closed}); !errors.Is(err, ErrOpenChildren) {
+		t.Errorf("UpdateIssue(status closed) error = %v, want ErrOpenChildren", err)
+	}
+	if err := repo.TransitionIssue("001", StateClosed, CloseOptions{Force: true, Strict: true}); !errors.Is(err, ErrUncheckedCriteria) {
+		t.Errorf("TransitionIssue(closed, strict) error = %v, want ErrUncheckedCriteria", err)
+	}
+	if _, dir, _ := repo.LoadIssue("001"); dir != OpenDir {
+		t.Fatalf("a refused transition moved the issue to %s", dir)
+	}
+
+	if _, dir, err := repo.UpdateIssue("001", IssueUpdate{Status: &closed, Close: CloseOptions{Force: true}}); err != nil || dir != ClosedDir {
+		t.Errorf("UpdateIssue(status closed, force) = %s, %v", dir, err)
+	}
+}
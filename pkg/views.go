@@ -0,0 +1,334 @@
+package pkg
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Scopes of saved views
+const (
+	// ViewShared views live in .issues/config.yaml and are shared through git
+	ViewShared = "shared"
+	// ViewPersonal views live in the user config file
+	ViewPersonal = "personal"
+)
+
+// View is a saved set of list options, run with 'gi list --view <name>'
+type View struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
+	Query       string `yaml:"query,omitempty"`   // as for list --query
+	Sort        string `yaml:"sort,omitempty"`    // as for list --sort
+	Columns     string `yaml:"columns,omitempty"` // as for list --columns
+	All         bool   `yaml:"all,omitempty"`     // include closed issues
+	// Scope is where the view is stored (ViewShared or ViewPersonal)
+	Scope string `yaml:"-"`
+}
+
+// ErrViewExists is returned when saving a view under a name that's taken
+var ErrViewExists = errors.New("view already exists")
+
+// viewNameRe matches valid view names
+var viewNameRe = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+
+// validateViews checks view names are valid and unique
+func validateViews(views []View) error {
+	seen := make(map[string]bool)
+	for _, view := range views {
+		if !viewNameRe.MatchString(view.Name) {
+			return fmt.Errorf("invalid view name %q (use letters, digits, '.', '_' and '-')", view.Name)
+		}
+		if seen[view.Name] {
+			return fmt.Errorf("view %q is defined more than once", view.Name)
+		}
+		seen[view.Name] = true
+	}
+	return nil
+}
+
+// UserConfig is the personal configuration in the user's config directory
+// ($XDG_CONFIG_HOME/gi/config.yaml on Linux)
+type UserConfig struct {
+	Views []View `yaml:"views,omitempty"`
+}
+
+// UserConfigPath returns the path of the user config file
+func UserConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find the user config directory: %w", err)
+	}
+	return filepath.Join(dir, "gi", ConfigFile), nil
+}
+
+// LoadUserConfig reads the user config file. A missing file yields an empty config.
+func LoadUserConfig() (*UserConfig, error) {
+	path, err := UserConfigPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &UserConfig{}, nil
+		}
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	var cfg UserConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	if err := validateViews(cfg.Views); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Views returns the shared and personal views, sorted by name. A personal
+// view hides a shared view with the same name.
+func (r *Repository) Views() ([]View, error) {
+	cfg, err := r.LoadConfig()
+	if err != nil {
+		return nil, err
+	}
+	user, err := LoadUserConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]View)
+	for _, view := range cfg.Views {
+		view.Scope = ViewShared
+		byName[view.Name] = view
+	}
+	for _, view := range user.Views {
+		view.Scope = ViewPersonal
+		byName[view.Name] = view
+	}
+
+	views := make([]View, 0, len(byName))
+	for _, view := range byName {
+		views = append(views, view)
+	}
+	sort.Slice(views, func(i, j int) bool { return views[i].Name < views[j].Name })
+	return views, nil
+}
+
+// FindView returns the view with the given name, personal views first
+func (r *Repository) FindView(name string) (*View, error) {
+	views, err := r.Views()
+	if err != nil {
+		return nil, err
+	}
+	for i := range views {
+		if views[i].Name == name {
+			return &views[i], nil
+		}
+	}
+	return nil, fmt.Errorf("view %q not found (see 'gi views')", name)
+}
+
+// SaveView adds a view to config.yaml, or replaces the view with the same
+// name when replace is set. The rest of config.yaml is left as it is.
+func (r *Repository) SaveView(view View, replace bool) error {
+	return r.withLock(func() error {
+		cfg, err := r.LoadConfig()
+		if err != nil {
+			return err
+		}
+		views, err := putView(cfg.Views, view, replace)
+		if err != nil {
+			return err
+		}
+		return r.writeConfigViews(views)
+	})
+}
+
+// DeleteView removes a view from config.yaml
+func (r *Repository) DeleteView(name string) error {
+	return r.withLock(func() error {
+		cfg, err := r.LoadConfig()
+		if err != nil {
+			return err
+		}
+		views, err := removeView(cfg.Views, name)
+		if err != nil {
+			return err
+		}
+		return r.writeConfigViews(views)
+	})
+}
+
+// writeConfigViews replaces the views of config.yaml; the caller must hold the store lock
+func (r *Repository) writeConfigViews(views []View) error {
+	data, err := r.store.ReadFile(ConfigFile)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", ConfigFile, err)
+	}
+	data, err = setYAMLKey(data, "views", views)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", ConfigFile, err)
+	}
+	if err := r.store.WriteFile(ConfigFile, data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", ConfigFile, err)
+	}
+	return nil
+}
+
+// SaveUserView adds a view to the user config file, or replaces the view
+// with the same name when replace is set
+func SaveUserView(view View, replace bool) error {
+	return updateUserViews(func(views []View) ([]View, error) {
+		return putView(views, view, replace)
+	})
+}
+
+// DeleteUserView removes a view from the user config file
+func DeleteUserView(name string) error {
+	return updateUserViews(func(views []View) ([]View, error) {
+		return removeView(views, name)
+	})
+}
+
+func updateUserViews(update func([]View) ([]View, error)) error {
+	path, err := UserConfigPath()
+	if err != nil {
+		return err
+	}
+	cfg, err := LoadUserConfig()
+	if err != nil {
+		return err
+	}
+	views, err := update(cfg.Views)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	data, err = setYAMLKey(data, "views", views)
+	if err != nil {
+		return fmt.Errorf("failed to update %s: %w", path, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
+
+// putView adds or replaces a view in a list of views
+func putView(views []View, view View, replace bool) ([]View, error) {
+	view.Scope = ""
+	if err := validateViews([]View{view}); err != nil {
+		return nil, err
+	}
+	for i := range views {
+		if views[i].Name == view.Name {
+			if !replace {
+				return nil, fmt.Errorf("%w: %s", ErrViewExists, view.Name)
+			}
+			views[i] = view
+			return views, nil
+		}
+	}
+	return append(views, view), nil
+}
+
+// removeView removes a view from a list of views
+func removeView(views []View, name string) ([]View, error) {
+	for i := range views {
+		if views[i].Name == name {
+			return append(views[:i], views[i+1:]...), nil
+		}
+	}
+	return nil, fmt.Errorf("view %q not found", name)
+}
+
+// setYAMLKey sets a top-level key of a YAML document, removing it when value
+// is an empty slice. Other keys and comments are kept; a document left with
+// no keys becomes empty.
+func setYAMLKey(data []byte, key string, value interface{}) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	mapping := doc.Content[0]
+	if mapping.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("top level is not a mapping")
+	}
+
+	var valueNode yaml.Node
+	if err := valueNode.Encode(value); err != nil {
+		return nil, err
+	}
+	remove := valueNode.Kind == yaml.SequenceNode && len(valueNode.Content) == 0
+
+	found := false
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value != key {
+			continue
+		}
+		found = true
+		if remove {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+		} else {
+			mapping.Content[i+1] = &valueNode
+		}
+		break
+	}
+	if !found && !remove {
+		mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, &valueNode)
+	}
+
+	if len(mapping.Content) == 0 {
+		return nil, nil
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Views returns the shared and personal views of the resolved .issues directory
+func Views() ([]View, error) {
+	return DefaultRepository().Views()
+}
+
+// FindView returns a view of the resolved .issues directory by name
+func FindView(name string) (*View, error) {
+	return DefaultRepository().FindView(name)
+}
+
+// SaveView adds a shared view to the resolved .issues directory
+func SaveView(view View, replace bool) error {
+	return DefaultRepository().SaveView(view, replace)
+}
+
+// DeleteView removes a shared view from the resolved .issues directory
+func DeleteView(name string) error {
+	return DefaultRepository().DeleteView(name)
+}
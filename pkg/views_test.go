@@ -0,0 +1,85 @@
+package pkg
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSaveViewKeepsConfig(t *testing.T) {
+	config := "# Shared settings\npriorities: [p0, p1] # highest first\n"
+	repo := newIDTestRepo(t, config)
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if err := repo.SaveView(View{Name: "my-bugs", Query: "label:bug", Sort: "priority"}, false); err != nil {
+		t.Fatalf("SaveView() error = %v", err)
+	}
+	err := repo.SaveView(View{Name: "my-bugs", Query: "label:ui"}, false)
+	if !errors.Is(err, ErrViewExists) {
+		t.Errorf("SaveView() of an existing view error = %v, want ErrViewExists", err)
+	}
+	if err := repo.SaveView(View{Name: "my bugs"}, false); err == nil {
+		t.Error("SaveView() should reject a name with a space")
+	}
+	if err := repo.SaveView(View{Name: "my-bugs", Query: "label:ui"}, true); err != nil {
+		t.Fatalf("SaveView() with replace error = %v", err)
+	}
+
+	data, _ := repo.Store().ReadFile(ConfigFile)
+	for _, want := range []string{"# Shared settings", "# highest first", "name: my-bugs", "query: label:ui"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config.yaml lacks %q:\n%s", want, data)
+		}
+	}
+	cfg, err := repo.LoadConfig()
+	if err != nil || len(cfg.Views) != 1 || len(cfg.Priorities) != 2 {
+		t.Fatalf("LoadConfig() = %+v, %v", cfg, err)
+	}
+
+	if err := repo.DeleteView("my-bugs"); err != nil {
+		t.Fatalf("DeleteView() error = %v", err)
+	}
+	if err := repo.DeleteView("my-bugs"); err == nil {
+		t.Error("DeleteView() of a missing view should fail")
+	}
+	data, _ = repo.Store().ReadFile(ConfigFile)
+	if strings.Contains(string(data), "views") || !strings.Contains(string(data), "priorities") {
+		t.Errorf("config.yaml after DeleteView() =\n%s", data)
+	}
+}
+
+func TestPersonalViews(t *testing.T) {
+	repo := newIDTestRepo(t, "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+
+	if err := repo.SaveView(View{Name: "triage", Query: "label:triage"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.SaveView(View{Name: "bugs", Query: "label:bug"}, false); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveUserView(View{Name: "bugs", Query: "label:bug assignee:mina"}, false); err != nil {
+		t.Fatalf("SaveUserView() error = %v", err)
+	}
+
+	views, err := repo.Views()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(views) != 2 || views[0].Name != "bugs" || views[0].Scope != ViewPersonal || views[1].Scope != ViewShared {
+		t.Errorf("Views() = %+v, want personal bugs and shared triage", views)
+	}
+	if view, err := repo.FindView("bugs"); err != nil || view.Query != "label:bug assignee:mina" {
+		t.Errorf("FindView() = %+v, %v, want the personal view", view, err)
+	}
+
+	if err := DeleteUserView("bugs"); err != nil {
+		t.Fatal(err)
+	}
+	if view, _ := repo.FindView("bugs"); view == nil || view.Scope != ViewShared {
+		t.Errorf("FindView() after DeleteUserView() = %+v, want the shared view", view)
+	}
+	if _, err := repo.FindView("nope"); err == nil {
+		t.Error("FindView() of a missing view should fail")
+	}
+}
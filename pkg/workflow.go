@@ -0,0 +1,237 @@
+package pkg
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Built-in states, one per issue directory. They always exist, even without a workflow in config.yaml.
+const (
+	StateOpen   = "open"
+	StateClosed = "closed"
+)
+
+// Workflow declares the states an issue can be in and the allowed transitions between them
+type Workflow struct {
+	States []State `yaml:"states,omitempty"`
+	// Transitions maps a state to the states it may move to. When empty, any transition is allowed.
+	Transitions map[string][]string `yaml:"transitions,omitempty"`
+	// Initial is the state new issues start in (defaults to open)
+	Initial string `yaml:"initial,omitempty"`
+}
+
+// State is a workflow state. Open states are stored in open/, closed states in closed/.
+type State struct {
+	Name        string `yaml:"name"`
+	Closed      bool   `yaml:"closed,omitempty"`
+	Description string `yaml:"description,omitempty"`
+}
+
+// Validate checks that states are unique and transitions only reference known states
+func (w *Workflow) Validate() error {
+	seen := map[string]bool{StateOpen: true, StateClosed: true}
+	for _, state := range w.States {
+		if state.Name == "" {
+			return fmt.Errorf("workflow state has no name")
+		}
+		if state.Name == StateOpen || state.Name == StateClosed {
+			return fmt.Errorf("workflow state %q is built-in and can't be redeclared", state.Name)
+		}
+		if seen[state.Name] {
+			return fmt.Errorf("workflow state %q is declared more than once", state.Name)
+		}
+		if strings.ContainsAny(state.Name, " \t") {
+			return fmt.Errorf("workflow state %q can't contain whitespace", state.Name)
+		}
+		seen[state.Name] = true
+	}
+
+	for from, targets := range w.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("transition from unknown state %q", from)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("transition from %q to unknown state %q", from, to)
+			}
+		}
+	}
+
+	if w.Initial != "" {
+		state := w.State(w.Initial)
+		if state == nil {
+			return fmt.Errorf("initial state %q is not declared", w.Initial)
+		}
+		if state.Closed {
+			return fmt.Errorf("initial state %q must be an open state", w.Initial)
+		}
+	}
+
+	return nil
+}
+
+// State returns the named state (including the built-in open and closed), or nil if unknown
+func (w *Workflow) State(name string) *State {
+	switch name {
+	case StateOpen:
+		return &State{Name: StateOpen}
+	case StateClosed:
+		return &State{Name: StateClosed, Closed: true}
+	}
+	for i := range w.States {
+		if w.States[i].Name == name {
+			return &w.States[i]
+		}
+	}
+	return nil
+}
+
+// StateNames lists every state, built-in ones first
+func (w *Workflow) StateNames() []string {
+	names := []string{StateOpen, StateClosed}
+	for _, state := range w.States {
+		names = append(names, state.Name)
+	}
+	return names
+}
+
+// DirFor returns the directory (open or closed) issues in the given state are stored in
+func (w *Workflow) DirFor(state string) (string, error) {
+	s := w.State(state)
+	if s == nil {
+		return "", fmt.Errorf("unknown state %q (valid states: %s)", state, strings.Join(w.StateNames(), ", "))
+	}
+	if s.Closed {
+		return ClosedDir, nil
+	}
+	return OpenDir, nil
+}
+
+// StatusOf returns the state of an issue stored in dir. The frontmatter status
+// is used when it names a known state belonging to that directory; otherwise
+// the directory name (open or closed) is the status.
+func (w *Workflow) StatusOf(issue *Issue, dir string) string {
+	if issue.Status != "" {
+		if stateDir, err := w.DirFor(issue.Status); err == nil && stateDir == dir {
+			return issue.Status
+		}
+	}
+	if dir == ClosedDir {
+		return StateClosed
+	}
+	return StateOpen
+}
+
+// AllowedTransitions lists the states an issue in state from may move to
+func (w *Workflow) AllowedTransitions(from string) []string {
+	if len(w.Transitions) == 0 {
+		var targets []string
+		for _, name := range w.StateNames() {
+			if name != from {
+				targets = append(targets, name)
+			}
+		}
+		return targets
+	}
+
+	targets := append([]string(nil), w.Transitions[from]...)
+	sort.Strings(targets)
+	return targets
+}
+
+// CanTransition reports whether the workflow allows moving from one state to another
+func (w *Workflow) CanTransition(from, to string) bool {
+	for _, target := range w.AllowedTransitions(from) {
+		if target == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransition returns an error naming the allowed states when issue id
+// may not move from one state to another
+func (w *Workflow) checkTransition(id, from, to string) error {
+	if w.CanTransition(from, to) {
+		return nil
+	}
+	allowed := w.AllowedTransitions(from)
+	if len(allowed) == 0 {
+		return fmt.Errorf("issue %s can't leave state %s", id, from)
+	}
+	return fmt.Errorf("can't move issue %s from %s to %s (allowed: %s)", id, from, to, strings.Join(allowed, ", "))
+}
+
+// TransitionIssue moves an issue to a new workflow state, enforcing the
+// transitions declared in config.yaml. The file moves between open/ and
+// closed/ when the new state belongs to the other directory; moving an open
+// issue to a closed state is checked as closing it is (see CloseCheck.Check).
+func (r *Repository) TransitionIssue(id, to string, opts CloseOptions) error {
+	return r.withLock(func() error {
+		return r.transitionIssue(id, to, opts)
+	})
+}
+
+// transitionIssue implements TransitionIssue; the caller must hold the store lock
+func (r *Repository) transitionIssue(id, to string, opts CloseOptions) error {
+	cfg, err := r.LoadConfig()
+	if err != nil {
+		return err
+	}
+	workflow := &cfg.Workflow
+
+	toDir, err := workflow.DirFor(to)
+	if err != nil {
+		return err
+	}
+
+	name, dir, err := r.resolveIssue(id)
+	if err != nil {
+		return err
+	}
+
+	data, err := r.store.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("failed to read issue file: %w", err)
+	}
+
+	issue, err := ParseMarkdown(string(data))
+	if err != nil {
+		return fmt.Errorf("failed to parse issue: %w", err)
+	}
+
+	from := workflow.StatusOf(issue, dir)
+	if from == to {
+		return fmt.Errorf("issue %s is already %s", id, to)
+	}
+	if err := workflow.checkTransition(id, from, to); err != nil {
+		return err
+	}
+	if toDir == ClosedDir && dir == OpenDir {
+		if err := r.checkClose(issue, opts); err != nil {
+			return err
+		}
+	}
+
+	setState(issue, to)
+	issue.Updated = time.Now()
+
+	return r.writeIssue(issue, name, toDir)
+}
+
+// setState records workflow state on issue. The built-in states are implied
+// by the directory and aren't written to frontmatter.
+func setState(issue *Issue, state string) {
+	if state == StateOpen || state == StateClosed {
+		issue.Status = ""
+	} else {
+		issue.Status = state
+	}
+}
+
+// TransitionIssue moves an issue to a new workflow state in the resolved .issues directory
+func TransitionIssue(id, to string, opts CloseOptions) error {
+	return DefaultRepository().TransitionIssue(id, to, opts)
+}
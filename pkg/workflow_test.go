@@ -0,0 +1,204 @@
+package pkg
+
+import (
+	"strings"
+	"testing"
+)
+
+const testWorkflowConfig = `workflow:
+  initial: triage
+  states:
+    - name: triage
+    - name: in-progress
+    - name: review
+    - name: blocked
+    - name: done
+      closed: true
+    - name: wontfix
+      closed: true
+  transitions:
+    triage: [in-progress, wontfix]
+    in-progress: [review, blocked]
+    blocked: [in-progress]
+    review: [in-progress, done]
+    done: [open]
+    open: [in-progress, closed]
+    closed: [open]
+`
+
+func newWorkflowTestRepo(t *testing.T) *Repository {
+	t.Helper()
+
+	repo := NewRepository(NewMemStore())
+	if err := repo.Initialize(); err != nil {
+		t.Fatal(err)
+	}
+	if err := repo.Store().WriteFile(ConfigFile, []byte(testWorkflowConfig)); err != nil {
+		t.Fatal(err)
+	}
+	return repo
+}
+
+func TestWorkflowValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		wantErr string
+	}{
+		{"redeclared builtin", "workflow:\n  states:\n    - name: open\n", "built-in"},
+		{"duplicate", "workflow:\n  states:\n    - name: a\n    - name: a\n", "more than once"},
+		{"unknown source", "workflow:\n  transitions:\n    nope: [open]\n", "unknown state"},
+		{"unknown target", "workflow:\n  transitions:\n    open: [nope]\n", "unknown state"},
+		{"closed initial", "workflow:\n  initial: closed\n", "open state"},
+		{"undeclared initial", "workflow:\n  initial: triage\n", "not declared"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseConfig([]byte(tt.config))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseConfig() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestWorkflowStatusOf(t *testing.T) {
+	cfg, err := ParseConfig([]byte(testWorkflowConfig))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := &cfg.Workflow
+
+	tests := []struct {
+		status string
+		dir    string
+		want   string
+	}{
+		{"", OpenDir, StateOpen},
+		{"", ClosedDir, StateClosed},
+		{"in-progress", OpenDir, "in-progress"},
+		{"done", ClosedDir, "done"},
+		{"done", OpenDir, StateOpen},            // status disagrees with directory
+		{"unknown", OpenDir, StateOpen},         // undeclared state
+		{"in-progress", ClosedDir, StateClosed}, // open state in closed/
+	}
+
+	for _, tt := range tests {
+		issue := &Issue{Status: tt.status}
+		if got := w.StatusOf(issue, tt.dir); got != tt.want {
+			t.Errorf("StatusOf(%q in %s) = %q, want %q", tt.status, tt.dir, got, tt.want)
+		}
+	}
+}
+
+func TestTransitionIssue(t *testing.T) {
+	repo := newWorkflowTestRepo(t)
+
+	issue := repo.NewIssue(1, "Workflow", "", nil)
+	if issue.Status != "triage" {
+		t.Fatalf("NewIssue() status = %q, want initial state triage", issue.Status)
+	}
+	if err := repo.SaveIssue(issue, OpenDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.TransitionIssue("001", "review", CloseOptions{}); err == nil || !strings.Contains(err.Error(), "allowed: in-progress, wontfix") {
+		t.Errorf("TransitionIssue(triage -> review) error = %v, want disallowed", err)
+	}
+	if err := repo.TransitionIssue("001", "nope", CloseOptions{}); err == nil || !strings.Contains(err.Error(), "unknown state") {
+		t.Errorf("TransitionIssue(unknown) error = %v", err)
+	}
+
+	for _, step := range []struct{ state, dir string }{
+		{"in-progress", OpenDir},
+		{"review", OpenDir},
+		{"done", ClosedDir},
+		{"open", OpenDir},
+	} {
+		if err := repo.TransitionIssue("001", step.state, CloseOptions{}); err != nil {
+			t.Fatalf("TransitionIssue(%s) error = %v", step.state, err)
+		}
+		loaded, dir, err := repo.LoadIssue("001")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if dir != step.dir {
+			t.Errorf("after %s issue is in %s, want %s", step.state, dir, step.dir)
+		}
+		if got := cfgStatus(t, repo, loaded, dir); got != step.state {
+			t.Errorf("after %s status = %q", step.state, got)
+		}
+	}
+
+	loaded, _, err := repo.LoadIssue("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Status != "" {
+		t.Errorf("built-in open state should not be written to frontmatter, got %q", loaded.Status)
+	}
+}
+
+func TestMoveIssueClearsStaleStatus(t *testing.T) {
+	repo := newWorkflowTestRepo(t)
+
+	issue := repo.NewIssue(1, "Reopen when done", "", nil)
+	issue.Status = "done"
+	if err := repo.SaveIssue(issue, ClosedDir); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := repo.MoveIssue("001", ClosedDir, OpenDir); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, dir, err := repo.LoadIssue("001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Status != "" || cfgStatus(t, repo, loaded, dir) != StateOpen {
+		t.Errorf("status after reopen = %q, want open", loaded.Status)
+	}
+}
+
+func TestMoveIssueEnforcesTransitions(t *testing.T) {
+	repo := newWorkflowTestRepo(t)
+
+	issue := repo.NewIssue(1, "Close while in progress", "", nil)
+	issue.Status = "in-progress"
+	if err := repo.SaveIssue(issue, OpenDir); err != nil {
+		t.Fatal(err)
+	}
+
+	// in-progress has no transition to closed
+	err := repo.MoveIssue("001", OpenDir, ClosedDir)
+	if err == nil || !strings.Contains(err.Error(), "from in-progress to closed (allowed: blocked, review)") {
+		t.Fatalf("MoveIssue() error = %v, want a transition error", err)
+	}
+	if _, err := repo.CloseIssue("001", "", CloseOptions{}); err == nil {
+		t.Error("CloseIssue() should enforce the transitions too")
+	}
+	if loaded, dir, _ := repo.LoadIssue("001"); dir != OpenDir || loaded.Status != "in-progress" {
+		t.Errorf("a refused move changed the issue: %q in %s", loaded.Status, dir)
+	}
+
+	// open may be closed
+	issue = repo.NewIssue(2, "Close from open", "", nil)
+	issue.Status = ""
+	if err := repo.SaveIssue(issue, OpenDir); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := repo.CloseIssue("002", "", CloseOptions{}); err != nil {
+		t.Errorf("CloseIssue() error = %v", err)
+	}
+}
+
+func cfgStatus(t *testing.T, repo *Repository, issue *Issue, dir string) string {
+	t.Helper()
+	cfg, err := repo.LoadConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cfg.Workflow.StatusOf(issue, dir)
+}